@@ -0,0 +1,306 @@
+package dbr
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/jiyeyuran/dbr/v2/dialect"
+)
+
+// Array builds an array literal, `[...]` in bigquery and `ARRAY[...]` in postgres.
+func Array(value ...interface{}) Builder {
+	return BuildFunc(func(d Dialect, buf Buffer) error {
+		switch d {
+		case dialect.BigQuery:
+			buf.WriteString("[")
+		case dialect.PostgreSQL, dialect.CockroachDB:
+			buf.WriteString("ARRAY[")
+		default:
+			return errDialectNotSupported("ARRAY")
+		}
+		for i, v := range value {
+			if i > 0 {
+				buf.WriteString(", ")
+			}
+			buf.WriteString(placeholder)
+			buf.WriteValue(v)
+		}
+		buf.WriteString("]")
+		return nil
+	})
+}
+
+// Struct builds `STRUCT(value AS field, ...)` in bigquery.
+// The order of values should match fields.
+func Struct(field []string, value ...interface{}) Builder {
+	return BuildFunc(func(d Dialect, buf Buffer) error {
+		if d != dialect.BigQuery {
+			return errDialectNotSupported("STRUCT")
+		}
+		if len(field) != len(value) {
+			return ErrPlaceholderCount
+		}
+		buf.WriteString("STRUCT(")
+		for i, v := range value {
+			if i > 0 {
+				buf.WriteString(", ")
+			}
+			buf.WriteString(placeholder)
+			buf.WriteValue(v)
+			buf.WriteString(" AS ")
+			buf.WriteString(d.QuoteIdent(field[i]))
+		}
+		buf.WriteString(")")
+		return nil
+	})
+}
+
+// ArrayOf builds an array literal from the elements of slice,
+// like Array(value...). The empty slice is `'{}'` in postgres.
+func ArrayOf(slice interface{}) Builder {
+	v := reflect.ValueOf(slice)
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		return BuildFunc(func(Dialect, Buffer) error {
+			return ErrNotSupported
+		})
+	}
+	value := make([]interface{}, v.Len())
+	for i := range value {
+		value[i] = v.Index(i).Interface()
+	}
+	return BuildFunc(func(d Dialect, buf Buffer) error {
+		if len(value) == 0 && (d == dialect.PostgreSQL || d == dialect.CockroachDB) {
+			// ARRAY[] needs an explicit type
+			buf.WriteString("'{}'")
+			return nil
+		}
+		return Array(value...).Build(d, buf)
+	})
+}
+
+// Int64Array is a postgres array of BIGINT like `{1,2}`.
+type Int64Array []int64
+
+// Scan implements the Scanner interface.
+func (a *Int64Array) Scan(value interface{}) error {
+	elem, err := scanArray(value)
+	if err != nil || elem == nil {
+		*a = nil
+		return err
+	}
+	arr := make(Int64Array, len(elem))
+	for i, e := range elem {
+		if e == nil {
+			return fmt.Errorf("dbr: cannot scan NULL into Int64Array")
+		}
+		arr[i], err = strconv.ParseInt(*e, 10, 64)
+		if err != nil {
+			return err
+		}
+	}
+	*a = arr
+	return nil
+}
+
+// Value implements the driver Valuer interface.
+func (a Int64Array) Value() (driver.Value, error) {
+	if a == nil {
+		return nil, nil
+	}
+	elem := make([]string, len(a))
+	for i, v := range a {
+		elem[i] = strconv.FormatInt(v, 10)
+	}
+	return "{" + strings.Join(elem, ",") + "}", nil
+}
+
+// Float64Array is a postgres array of DOUBLE PRECISION like `{1.5,NaN}`.
+type Float64Array []float64
+
+// Scan implements the Scanner interface.
+func (a *Float64Array) Scan(value interface{}) error {
+	elem, err := scanArray(value)
+	if err != nil || elem == nil {
+		*a = nil
+		return err
+	}
+	arr := make(Float64Array, len(elem))
+	for i, e := range elem {
+		if e == nil {
+			return fmt.Errorf("dbr: cannot scan NULL into Float64Array")
+		}
+		arr[i], err = strconv.ParseFloat(*e, 64)
+		if err != nil {
+			return err
+		}
+	}
+	*a = arr
+	return nil
+}
+
+// Value implements the driver Valuer interface.
+func (a Float64Array) Value() (driver.Value, error) {
+	if a == nil {
+		return nil, nil
+	}
+	elem := make([]string, len(a))
+	for i, v := range a {
+		switch {
+		case math.IsInf(v, 1):
+			elem[i] = "Infinity"
+		case math.IsInf(v, -1):
+			elem[i] = "-Infinity"
+		default:
+			elem[i] = strconv.FormatFloat(v, 'g', -1, 64)
+		}
+	}
+	return "{" + strings.Join(elem, ",") + "}", nil
+}
+
+// BoolArray is a postgres array of BOOLEAN like `{t,f}`.
+type BoolArray []bool
+
+// Scan implements the Scanner interface.
+func (a *BoolArray) Scan(value interface{}) error {
+	elem, err := scanArray(value)
+	if err != nil || elem == nil {
+		*a = nil
+		return err
+	}
+	arr := make(BoolArray, len(elem))
+	for i, e := range elem {
+		if e == nil {
+			return fmt.Errorf("dbr: cannot scan NULL into BoolArray")
+		}
+		arr[i], err = strconv.ParseBool(*e)
+		if err != nil {
+			return err
+		}
+	}
+	*a = arr
+	return nil
+}
+
+// Value implements the driver Valuer interface.
+func (a BoolArray) Value() (driver.Value, error) {
+	if a == nil {
+		return nil, nil
+	}
+	elem := make([]string, len(a))
+	for i, v := range a {
+		elem[i] = "f"
+		if v {
+			elem[i] = "t"
+		}
+	}
+	return "{" + strings.Join(elem, ",") + "}", nil
+}
+
+// StringArray is a postgres array of TEXT like `{a,"b c"}`.
+type StringArray []string
+
+// Scan implements the Scanner interface.
+func (a *StringArray) Scan(value interface{}) error {
+	elem, err := scanArray(value)
+	if err != nil || elem == nil {
+		*a = nil
+		return err
+	}
+	arr := make(StringArray, len(elem))
+	for i, e := range elem {
+		if e == nil {
+			return fmt.Errorf("dbr: cannot scan NULL into StringArray")
+		}
+		arr[i] = *e
+	}
+	*a = arr
+	return nil
+}
+
+// Value implements the driver Valuer interface.
+// The elements are always quoted, so that NULL and the empty string are text.
+func (a StringArray) Value() (driver.Value, error) {
+	if a == nil {
+		return nil, nil
+	}
+	elem := make([]string, len(a))
+	for i, v := range a {
+		v = strings.ReplaceAll(v, `\`, `\\`)
+		v = strings.ReplaceAll(v, `"`, `\"`)
+		elem[i] = `"` + v + `"`
+	}
+	return "{" + strings.Join(elem, ",") + "}", nil
+}
+
+// scanArray parses an one-dimensional postgres array like `{1,"a b",NULL}`.
+// A NULL element is nil, and NULL array is nil slice.
+func scanArray(value interface{}) ([]*string, error) {
+	var s string
+	switch v := value.(type) {
+	case nil:
+		return nil, nil
+	case []byte:
+		s = string(v)
+	case string:
+		s = v
+	default:
+		return nil, fmt.Errorf("dbr: cannot scan %T into array", value)
+	}
+	if i := strings.IndexByte(s, '='); i >= 0 && strings.HasPrefix(s, "[") {
+		// skip dimension decoration like `[0:1]={1,2}`
+		s = s[i+1:]
+	}
+	if len(s) < 2 || s[0] != '{' || s[len(s)-1] != '}' {
+		return nil, fmt.Errorf("dbr: invalid array %q", s)
+	}
+	body := s[1 : len(s)-1]
+	elem := []*string{}
+	if body == "" {
+		return elem, nil
+	}
+	for i := 0; i <= len(body); {
+		var b strings.Builder
+		quoted := i < len(body) && body[i] == '"'
+		if quoted {
+			i++
+			for ; i < len(body) && body[i] != '"'; i++ {
+				if body[i] == '\\' {
+					i++
+					if i == len(body) {
+						break
+					}
+				}
+				b.WriteByte(body[i])
+			}
+			if i >= len(body) {
+				return nil, fmt.Errorf("dbr: invalid array %q", s)
+			}
+			i++ // closing quote
+		} else {
+			for ; i < len(body) && body[i] != ','; i++ {
+				if body[i] == '{' || body[i] == '"' {
+					return nil, fmt.Errorf("dbr: cannot scan multi-dimensional array %q", s)
+				}
+				b.WriteByte(body[i])
+			}
+		}
+		str := b.String()
+		if !quoted && str == "" {
+			return nil, fmt.Errorf("dbr: invalid array %q", s)
+		}
+		if !quoted && strings.EqualFold(str, "NULL") {
+			elem = append(elem, nil)
+		} else {
+			elem = append(elem, &str)
+		}
+		if i < len(body) && body[i] != ',' {
+			return nil, fmt.Errorf("dbr: invalid array %q", s)
+		}
+		i++
+	}
+	return elem, nil
+}
@@ -0,0 +1,90 @@
+package dbr
+
+import (
+	"database/sql/driver"
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/jiyeyuran/dbr/v2/dialect"
+	"github.com/stretchr/testify/require"
+)
+
+func TestArrayAndStruct(t *testing.T) {
+	builder := InsertInto("my-project.shop.orders").Columns("id", "tags", "address").
+		Values(1, Array("a", "b"), Struct([]string{"city", "zip"}, "Paris", 75001))
+
+	query, err := InterpolateForDialect("?", []interface{}{builder}, dialect.BigQuery)
+	require.NoError(t, err)
+	require.Equal(t, "INSERT INTO `my-project.shop.orders` (`id`,`tags`,`address`) VALUES (1,['a', 'b'],STRUCT('Paris' AS `city`, 75001 AS `zip`))", query)
+
+	query, err = InterpolateForDialect("?", []interface{}{Array(1, 2)}, dialect.PostgreSQL)
+	require.NoError(t, err)
+	require.Equal(t, "ARRAY[1, 2]", query)
+
+	_, err = InterpolateForDialect("?", []interface{}{Array(1, 2)}, dialect.MySQL)
+	require.True(t, errors.Is(err, ErrDialectNotSupported))
+}
+
+func TestArrayOf(t *testing.T) {
+	query, err := InterpolateForDialect("?", []interface{}{ArrayOf([]int64{1, 2})}, dialect.PostgreSQL)
+	require.NoError(t, err)
+	require.Equal(t, "ARRAY[1, 2]", query)
+
+	query, err = InterpolateForDialect("?", []interface{}{ArrayOf([]string{})}, dialect.PostgreSQL)
+	require.NoError(t, err)
+	require.Equal(t, "'{}'", query)
+
+	query, err = InterpolateForDialect("?", []interface{}{ArrayOf(StringArray{"a"})}, dialect.BigQuery)
+	require.NoError(t, err)
+	require.Equal(t, "['a']", query)
+
+	_, err = InterpolateForDialect("?", []interface{}{ArrayOf(1)}, dialect.PostgreSQL)
+	require.Equal(t, ErrNotSupported, err)
+}
+
+func TestPostgresArrayTypes(t *testing.T) {
+	var ints Int64Array
+	require.NoError(t, ints.Scan([]byte("{1,-2,3}")))
+	require.Equal(t, Int64Array{1, -2, 3}, ints)
+	require.Error(t, ints.Scan("{1,NULL}"))
+	require.NoError(t, ints.Scan(nil))
+	require.Nil(t, ints)
+
+	var floats Float64Array
+	require.NoError(t, floats.Scan("{1.5,-Infinity}"))
+	require.Equal(t, Float64Array{1.5, math.Inf(-1)}, floats)
+
+	var bools BoolArray
+	require.NoError(t, bools.Scan("{t,f}"))
+	require.Equal(t, BoolArray{true, false}, bools)
+
+	var strs StringArray
+	require.NoError(t, strs.Scan(`{a,"b c","d\"e","NULL",""}`))
+	require.Equal(t, StringArray{"a", "b c", `d"e`, "NULL", ""}, strs)
+	require.NoError(t, strs.Scan("{}"))
+	require.Equal(t, StringArray{}, strs)
+	require.Error(t, strs.Scan("{{a,b},{c,d}}"))
+	require.Error(t, strs.Scan(`{"a`))
+	require.Error(t, strs.Scan("a,b"))
+
+	for _, test := range []struct {
+		value driver.Valuer
+		want  interface{}
+	}{
+		{value: Int64Array{1, 2}, want: "{1,2}"},
+		{value: Float64Array{0.5, math.Inf(1)}, want: "{0.5,Infinity}"},
+		{value: BoolArray{true, false}, want: "{t,f}"},
+		{value: StringArray{"a", `b"\`, "NULL"}, want: `{"a","b\"\\","NULL"}`},
+		{value: StringArray{}, want: "{}"},
+		{value: Int64Array(nil), want: nil},
+	} {
+		v, err := test.value.Value()
+		require.NoError(t, err)
+		require.Equal(t, test.want, v)
+	}
+
+	query, err := InterpolateForDialect("?", []interface{}{Int64Array{1, 2}}, dialect.PostgreSQL)
+	require.NoError(t, err)
+	require.Equal(t, "'{1,2}'", query)
+}
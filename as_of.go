@@ -0,0 +1,16 @@
+package dbr
+
+import "github.com/jiyeyuran/dbr/v2/dialect"
+
+// asOfSystemTime builds `AS OF SYSTEM TIME ...` in cockroachdb.
+func asOfSystemTime(value interface{}) Builder {
+	return BuildFunc(func(d Dialect, buf Buffer) error {
+		if d != dialect.CockroachDB {
+			return errDialectNotSupported("AS OF SYSTEM TIME")
+		}
+		buf.WriteString(" AS OF SYSTEM TIME ")
+		buf.WriteString(placeholder)
+		buf.WriteValue(value)
+		return nil
+	})
+}
@@ -0,0 +1,113 @@
+package dbr
+
+import (
+	"context"
+)
+
+// Batch executes statements in one transaction.
+// The statements are sent one by one, since database/sql does not
+// pipeline them. They are not joined into one multi-statement query either,
+// which most drivers reject by default and which loses the result of each
+// statement.
+type Batch struct {
+	sess  *Session
+	stmts []Builder
+	opts  *TxOptions
+}
+
+// BatchResult is the result of a statement in Batch.
+type BatchResult struct {
+	Result *Result
+	Err    error
+}
+
+// Batch creates a Batch of the session.
+func (sess *Session) Batch() *Batch {
+	return &Batch{sess: sess}
+}
+
+// Add adds statements like InsertStmt, UpdateStmt and DeleteStmt,
+// which are executed in the transaction regardless of their sessions.
+func (b *Batch) Add(stmt ...Builder) *Batch {
+	b.stmts = append(b.stmts, stmt...)
+	return b
+}
+
+// TxOptions sets the options of the transaction.
+func (b *Batch) TxOptions(opts *TxOptions) *Batch {
+	b.opts = opts
+	return b
+}
+
+// Len returns the number of statements.
+func (b *Batch) Len() int {
+	return len(b.stmts)
+}
+
+// Exec executes the statements like ExecContext.
+func (b *Batch) Exec() ([]BatchResult, error) {
+	return b.ExecContext(context.Background())
+}
+
+// ExecContext executes the statements in order in one transaction,
+// which is committed if all of them succeed.
+//
+// It returns a BatchResult for each statement. If a statement fails,
+// the transaction is rolled back and the error is returned; the following
+// statements are not executed, and fail with ErrBatchAborted.
+func (b *Batch) ExecContext(ctx context.Context) ([]BatchResult, error) {
+	results := make([]BatchResult, len(b.stmts))
+	if len(b.stmts) == 0 {
+		return results, nil
+	}
+
+	tx, err := b.sess.BeginTx(ctx, b.opts)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.RollbackUnlessCommitted()
+
+	for i, stmt := range b.stmts {
+		result, err := execIn(ctx, tx, stmt)
+		results[i] = BatchResult{Result: result, Err: err}
+		if err != nil {
+			for j := i + 1; j < len(b.stmts); j++ {
+				results[j].Err = ErrBatchAborted
+			}
+			return results, err
+		}
+	}
+	return results, tx.Commit()
+}
+
+// execIn executes a copy of stmt with tx as its runner,
+// so that the statements shared with other goroutines are never changed.
+func execIn(ctx context.Context, tx *Tx, stmt Builder) (*Result, error) {
+	switch stmt := stmt.(type) {
+	case *InsertStmt:
+		c := *stmt
+		bind(tx, &c.runner, &c.EventReceiver, &c.Dialect)
+		return c.ExecContext(ctx)
+	case *UpdateStmt:
+		c := *stmt
+		bind(tx, &c.runner, &c.EventReceiver, &c.Dialect)
+		return c.ExecContext(ctx)
+	case *DeleteStmt:
+		c := *stmt
+		bind(tx, &c.runner, &c.EventReceiver, &c.Dialect)
+		return c.ExecContext(ctx)
+	}
+	return exec(ctx, tx, tx.EventReceiver, stmt, tx.Dialect)
+}
+
+// bind sets the runner of a copied statement to tx, and the EventReceiver
+// and Dialect if they are not set.
+func bind(tx *Tx, r *runner, log *EventReceiver, d *Dialect) {
+	*r = tx
+	if *log == nil {
+		*log = tx.EventReceiver
+	}
+	if *d == nil {
+		*d = tx.Dialect
+	}
+}
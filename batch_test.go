@@ -0,0 +1,74 @@
+package dbr
+
+import (
+	"errors"
+	"sync"
+	"testing"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/jiyeyuran/dbr/v2/dialect"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBatch(t *testing.T) {
+	sess, mock := newMockSession(t, dialect.MySQL)
+
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO `suggestions`").WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("UPDATE `suggestions`").WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec("DELETE FROM `suggestions`").WillReturnResult(sqlmock.NewResult(0, 3))
+	mock.ExpectCommit()
+
+	update := sess.Update("suggestions").Set("title", "a")
+	results, err := sess.Batch().
+		Add(InsertInto("suggestions").Pair("title", "a")).
+		Add(update, DeleteFrom("suggestions")).
+		Exec()
+	require.NoError(t, err)
+	require.Len(t, results, 3)
+	for i, result := range results {
+		require.NoError(t, result.Err)
+		n, err := result.Result.RowsAffected()
+		require.NoError(t, err)
+		require.Equal(t, int64(i+1), n)
+	}
+	require.Equal(t, sess, update.runner)
+
+	failed := errors.New("failed")
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO `suggestions`").WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("UPDATE `suggestions`").WillReturnError(failed)
+	mock.ExpectRollback()
+	results, err = sess.Batch().
+		Add(sess.InsertInto("suggestions").Pair("title", "a"), update, sess.DeleteFrom("suggestions")).
+		Exec()
+	require.Equal(t, failed, err)
+	require.Len(t, results, 3)
+	require.NoError(t, results[0].Err)
+	require.Equal(t, failed, results[1].Err)
+	require.Equal(t, ErrBatchAborted, results[2].Err)
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestBatchSharedStmt(t *testing.T) {
+	// the statement shared by the batches is never changed
+	update := Update("suggestions").Set("title", "a")
+	var wg sync.WaitGroup
+	for i := 0; i < 2; i++ {
+		sess, mock := newMockSession(t, dialect.MySQL)
+		mock.ExpectBegin()
+		mock.ExpectExec("UPDATE `suggestions`").WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := sess.Batch().Add(update).Exec()
+			require.NoError(t, err)
+			require.NoError(t, mock.ExpectationsWereMet())
+		}()
+	}
+	wg.Wait()
+	require.Nil(t, update.runner)
+	require.Nil(t, update.Dialect)
+}
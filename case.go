@@ -0,0 +1,74 @@
+package dbr
+
+import "fmt"
+
+// CaseBuilder builds `CASE WHEN ... THEN ... ELSE ... END`.
+type CaseBuilder struct {
+	WhenCond  []Builder
+	ThenValue []interface{}
+	ElseValue interface{}
+	HasElse   bool
+
+	// err is the unsupported condition of When, returned by Build.
+	err error
+}
+
+// Case creates a CaseBuilder.
+// It can be used in select columns, update values and ordering.
+func Case() *CaseBuilder {
+	return &CaseBuilder{}
+}
+
+// When adds `WHEN cond THEN value`.
+// cond can be Builder or string. Build fails if it is not.
+func (b *CaseBuilder) When(cond interface{}, value interface{}) *CaseBuilder {
+	switch cond := cond.(type) {
+	case string:
+		b.WhenCond = append(b.WhenCond, Expr(cond))
+	case Builder:
+		b.WhenCond = append(b.WhenCond, cond)
+	default:
+		if b.err == nil {
+			b.err = fmt.Errorf("%w: CASE condition of %T", ErrNotSupported, cond)
+		}
+		return b
+	}
+	b.ThenValue = append(b.ThenValue, value)
+	return b
+}
+
+// Else sets the value if no condition matches.
+func (b *CaseBuilder) Else(value interface{}) *CaseBuilder {
+	b.ElseValue = value
+	b.HasElse = true
+	return b
+}
+
+func (b *CaseBuilder) Build(d Dialect, buf Buffer) error {
+	if b.err != nil {
+		return b.err
+	}
+	buf.WriteString("CASE")
+	for i, cond := range b.WhenCond {
+		buf.WriteString(" WHEN ")
+		err := cond.Build(d, buf)
+		if err != nil {
+			return err
+		}
+		buf.WriteString(" THEN ")
+		buf.WriteString(placeholder)
+		buf.WriteValue(b.ThenValue[i])
+	}
+	if b.HasElse {
+		buf.WriteString(" ELSE ")
+		buf.WriteString(placeholder)
+		buf.WriteValue(b.ElseValue)
+	}
+	buf.WriteString(" END")
+	return nil
+}
+
+// As creates alias for case expression.
+func (b *CaseBuilder) As(alias string) Builder {
+	return as(b, alias)
+}
@@ -0,0 +1,48 @@
+package dbr
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/jiyeyuran/dbr/v2/dialect"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCase(t *testing.T) {
+	for _, test := range []struct {
+		builder Builder
+		query   string
+	}{
+		{
+			builder: Select(
+				"region",
+				Expr("SUM(?) AS paid", Case().When(Eq("status", "paid"), I("amount")).Else(0)),
+			).From("orders").GroupBy("region"),
+			query: "SELECT `region`, SUM(CASE WHEN `status` = 'paid' THEN `amount` ELSE 0 END) AS paid FROM `orders` GROUP BY `region`",
+		},
+		{
+			builder: Update("users").Set("tier", Case().When("points > 100", "gold").When(Gt("points", 10), "silver")),
+			query:   "UPDATE `users` SET `tier` = CASE WHEN points > 100 THEN 'gold' WHEN `points` > 10 THEN 'silver' END",
+		},
+		{
+			builder: Select("*").From("tasks").OrderBy(Case().When(Eq("priority", "high"), 0).Else(1)),
+			query:   "SELECT * FROM `tasks` ORDER BY CASE WHEN `priority` = 'high' THEN 0 ELSE 1 END",
+		},
+	} {
+		buf := NewBuffer()
+		err := test.builder.Build(dialect.MySQL, buf)
+		require.NoError(t, err)
+
+		query, err := InterpolateForDialect(buf.String(), buf.Value(), dialect.MySQL)
+		require.NoError(t, err)
+		require.Equal(t, test.query, query)
+	}
+}
+
+func TestCaseInvalidCondition(t *testing.T) {
+	b := Case().When(1, "one").When("n = 2", "two")
+	require.Len(t, b.WhenCond, 1)
+	require.Equal(t, []interface{}{"two"}, b.ThenValue)
+	err := b.Build(dialect.MySQL, NewBuffer())
+	require.True(t, errors.Is(err, ErrNotSupported))
+}
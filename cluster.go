@@ -0,0 +1,186 @@
+package dbr
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Balancer picks a replica for a read.
+type Balancer int
+
+const (
+	// RoundRobin picks the replicas in turn.
+	RoundRobin Balancer = iota
+	// LeastLoaded picks the replica with the fewest connections in use.
+	LeastLoaded
+)
+
+const defaultCooldown = 30 * time.Second
+
+// ReplicaPool routes the reads of the sessions to the replicas of
+// Connection, which are Select and Union without INTO, FOR UPDATE, FOR SHARE
+// or OnPrimary. SelectBySql reads from the replicas only with OnReplica.
+// Transactions and the sessions with Init use the primary.
+//
+// A replica is excluded for Cooldown if it fails with a transient error,
+// and the read is sent to another replica, or the primary if none is healthy.
+type ReplicaPool struct {
+	// Balance picks the replica, RoundRobin by default.
+	Balance Balancer
+	// Cooldown is how long a failed replica is excluded, 30s by default.
+	Cooldown time.Duration
+
+	replicas []*replica
+	next     uint32
+}
+
+type replica struct {
+	*sql.DB
+
+	mu        sync.Mutex
+	downUntil time.Time
+}
+
+func (r *replica) healthy(now time.Time) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return !now.Before(r.downUntil)
+}
+
+// NewReplicaPool creates a ReplicaPool of db.
+func NewReplicaPool(db ...*sql.DB) *ReplicaPool {
+	p := new(ReplicaPool)
+	for _, db := range db {
+		p.replicas = append(p.replicas, &replica{DB: db})
+	}
+	return p
+}
+
+// OpenCluster creates a Connection to the primary, which reads from the
+// replicas with a ReplicaPool.
+// log can be nil to ignore logging.
+func OpenCluster(driver string, log EventReceiver, primaryDSN string, replicaDSNs ...string) (*Connection, error) {
+	conn, err := Open(driver, primaryDSN, log)
+	if err != nil {
+		return nil, err
+	}
+	pool := new(ReplicaPool)
+	for _, dsn := range replicaDSNs {
+		db, err := sql.Open(driver, dsn)
+		if err != nil {
+			pool.close()
+			conn.DB.Close()
+			return nil, err
+		}
+		pool.replicas = append(pool.replicas, &replica{DB: db})
+	}
+	conn.Replicas = pool
+	return conn, nil
+}
+
+// pick returns a healthy replica other than the excluded ones,
+// or nil if none is healthy.
+func (p *ReplicaPool) pick(exclude map[*replica]bool) *replica {
+	now := time.Now()
+	n := len(p.replicas)
+	if n == 0 {
+		return nil
+	}
+	var picked *replica
+	start := int(atomic.AddUint32(&p.next, 1) - 1)
+	for i := 0; i < n; i++ {
+		r := p.replicas[(start+i)%n]
+		if exclude[r] || !r.healthy(now) {
+			continue
+		}
+		if p.Balance != LeastLoaded {
+			return r
+		}
+		if picked == nil || r.Stats().InUse < picked.Stats().InUse {
+			picked = r
+		}
+	}
+	return picked
+}
+
+// markDown excludes r for Cooldown.
+func (p *ReplicaPool) markDown(r *replica) {
+	cooldown := p.Cooldown
+	if cooldown <= 0 {
+		cooldown = defaultCooldown
+	}
+	r.mu.Lock()
+	r.downUntil = time.Now().Add(cooldown)
+	r.mu.Unlock()
+}
+
+func (p *ReplicaPool) close() {
+	for _, r := range p.replicas {
+		r.Close()
+	}
+}
+
+// replicaRunner runs the reads of a session on the replicas.
+type replicaRunner struct {
+	*Session
+	pool *ReplicaPool
+}
+
+// QueryContext runs the query on a healthy replica,
+// and fails over to the others and then the primary on transient errors.
+func (rr *replicaRunner) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	var tried map[*replica]bool
+	for {
+		r := rr.pool.pick(tried)
+		if r == nil {
+			watchOn(ctx, rr.DB)
+			return rr.Session.QueryContext(ctx, query, args...)
+		}
+		watchOn(ctx, r.DB)
+		rows, err := r.QueryContext(ctx, query, args...)
+		if err == nil || !IsTransient(err) || ctx.Err() != nil {
+			return rows, err
+		}
+		rr.pool.markDown(r)
+		rr.EventErrKv("dbr.replica.down", err, kvs{
+			"sql": query,
+		})
+		if tried == nil {
+			tried = make(map[*replica]bool)
+		}
+		tried[r] = true
+	}
+}
+
+// stmtCache disables the cache of the primary.
+func (rr *replicaRunner) stmtCache() *stmtCache {
+	return nil
+}
+
+// routeRead returns the runner of builder, which is a replicaRunner
+// if builder reads from the replicas of the session.
+func routeRead(runner runner, builder Builder) runner {
+	sess, ok := runner.(*Session)
+	if !ok || sess.Connection == nil || sess.Replicas == nil || len(sess.Init) > 0 {
+		return runner
+	}
+	switch builder := builder.(type) {
+	case *SelectStmt:
+		if builder.IntoTable != "" || builder.lock != nil || builder.primary {
+			return runner
+		}
+		if builder.raw.Query != "" && !builder.replica {
+			return runner
+		}
+	case *UnionStmt:
+		if builder.primary {
+			return runner
+		}
+	default:
+		return runner
+	}
+	return &replicaRunner{Session: sess, pool: sess.Replicas}
+}
@@ -0,0 +1,72 @@
+package dbr
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/jiyeyuran/dbr/v2/dialect"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReplicaPool(t *testing.T) {
+	sess, mock := newMockSession(t, dialect.MySQL)
+	replica1, mock1, err := sqlmock.New()
+	require.NoError(t, err)
+	replica2, mock2, err := sqlmock.New()
+	require.NoError(t, err)
+	sess.Replicas = NewReplicaPool(replica1, replica2)
+
+	mock1.ExpectQuery("SELECT `id` FROM `suggestions`").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	mock2.ExpectQuery("SELECT `id` FROM `suggestions`").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2))
+	for _, want := range []int64{1, 2} {
+		var id int64
+		require.NoError(t, sess.Select("id").From("suggestions").LoadOne(&id))
+		require.Equal(t, want, id)
+	}
+
+	// writes, locks, OnPrimary and raw queries use the primary
+	mock.ExpectQuery("SELECT `id` FROM `suggestions`").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(3))
+	mock.ExpectQuery("SELECT `id` FROM `suggestions` FOR UPDATE").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(3))
+	mock.ExpectExec("DELETE FROM `suggestions`").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery("SELECT next_id()").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(3))
+	var id int64
+	require.NoError(t, sess.Select("id").From("suggestions").OnPrimary().LoadOne(&id))
+	require.NoError(t, sess.Select("id").From("suggestions").ForUpdate().LoadOne(&id))
+	_, err = sess.DeleteFrom("suggestions").Exec()
+	require.NoError(t, err)
+	require.NoError(t, sess.SelectBySql("SELECT next_id()").LoadOne(&id))
+
+	// unless they opt in
+	mock1.ExpectQuery("SELECT id FROM suggestions").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	require.NoError(t, sess.SelectBySql("SELECT id FROM suggestions").OnReplica().LoadOne(&id))
+	require.Equal(t, int64(1), id)
+
+	// the failed replica is excluded
+	mock1.ExpectQuery("SELECT `id` FROM `suggestions`").WillReturnError(&testMySQLError{Number: 2013})
+	mock2.ExpectQuery("SELECT `id` FROM `suggestions`").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2))
+	mock2.ExpectQuery("SELECT `id` FROM `suggestions`").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2))
+	for i := 0; i < 2; i++ {
+		require.NoError(t, sess.Select("id").From("suggestions").LoadOne(&id))
+		require.Equal(t, int64(2), id)
+	}
+
+	// the watchdog kills the query on the replica
+	sess.Watchdog = Watchdog{Threshold: 10 * time.Millisecond, Kill: true}
+	mock2.ExpectQuery(`^/\* dbr:watch=[0-9a-f]{16} \*/ SELECT ` + "`id` FROM `suggestions`$").
+		WillDelayFor(200 * time.Millisecond).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2))
+	mock2.ExpectQuery(regexp.QuoteMeta("SELECT ID FROM information_schema.PROCESSLIST")).
+		WillReturnRows(sqlmock.NewRows([]string{"ID"}).AddRow(42))
+	mock2.ExpectExec(regexp.QuoteMeta("KILL QUERY 42")).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	require.NoError(t, sess.Select("id").From("suggestions").LoadOne(&id))
+	require.Eventually(t, func() bool {
+		return mock2.ExpectationsWereMet() == nil
+	}, time.Second, 10*time.Millisecond)
+
+	require.NoError(t, mock.ExpectationsWereMet())
+	require.NoError(t, mock1.ExpectationsWereMet())
+	require.NoError(t, mock2.ExpectationsWereMet())
+}
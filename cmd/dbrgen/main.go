@@ -0,0 +1,191 @@
+// Command dbrgen generates the Columns and ColumnPtrs methods of structs,
+// so that dbr loads them without reflection.
+//
+// Usage:
+//
+//	//go:generate dbrgen -type User,Post
+//
+// The generated methods implement dbr.ColumnsScanner, which is preferred by
+// Load over the struct fields. The columns are the `db` tags, or the snake
+// case of the field names, so NameMapper must be the default SnakeCase.
+// Embedded structs and the `prefix` tag option are not supported.
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/jiyeyuran/dbr/v2"
+)
+
+var (
+	typeNames = flag.String("type", "", "comma-separated list of struct type names; required")
+	output    = flag.String("output", "", "output file name; default dbr_gen.go in the directory")
+	tagName   = flag.String("tag", "db", "struct tag of column names")
+)
+
+func main() {
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: dbrgen -type T[,T...] [flags] [directory]\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if *typeNames == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	dir := "."
+	if flag.NArg() > 0 {
+		dir = flag.Arg(0)
+	}
+	src, err := generateDir(dir, strings.Split(*typeNames, ","), *tagName)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "dbrgen:", err)
+		os.Exit(1)
+	}
+	name := *output
+	if name == "" {
+		name = filepath.Join(dir, "dbr_gen.go")
+	}
+	if err := os.WriteFile(name, src, 0644); err != nil {
+		fmt.Fprintln(os.Stderr, "dbrgen:", err)
+		os.Exit(1)
+	}
+}
+
+// generateDir generates the methods of types in the package of dir.
+func generateDir(dir string, types []string, tag string) ([]byte, error) {
+	fset := token.NewFileSet()
+	pkgs, err := parser.ParseDir(fset, dir, func(fi os.FileInfo) bool {
+		return !strings.HasSuffix(fi.Name(), "_test.go")
+	}, 0)
+	if err != nil {
+		return nil, err
+	}
+	if len(pkgs) != 1 {
+		return nil, fmt.Errorf("%d packages found in %s", len(pkgs), dir)
+	}
+	for _, pkg := range pkgs {
+		var files []*ast.File
+		for _, f := range pkg.Files {
+			files = append(files, f)
+		}
+		// generate in the same order
+		sort.Slice(files, func(i, j int) bool {
+			return fset.File(files[i].Pos()).Name() < fset.File(files[j].Pos()).Name()
+		})
+		return generate(pkg.Name, files, types, tag)
+	}
+	return nil, nil
+}
+
+// structField is a field of the generated methods.
+type structField struct {
+	name   string
+	column string
+}
+
+// generate generates the methods of types in files of package pkgName.
+func generate(pkgName string, files []*ast.File, types []string, tag string) ([]byte, error) {
+	structs := make(map[string]*ast.StructType)
+	for _, f := range files {
+		ast.Inspect(f, func(n ast.Node) bool {
+			spec, ok := n.(*ast.TypeSpec)
+			if !ok {
+				return true
+			}
+			if st, ok := spec.Type.(*ast.StructType); ok {
+				structs[spec.Name.Name] = st
+			}
+			return false
+		})
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "// Code generated by dbrgen; DO NOT EDIT.\n\npackage %s\n", pkgName)
+	for _, name := range types {
+		name = strings.TrimSpace(name)
+		st, ok := structs[name]
+		if !ok {
+			return nil, fmt.Errorf("struct type %s not found", name)
+		}
+		fields, err := structFields(st, tag)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+
+		fmt.Fprintf(&buf, "\n// Columns returns the columns of %s.\n", name)
+		fmt.Fprintf(&buf, "func (r %s) Columns() []string {\n\treturn []string{", name)
+		for i, f := range fields {
+			if i > 0 {
+				buf.WriteString(", ")
+			}
+			buf.WriteString(strconv.Quote(f.column))
+		}
+		buf.WriteString("}\n}\n")
+
+		fmt.Fprintf(&buf, "\n// ColumnPtrs returns the scan destinations of %s in the order of Columns.\n", name)
+		fmt.Fprintf(&buf, "func (r *%s) ColumnPtrs() []interface{} {\n\treturn []interface{}{", name)
+		for i, f := range fields {
+			if i > 0 {
+				buf.WriteString(", ")
+			}
+			buf.WriteString("&r." + f.name)
+		}
+		buf.WriteString("}\n}\n")
+	}
+	return format.Source(buf.Bytes())
+}
+
+// structFields returns the exported fields of st with the columns.
+func structFields(st *ast.StructType, tag string) ([]structField, error) {
+	var fields []structField
+	for _, field := range st.Fields.List {
+		if len(field.Names) == 0 {
+			return nil, errors.New("embedded structs are not supported")
+		}
+		var column, opt string
+		if field.Tag != nil {
+			s, err := strconv.Unquote(field.Tag.Value)
+			if err != nil {
+				return nil, err
+			}
+			column = reflect.StructTag(s).Get(tag)
+			if i := strings.Index(column, ","); i != -1 {
+				column, opt = column[:i], column[i+1:]
+			}
+		}
+		if column == "-" {
+			continue
+		}
+		for _, o := range strings.Split(opt, ",") {
+			if o == "prefix" || strings.HasPrefix(o, "prefix=") {
+				return nil, errors.New("the prefix tag option is not supported")
+			}
+		}
+		for _, name := range field.Names {
+			if !name.IsExported() {
+				continue
+			}
+			c := column
+			if c == "" {
+				c = dbr.SnakeCase(name.Name)
+			}
+			fields = append(fields, structField{name: name.Name, column: c})
+		}
+	}
+	return fields, nil
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerate(t *testing.T) {
+	const src = `package model
+
+type User struct {
+	ID        int64
+	Name      string ` + "`db:\"full_name\"`" + `
+	Secret    string ` + "`db:\"-\"`" + `
+	CreatedAt *string
+	password  string
+}
+
+type Embedded struct {
+	User
+}
+
+type Prefixed struct {
+	Author User ` + "`db:\"author,prefix\"`" + `
+}
+`
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "model.go", src, 0)
+	require.NoError(t, err)
+	files := []*ast.File{f}
+
+	out, err := generate("model", files, []string{"User"}, "db")
+	require.NoError(t, err)
+	require.Equal(t, `// Code generated by dbrgen; DO NOT EDIT.
+
+package model
+
+// Columns returns the columns of User.
+func (r User) Columns() []string {
+	return []string{"id", "full_name", "created_at"}
+}
+
+// ColumnPtrs returns the scan destinations of User in the order of Columns.
+func (r *User) ColumnPtrs() []interface{} {
+	return []interface{}{&r.ID, &r.Name, &r.CreatedAt}
+}
+`, string(out))
+
+	_, err = generate("model", files, []string{"Embedded"}, "db")
+	require.Error(t, err)
+	_, err = generate("model", files, []string{"Prefixed"}, "db")
+	require.Error(t, err)
+	_, err = generate("model", files, []string{"Missing"}, "db")
+	require.Error(t, err)
+}
@@ -0,0 +1,156 @@
+package dbr
+
+import "reflect"
+
+// ColumnsValuer is implemented by the types of struct fields that are stored
+// in multiple columns, like Money{Amount, Currency} in amount and currency,
+// which are handled by InsertStmt.Record and UpdateRecord.
+//
+// The columns are prefixed with the field name and underscore
+// if the field has the `prefix` tag option, like `db:"price,prefix"`
+// for price_amount and price_currency, or `db:"price,prefix=p_"` for p_amount.
+type ColumnsValuer interface {
+	// Columns returns the column names.
+	Columns() []string
+	// ColumnValues returns the values in the order of Columns().
+	ColumnValues() []interface{}
+}
+
+// ColumnsScanner is implemented by the pointers of struct field types
+// that are loaded from multiple columns like ColumnsValuer.
+// Load also uses it instead of the struct fields of the loaded structs,
+// like the methods generated by cmd/dbrgen.
+type ColumnsScanner interface {
+	// Columns returns the column names.
+	Columns() []string
+	// ColumnPtrs returns the scan destinations in the order of Columns().
+	ColumnPtrs() []interface{}
+}
+
+// fieldColumns returns the columns and the values (or the scan destinations
+// if ptr is true) of a field stored in multiple columns.
+// The columns are nil for other fields.
+func fieldColumns(field reflect.Value, tag string, opt tagOptions, ptr bool) ([]string, []interface{}) {
+	if !field.CanInterface() {
+		return nil, nil
+	}
+	var column []string
+	var value []interface{}
+	if ptr {
+		if !field.CanAddr() {
+			return nil, nil
+		}
+		scanner, ok := field.Addr().Interface().(ColumnsScanner)
+		if !ok {
+			return nil, nil
+		}
+		column, value = scanner.Columns(), scanner.ColumnPtrs()
+	} else {
+		valuer, ok := field.Interface().(ColumnsValuer)
+		if !ok && field.CanAddr() {
+			valuer, ok = field.Addr().Interface().(ColumnsValuer)
+		}
+		if !ok {
+			return nil, nil
+		}
+		column, value = valuer.Columns(), valuer.ColumnValues()
+	}
+	if prefix := opt.prefix(tag); prefix != "" {
+		prefixed := make([]string, len(column))
+		for i, col := range column {
+			prefixed[i] = prefix + col
+		}
+		column = prefixed
+	}
+	return column, value
+}
+
+// ColumnsOf returns the columns of struct v, or a pointer to it, except the
+// given columns, so that the columns of SELECT and INSERT are kept in sync
+// with the model, like sess.InsertInto("user").Columns(dbr.ColumnsOf(u, "id")...).
+//
+// Embedded structs and nested structs with the `prefix` tag option are
+// flattened, and the fields of ColumnsValuer or ColumnsScanner are expanded.
+func ColumnsOf(v interface{}, except ...string) []string {
+	return newTagStore().columnsOf(reflect.TypeOf(v), except)
+}
+
+func (s *tagStore) columnsOf(t reflect.Type, except []string) []string {
+	var column []string
+	s.appendColumns(&column, t, "", make(map[reflect.Type]bool))
+	ret := column[:0]
+	for _, col := range column {
+		excluded := false
+		for _, e := range except {
+			if col == e {
+				excluded = true
+				break
+			}
+		}
+		if !excluded {
+			ret = append(ret, col)
+		}
+	}
+	return ret
+}
+
+// appendColumns appends the columns of struct t with prefix to column.
+func (s *tagStore) appendColumns(column *[]string, t reflect.Type, prefix string, seen map[reflect.Type]bool) {
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct || seen[t] {
+		return
+	}
+	if multi, ok := multiColumns(t); ok {
+		for _, col := range multi {
+			*column = append(*column, prefix+col)
+		}
+		return
+	}
+	seen[t] = true
+	defer delete(seen, t)
+
+	l := s.get(t)
+	opts := s.options(t)
+	for i := 0; i < t.NumField(); i++ {
+		tag := l[i]
+		if tag == "" {
+			continue
+		}
+		field := t.Field(i)
+		fieldType := field.Type
+		if fieldType.Kind() == reflect.Ptr {
+			fieldType = fieldType.Elem()
+		}
+		if multi, ok := multiColumns(fieldType); ok {
+			p := prefix + opts[i].prefix(tag)
+			for _, col := range multi {
+				*column = append(*column, p+col)
+			}
+			continue
+		}
+		if fieldType.Kind() == reflect.Struct && !isValueType(fieldType) {
+			if p := opts[i].prefix(tag); p != "" {
+				s.appendColumns(column, fieldType, prefix+p, seen)
+				continue
+			}
+			if field.Anonymous {
+				s.appendColumns(column, fieldType, prefix, seen)
+				continue
+			}
+		}
+		*column = append(*column, prefix+tag)
+	}
+}
+
+// multiColumns returns the columns of ColumnsValuer or ColumnsScanner type t.
+func multiColumns(t reflect.Type) ([]string, bool) {
+	switch v := reflect.New(t).Interface().(type) {
+	case ColumnsValuer:
+		return v.Columns(), true
+	case ColumnsScanner:
+		return v.Columns(), true
+	}
+	return nil, false
+}
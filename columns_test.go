@@ -0,0 +1,106 @@
+package dbr
+
+import (
+	"testing"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/jiyeyuran/dbr/v2/dialect"
+	"github.com/stretchr/testify/require"
+)
+
+type testMoney struct {
+	amount   int64
+	currency string
+}
+
+func (m testMoney) Columns() []string {
+	return []string{"amount", "currency"}
+}
+
+func (m testMoney) ColumnValues() []interface{} {
+	return []interface{}{m.amount, m.currency}
+}
+
+func (m *testMoney) ColumnPtrs() []interface{} {
+	return []interface{}{&m.amount, &m.currency}
+}
+
+type testOrder struct {
+	ID    int64
+	Price testMoney `db:"price,prefix"`
+	Fee   testMoney
+}
+
+func TestColumnsValuer(t *testing.T) {
+	order := &testOrder{
+		ID:    1,
+		Price: testMoney{amount: 1000, currency: "USD"},
+		Fee:   testMoney{amount: 5, currency: "EUR"},
+	}
+	builder := InsertInto("orders").Record(order)
+	require.Equal(t, []string{"id", "price_amount", "price_currency", "amount", "currency"}, builder.Column)
+
+	query, err := InterpolateForDialect("?", []interface{}{builder}, dialect.MySQL)
+	require.NoError(t, err)
+	require.Equal(t, "INSERT INTO `orders` (`id`,`price_amount`,`price_currency`,`amount`,`currency`) VALUES (1,1000,'USD',5,'EUR')", query)
+
+	changed := *order
+	changed.Price.amount = 2000
+	query, err = InterpolateForDialect("?", []interface{}{UpdateRecord("orders", order, &changed)}, dialect.MySQL)
+	require.NoError(t, err)
+	require.Equal(t, "UPDATE `orders` SET `price_amount` = 2000", query)
+}
+
+func TestColumnsScanner(t *testing.T) {
+	sess, mock := newMockSession(t, dialect.MySQL)
+
+	mock.ExpectQuery("SELECT \\* FROM `orders`").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "price_amount", "price_currency", "amount", "currency"}).
+			AddRow(1, 1000, "USD", 5, "EUR"))
+
+	var orders []testOrder
+	_, err := sess.Select("*").From("orders").Load(&orders)
+	require.NoError(t, err)
+	require.Equal(t, []testOrder{{
+		ID:    1,
+		Price: testMoney{amount: 1000, currency: "USD"},
+		Fee:   testMoney{amount: 5, currency: "EUR"},
+	}}, orders)
+
+	// structs with ColumnPtrs like the code of cmd/dbrgen
+	mock.ExpectQuery("SELECT `currency`, `amount` FROM `orders`").
+		WillReturnRows(sqlmock.NewRows([]string{"currency", "amount"}).AddRow("USD", 1000))
+	var prices []*testMoney
+	_, err = sess.Select("currency", "amount").From("orders").Load(&prices)
+	require.NoError(t, err)
+	require.Equal(t, []*testMoney{{amount: 1000, currency: "USD"}}, prices)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestColumnsOf(t *testing.T) {
+	type base struct {
+		ID        int64
+		CreatedAt NullTime
+	}
+	type author struct {
+		Name string
+	}
+	type post struct {
+		base
+		Title  string
+		Secret string  `db:"-"`
+		Author *author `db:"author,prefix"`
+		Price  testMoney
+		Editor author
+		hidden int
+	}
+	require.Equal(t, []string{"id", "created_at", "title", "author_name", "amount", "currency", "editor"}, ColumnsOf(post{}))
+	require.Equal(t, []string{"title", "author_name", "amount", "currency", "editor"}, ColumnsOf(&post{}, "id", "created_at"))
+	require.Equal(t, []string{"amount", "currency"}, ColumnsOf(testMoney{}))
+
+	query, err := InterpolateForDialect("?", []interface{}{
+		Select().From("posts").ColumnsFromStruct(&base{}, "created_at"),
+	}, dialect.MySQL)
+	require.NoError(t, err)
+	require.Equal(t, "SELECT `id` FROM `posts`", query)
+}
@@ -120,6 +120,33 @@ func Lte(column string, value interface{}) Builder {
 	})
 }
 
+func buildInSelect(d Dialect, buf Buffer, pred string, column string, subquery Builder) error {
+	buf.WriteString(d.QuoteIdent(column))
+	buf.WriteString(" ")
+	buf.WriteString(pred)
+	buf.WriteString(" (")
+	err := subquery.Build(d, buf)
+	if err != nil {
+		return err
+	}
+	buf.WriteString(")")
+	return nil
+}
+
+// InSelect is `IN` with a subquery like SelectStmt.
+func InSelect(column string, subquery Builder) Builder {
+	return BuildFunc(func(d Dialect, buf Buffer) error {
+		return buildInSelect(d, buf, "IN", column, subquery)
+	})
+}
+
+// NotInSelect is `NOT IN` with a subquery like SelectStmt.
+func NotInSelect(column string, subquery Builder) Builder {
+	return BuildFunc(func(d Dialect, buf Buffer) error {
+		return buildInSelect(d, buf, "NOT IN", column, subquery)
+	})
+}
+
 func buildLike(d Dialect, buf Buffer, column, pattern string, isNot bool, escape []string) error {
 	buf.WriteString(d.QuoteIdent(column))
 	if isNot {
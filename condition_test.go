@@ -63,6 +63,16 @@ func TestCondition(t *testing.T) {
 			query: "(`a` < ?) AND ((`b` > ?) OR (`c` != ?))",
 			value: []interface{}{1, 2, 3},
 		},
+		{
+			cond:  InSelect("a", Select("id").From("t").Where(Eq("b", 1))),
+			query: "`a` IN (SELECT `id` FROM `t` WHERE (`b` = ?))",
+			value: []interface{}{1},
+		},
+		{
+			cond:  NotInSelect("a", Select("id").From("t").Where(Eq("b", 1))),
+			query: "`a` NOT IN (SELECT `id` FROM `t` WHERE (`b` = ?))",
+			value: []interface{}{1},
+		},
 		{
 			cond:  Like("a", "%BLAH%", "#"),
 			query: "`a` LIKE '%BLAH%' ESCAPE '#'",
@@ -0,0 +1,194 @@
+package dbr
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/jiyeyuran/dbr/v2/dialect"
+)
+
+// ConflictStmt builds `ON CONFLICT ...` in postgres and sqlite,
+// or `ON DUPLICATE KEY UPDATE ...` in mysql.
+type ConflictStmt struct {
+	stmt *InsertStmt
+
+	Column    []string
+	WhereCond []Builder
+	Value     map[string]interface{}
+}
+
+func (c *ConflictStmt) Build(d Dialect, buf Buffer) error {
+	caps := dialect.CapabilitiesOf(d)
+	switch {
+	case caps.SupportsOnDuplicateKey:
+		if len(c.Value) == 0 {
+			// built as INSERT IGNORE
+			return nil
+		}
+		// mysql checks all unique keys, so the columns are not needed
+		buf.WriteString(" ON DUPLICATE KEY UPDATE ")
+	case caps.SupportsOnConflict:
+		if len(c.Column) == 0 && len(c.Value) > 0 {
+			// DO UPDATE needs the columns or the constraint to infer
+			return fmt.Errorf("%w: ON CONFLICT DO UPDATE needs conflict columns", ErrColumnNotSpecified)
+		}
+		buf.WriteString(" ON CONFLICT ")
+		if len(c.Column) > 0 {
+			buf.WriteString("(")
+			for i, col := range c.Column {
+				if i > 0 {
+					buf.WriteString(", ")
+				}
+				buf.WriteString(d.QuoteIdent(col))
+			}
+			buf.WriteString(") ")
+			if len(c.WhereCond) > 0 {
+				buf.WriteString("WHERE ")
+				err := And(c.WhereCond...).Build(d, buf)
+				if err != nil {
+					return err
+				}
+				buf.WriteString(" ")
+			}
+		}
+		if len(c.Value) == 0 {
+			buf.WriteString("DO NOTHING")
+			return nil
+		}
+		buf.WriteString("DO UPDATE SET ")
+	default:
+		return errDialectNotSupported("ON CONFLICT")
+	}
+
+	buildAssignments(d, buf, c.Value)
+	return nil
+}
+
+// ignored reports whether c is built as INSERT IGNORE in dialect d.
+func (c *ConflictStmt) ignored(d Dialect) bool {
+	return c != nil && len(c.Value) == 0 && dialect.CapabilitiesOf(d).SupportsOnDuplicateKey
+}
+
+// buildAssignments builds `column = value, ...` sorted by column.
+func buildAssignments(d Dialect, buf Buffer, value map[string]interface{}) {
+	col := make([]string, 0, len(value))
+	for k := range value {
+		col = append(col, k)
+	}
+	sort.Strings(col)
+
+	for i, k := range col {
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+		buf.WriteString(d.QuoteIdent(k))
+		buf.WriteString(" = ")
+		buf.WriteString(placeholder)
+		buf.WriteValue(value[k])
+	}
+}
+
+// Where adds a condition to the conflict columns, which matches the
+// predicate of a partial unique index.
+// query can be Builder or string. value is used only if query type is string.
+// It is ignored by mysql, which checks all unique keys.
+func (c *ConflictStmt) Where(query interface{}, value ...interface{}) *ConflictStmt {
+	switch query := query.(type) {
+	case string:
+		c.WhereCond = append(c.WhereCond, Expr(query, value...))
+	case Builder:
+		c.WhereCond = append(c.WhereCond, query)
+	}
+	return c
+}
+
+// DoUpdate sets the columns to update on conflict.
+// The values can be Builder like Excluded.
+// If value is empty, the conflicting rows are skipped like DoNothing.
+func (c *ConflictStmt) DoUpdate(value map[string]interface{}) *InsertStmt {
+	c.Value = value
+	return c.stmt
+}
+
+// DoNothing skips the rows that conflict on the columns.
+// It builds `INSERT IGNORE` in mysql like Ignore.
+func (c *ConflictStmt) DoNothing() *InsertStmt {
+	c.Value = nil
+	return c.stmt
+}
+
+// Upsert creates an InsertStmt for the record, which updates all the columns
+// except conflict columns on conflict.
+// The conflict columns are the pk columns of the record if they are not set,
+// since postgres and sqlite need them.
+func Upsert(table string, record interface{}, conflictColumn ...string) *InsertStmt {
+	return upsert(InsertInto(table), newTagStore(), record, conflictColumn)
+}
+
+// Upsert creates an InsertStmt for the record, which updates all the columns
+// except conflict columns on conflict.
+// The conflict columns are the pk columns of the record if they are not set,
+// since postgres and sqlite need them.
+func (sess *Session) Upsert(table string, record interface{}, conflictColumn ...string) *InsertStmt {
+	return upsert(sess.InsertInto(table), newTagStoreFor(sess), record, conflictColumn)
+}
+
+// Upsert creates an InsertStmt for the record, which updates all the columns
+// except conflict columns on conflict.
+// The conflict columns are the pk columns of the record if they are not set,
+// since postgres and sqlite need them.
+func (tx *Tx) Upsert(table string, record interface{}, conflictColumn ...string) *InsertStmt {
+	return upsert(tx.InsertInto(table), newTagStoreFor(tx), record, conflictColumn)
+}
+
+func upsert(b *InsertStmt, s *tagStore, record interface{}, conflictColumn []string) *InsertStmt {
+	b.Record(record)
+	if len(conflictColumn) == 0 {
+		conflictColumn = s.pkColumns(record)
+	}
+	return b.OnConflict(conflictColumn...).DoUpdate(upsertValue(b.Column, conflictColumn))
+}
+
+// pkColumns returns the columns of record with the pk tag option.
+func (s *tagStore) pkColumns(record interface{}) []string {
+	v := reflect.Indirect(reflect.ValueOf(record))
+	if v.Kind() != reflect.Struct {
+		return nil
+	}
+	var pk []string
+	column, opts := s.recordColumns(v, nil, nil)
+	for i, col := range column {
+		if opts[i].Contains("pk") {
+			pk = append(pk, col)
+		}
+	}
+	return pk
+}
+
+func upsertValue(column, conflictColumn []string) map[string]interface{} {
+	value := make(map[string]interface{})
+	for _, col := range column {
+		value[col] = Excluded(col)
+	}
+	for _, col := range conflictColumn {
+		delete(value, col)
+	}
+	return value
+}
+
+// Excluded refers to the value of column that was proposed for insertion.
+// It builds `EXCLUDED.column` in postgres and sqlite, and `VALUES(column)` in mysql.
+func Excluded(column string) Builder {
+	return BuildFunc(func(d Dialect, buf Buffer) error {
+		if dialect.CapabilitiesOf(d).SupportsOnDuplicateKey {
+			buf.WriteString("VALUES(")
+			buf.WriteString(d.QuoteIdent(column))
+			buf.WriteString(")")
+			return nil
+		}
+		buf.WriteString("EXCLUDED.")
+		buf.WriteString(d.QuoteIdent(column))
+		return nil
+	})
+}
@@ -0,0 +1,112 @@
+package dbr
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"sync"
+)
+
+// DSNProvider returns the DSN for a new connection, which may have the
+// short-lived credentials like AWS IAM auth tokens or Vault leases.
+type DSNProvider func(ctx context.Context) (string, error)
+
+// OpenConnector creates a Connection with connector of driver, like the
+// connectors of the drivers updating the credentials before connecting.
+// log can be nil to ignore logging.
+func OpenConnector(driver string, connector driver.Connector, log EventReceiver) (*Connection, error) {
+	d, err := dialectOf(driver)
+	if err != nil {
+		return nil, err
+	}
+	if log == nil {
+		log = nullReceiver
+	}
+	return &Connection{DB: sql.OpenDB(connector), EventReceiver: log, Dialect: d, connector: connector}, nil
+}
+
+// OpenDSNProvider creates a Connection, which gets the DSN from provider for
+// each new connection, so that the credentials can rotate without restarting.
+// The open connections keep working with the old credentials, so
+// SetConnMaxLifetime should be shorter than their lifetime.
+// log can be nil to ignore logging.
+func OpenDSNProvider(driver string, provider DSNProvider, log EventReceiver) (*Connection, error) {
+	// the driver is only available from sql.DB
+	db, err := sql.Open(driver, "")
+	if err != nil {
+		return nil, err
+	}
+	drv := db.Driver()
+	db.Close()
+	return OpenConnector(driver, newDSNConnector(drv, provider), log)
+}
+
+// dsnConnector connects with the DSN of provider.
+type dsnConnector struct {
+	driver   driver.Driver
+	provider DSNProvider
+
+	mu        sync.Mutex
+	dsn       string
+	connector driver.Connector
+}
+
+func newDSNConnector(drv driver.Driver, provider DSNProvider) *dsnConnector {
+	return &dsnConnector{driver: drv, provider: provider}
+}
+
+// Connect implements driver.Connector.
+func (c *dsnConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	dsn, err := c.provider(ctx)
+	if err != nil {
+		return nil, err
+	}
+	connector, err := c.connectorOf(dsn)
+	if err != nil {
+		return nil, err
+	}
+	return connector.Connect(ctx)
+}
+
+// connectorOf returns the connector of dsn,
+// which is reused until the DSN changes.
+func (c *dsnConnector) connectorOf(dsn string) (driver.Connector, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.connector != nil && c.dsn == dsn {
+		return c.connector, nil
+	}
+	connector, err := connectorOf(c.driver, dsn)
+	if err != nil {
+		return nil, err
+	}
+	c.dsn, c.connector = dsn, connector
+	return connector, nil
+}
+
+// connectorOf returns the connector of drv connecting to dsn.
+func connectorOf(drv driver.Driver, dsn string) (driver.Connector, error) {
+	if dc, ok := drv.(driver.DriverContext); ok {
+		return dc.OpenConnector(dsn)
+	}
+	return dsnOpener{driver: drv, dsn: dsn}, nil
+}
+
+// Driver implements driver.Connector.
+func (c *dsnConnector) Driver() driver.Driver {
+	return c.driver
+}
+
+// dsnOpener is the connector of the drivers without driver.DriverContext.
+type dsnOpener struct {
+	driver driver.Driver
+	dsn    string
+}
+
+func (o dsnOpener) Connect(context.Context) (driver.Conn, error) {
+	return o.driver.Open(o.dsn)
+}
+
+func (o dsnOpener) Driver() driver.Driver {
+	return o.driver
+}
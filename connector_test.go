@@ -0,0 +1,42 @@
+package dbr
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/jiyeyuran/dbr/v2/dialect"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDSNProvider(t *testing.T) {
+	dbA, mockA, err := sqlmock.NewWithDSN("dsn-a")
+	require.NoError(t, err)
+	_, mockB, err := sqlmock.NewWithDSN("dsn-b")
+	require.NoError(t, err)
+
+	dsn := "dsn-a"
+	db := sql.OpenDB(newDSNConnector(dbA.Driver(), func(ctx context.Context) (string, error) {
+		return dsn, nil
+	}))
+	db.SetMaxIdleConns(0)
+	conn := &Connection{DB: db, EventReceiver: &NullEventReceiver{}, Dialect: dialect.MySQL}
+	sess := conn.NewSession(nil)
+
+	mockA.ExpectExec("DELETE FROM `users`").WillReturnResult(sqlmock.NewResult(0, 1))
+	_, err = sess.DeleteFrom("users").Exec()
+	require.NoError(t, err)
+
+	// the new connections use the rotated dsn
+	dsn = "dsn-b"
+	mockB.ExpectExec("DELETE FROM `users`").WillReturnResult(sqlmock.NewResult(0, 1))
+	_, err = sess.DeleteFrom("users").Exec()
+	require.NoError(t, err)
+
+	require.NoError(t, mockA.ExpectationsWereMet())
+	require.NoError(t, mockB.ExpectationsWereMet())
+
+	_, err = OpenDSNProvider("unknown", nil, nil)
+	require.Error(t, err)
+}
@@ -4,7 +4,10 @@ package dbr
 import (
 	"context"
 	"database/sql"
+	"database/sql/driver"
 	"fmt"
+	"reflect"
+	"sync"
 	"time"
 
 	"github.com/jiyeyuran/dbr/v2/dialect"
@@ -20,20 +23,36 @@ func Open(driver, dsn string, log EventReceiver) (*Connection, error) {
 	if err != nil {
 		return nil, err
 	}
-	var d Dialect
+	d, err := dialectOf(driver)
+	if err != nil {
+		return nil, err
+	}
+	connector, err := connectorOf(conn.Driver(), dsn)
+	if err != nil {
+		return nil, err
+	}
+	return &Connection{DB: conn, EventReceiver: log, Dialect: d, connector: connector}, nil
+}
+
+// dialectOf returns the Dialect of driver.
+func dialectOf(driver string) (Dialect, error) {
 	switch driver {
 	case "mysql":
-		d = dialect.MySQL
+		return dialect.MySQL, nil
 	case "postgres", "pgx":
-		d = dialect.PostgreSQL
+		return dialect.PostgreSQL, nil
 	case "sqlite3":
-		d = dialect.SQLite3
-	case "mssql":
-		d = dialect.MSSQL
-	default:
-		return nil, ErrNotSupported
+		return dialect.SQLite3, nil
+	case "mssql", "sqlserver":
+		return dialect.MSSQL, nil
+	case "godror", "oracle":
+		return dialect.Oracle, nil
+	case "snowflake":
+		return dialect.Snowflake, nil
+	case "bigquery":
+		return dialect.BigQuery, nil
 	}
-	return &Connection{DB: conn, EventReceiver: log, Dialect: d}, nil
+	return nil, ErrNotSupported
 }
 
 const (
@@ -46,6 +65,42 @@ type Connection struct {
 	*sql.DB
 	Dialect
 	EventReceiver
+	// NameMapper maps the names of struct fields without tag to columns,
+	// like SnakeCase, CamelCase or LowerCase. NameMapping is used if nil.
+	NameMapper func(fieldName string) string
+	// TagName is the struct tag of column names and options, "db" by
+	// default, like `db:"name,omitempty"`. It can be changed to keep the
+	// tags of other libraries.
+	//
+	// The tag options are:
+	//
+	//	omitempty      skip the zero field in InsertStmt.Record
+	//	autoincrement  set the field to the generated id after insert
+	//	pk             the primary key, which is the WHERE of UpdateRecord
+	//	key            the map key of LoadMap
+	//	readonly       only load the field, like generated columns
+	//	prefix         prefix the columns of nested structs and ColumnsValuer
+	//	               with the field name and underscore, or like `prefix=author_`
+	TagName string
+	// Replicas routes the reads to the replicas if set. See OpenCluster.
+	Replicas *ReplicaPool
+	// Sharder picks the shards for Session.WithShardKey. A connection
+	// only routing to the shards can have no DB.
+	Sharder Sharder
+
+	stmts *stmtCache
+	// connector connects to the database for the pools of Session.Init.
+	connector driver.Connector
+	initMu    sync.Mutex
+	initDBs   map[string]*sql.DB
+}
+
+func (conn *Connection) nameMapper() func(string) string {
+	return conn.NameMapper
+}
+
+func (conn *Connection) tagName() string {
+	return conn.TagName
 }
 
 // Session represents a business unit of execution.
@@ -57,10 +112,75 @@ type Connection struct {
 // A custom EventReceiver can be set.
 //
 // Timeout specifies max duration for an operation like Select.
+//
+// Strict makes Load return *UnmappedColumnsError if any column has no
+// struct field, instead of discarding it, which is useful in tests.
+//
+// Time controls time.Time of the queries.
+//
+// CommentTags returns the tags appended to the queries as a comment, like
+// the route and traceparent of ctx. See CommentTag of the statements.
+//
+// Retry retries the queries that fail with transient errors.
+//
+// Watchdog reports and stops the queries running too long.
+//
+// Init are the statements like SET time_zone = '+00:00' to set up the
+// server variables of the session. The session with Init uses a pool of its
+// own, which runs Init once on each new connection, so the other sessions
+// never see the variables. The sessions with the same Init share the pool,
+// which has the max open connections of the Connection when it is created.
+// Init needs a Connection of Open or OpenConnector.
+//
+// Schema qualifies the tables of the statements built by the session.
+// See WithSchema.
 type Session struct {
 	*Connection
 	EventReceiver
-	Timeout time.Duration
+	Timeout     time.Duration
+	Strict      bool
+	Time        TimeOptions
+	CommentTags func(ctx context.Context) map[string]string
+	Retry       RetryPolicy
+	Watchdog    Watchdog
+	Init        []string
+	Schema      string
+}
+
+func (sess *Session) watchdog() *Watchdog {
+	if sess.Watchdog.Threshold <= 0 {
+		return nil
+	}
+	return &sess.Watchdog
+}
+
+func (sess *Session) retryPolicy() *RetryPolicy {
+	if sess.Retry.MaxAttempts < 2 {
+		return nil
+	}
+	return &sess.Retry
+}
+
+func (sess *Session) strict() bool {
+	return sess.Strict
+}
+
+func (sess *Session) commentTags(ctx context.Context) map[string]string {
+	if sess.CommentTags == nil {
+		return nil
+	}
+	return sess.CommentTags(ctx)
+}
+
+func (sess *Session) schema() string {
+	return sess.Schema
+}
+
+func (sess *Session) timeOptions() *TimeOptions {
+	if sess.Time.isZero() {
+		return nil
+	}
+	return &sess.Time
 }
 
 // GetTimeout returns current timeout enforced in session.
@@ -77,6 +197,22 @@ func (conn *Connection) NewSession(log EventReceiver) *Session {
 	return &Session{Connection: conn, EventReceiver: log}
 }
 
+// clone returns a session of conn with the settings of sess.
+func (sess *Session) clone(conn *Connection) *Session {
+	return &Session{
+		Connection:    conn,
+		EventReceiver: sess.EventReceiver,
+		Timeout:       sess.Timeout,
+		Strict:        sess.Strict,
+		Time:          sess.Time,
+		CommentTags:   sess.CommentTags,
+		Retry:         sess.Retry,
+		Watchdog:      sess.Watchdog,
+		Init:          sess.Init,
+		Schema:        sess.Schema,
+	}
+}
+
 // Ensure that tx and session are session runner
 var (
 	_ SessionRunner = (*Tx)(nil)
@@ -105,21 +241,32 @@ type runner interface {
 	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
 }
 
-func exec(ctx context.Context, runner runner, log EventReceiver, builder Builder, d Dialect) (sql.Result, error) {
-	timeout := runner.GetTimeout()
-	if timeout > 0 {
-		var cancel func()
-		ctx, cancel = context.WithTimeout(ctx, timeout)
-		defer cancel()
+func exec(ctx context.Context, runner runner, log EventReceiver, builder Builder, d Dialect) (*Result, error) {
+	ctx, cancel, err := withTimeout(ctx, runner, log, builder, d)
+	if err != nil {
+		return nil, err
 	}
+	defer cancel()
+	ctx, stop := startWatch(ctx, runner, log, d)
+	defer stop()
 
 	i := interpolator{
 		Buffer:       NewBuffer(),
 		Dialect:      d,
 		IgnoreBinary: true,
+		Prepared:     stmtCacheOf(runner) != nil,
+		Time:         timeOptionsOf(runner),
 	}
-	err := i.encodePlaceholder(builder, true)
+	err = i.encodePlaceholder(builder, true)
 	query, value := i.String(), i.Value()
+	if err == nil {
+		err = checkPlaceholders(d, len(value))
+	}
+	query, varying := appendCommentTags(ctx, runner, builder, query)
+	if varying {
+		ctx = skipStmtCache(ctx)
+	}
+	query = watchQuery(ctx, query)
 	if err != nil {
 		return nil, log.EventErrKv("dbr.exec.interpolate", err, kvs{
 			"sql":  query,
@@ -140,29 +287,63 @@ func exec(ctx context.Context, runner runner, log EventReceiver, builder Builder
 		defer traceImpl.SpanFinish(ctx)
 	}
 
-	result, err := runner.ExecContext(ctx, query, value...)
+	var result sql.Result
+	attempts, err := retryQuery(ctx, runner, log, builder, query, func() (err error) {
+		result, err = execStmt(ctx, runner, query, value)
+		return
+	})
 	if err != nil {
 		if hasTracingImpl {
 			traceImpl.SpanError(ctx, err)
 		}
-		return result, log.EventErrKv("dbr.exec.exec", err, kvs{
+		return nil, log.EventErrKv("dbr.exec.exec", err, kvs{
 			"sql": query,
 		})
 	}
-	return result, nil
+	if attempts == 1 {
+		// the statement is not retried, but may be in a retried transaction
+		attempts = attemptsOf(runner)
+	}
+	return &Result{
+		Result:   result,
+		SQL:      query,
+		Duration: time.Since(startTime),
+		Attempts: attempts,
+	}, nil
+}
+
+// checkPlaceholders fails fast if the query has more placeholders
+// than the dialect allows.
+func checkPlaceholders(d Dialect, n int) error {
+	max := dialect.CapabilitiesOf(d).MaxPlaceholders
+	if max > 0 && n > max {
+		return errDialectNotSupported(fmt.Sprintf("%d placeholders, the limit is %d", n, max))
+	}
+	return nil
 }
 
 func queryRows(ctx context.Context, runner runner, log EventReceiver, builder Builder, d Dialect) (string, *sql.Rows, error) {
 	// discard the timeout set in the runner, the context should not be canceled
 	// implicitly here but explicitly by the caller since the returned *sql.Rows
 	// may still listening to the context
+	runner = routeRead(runner, builder)
 	i := interpolator{
 		Buffer:       NewBuffer(),
 		Dialect:      d,
 		IgnoreBinary: true,
+		Prepared:     stmtCacheOf(runner) != nil,
+		Time:         timeOptionsOf(runner),
 	}
 	err := i.encodePlaceholder(builder, true)
 	query, value := i.String(), i.Value()
+	if err == nil {
+		err = checkPlaceholders(d, len(value))
+	}
+	query, varying := appendCommentTags(ctx, runner, builder, query)
+	if varying {
+		ctx = skipStmtCache(ctx)
+	}
+	query = watchQuery(ctx, query)
 	if err != nil {
 		return query, nil, log.EventErrKv("dbr.select.interpolate", err, kvs{
 			"sql":  query,
@@ -183,7 +364,11 @@ func queryRows(ctx context.Context, runner runner, log EventReceiver, builder Bu
 		defer traceImpl.SpanFinish(ctx)
 	}
 
-	rows, err := runner.QueryContext(ctx, query, value...)
+	var rows *sql.Rows
+	_, err = retryQuery(ctx, runner, log, builder, query, func() (err error) {
+		rows, err = queryStmt(ctx, runner, query, value)
+		return
+	})
 	if err != nil {
 		if hasTracingImpl {
 			traceImpl.SpanError(ctx, err)
@@ -197,22 +382,89 @@ func queryRows(ctx context.Context, runner runner, log EventReceiver, builder Bu
 }
 
 func query(ctx context.Context, runner runner, log EventReceiver, builder Builder, d Dialect, dest interface{}) (int, error) {
-	timeout := runner.GetTimeout()
-	if timeout > 0 {
-		var cancel func()
-		ctx, cancel = context.WithTimeout(ctx, timeout)
-		defer cancel()
+	var count int
+	err := queryWith(ctx, runner, log, builder, d, func(rows *sql.Rows) (err error) {
+		count, err = load(rows, dest, newTagStoreFor(runner))
+		return
+	})
+	if err != nil {
+		return 0, err
 	}
+	return count, nil
+}
+
+func queryMaps(ctx context.Context, runner runner, log EventReceiver, builder Builder, d Dialect) ([]map[string]interface{}, error) {
+	var m []map[string]interface{}
+	err := queryWith(ctx, runner, log, builder, d, func(rows *sql.Rows) (err error) {
+		m, err = loadMaps(rows, timeOptionsOf(runner))
+		return
+	})
+	if err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// queryWith executes the query with the timeout of runner,
+// and loads the rows with fn, which closes the rows.
+func queryWith(ctx context.Context, runner runner, log EventReceiver, builder Builder, d Dialect, fn func(rows *sql.Rows) error) error {
+	runner = routeRead(runner, builder)
+	ctx, cancel, err := withTimeout(ctx, runner, log, builder, d)
+	if err != nil {
+		return err
+	}
+	defer cancel()
+	ctx, stop := startWatch(ctx, runner, log, d)
+	defer stop()
 
 	query, rows, err := queryRows(ctx, runner, log, builder, d)
 	if err != nil {
-		return 0, err
+		return err
 	}
-	count, err := Load(rows, dest)
+	err = fn(rows)
 	if err != nil {
-		return 0, log.EventErrKv("dbr.select.load.scan", err, kvs{
+		return log.EventErrKv("dbr.select.load.scan", err, kvs{
 			"sql": query,
 		})
 	}
-	return count, nil
+	return nil
+}
+
+// returningResult is the result of exec with returning columns loaded into records.
+type returningResult int64
+
+func (r returningResult) LastInsertId() (int64, error) {
+	return 0, ErrNotSupported
+}
+
+func (r returningResult) RowsAffected() (int64, error) {
+	return int64(r), nil
+}
+
+func execReturning(ctx context.Context, runner runner, log EventReceiver, builder Builder, d Dialect, records []reflect.Value) (*Result, error) {
+	ctx, cancel, err := withTimeout(ctx, runner, log, builder, d)
+	if err != nil {
+		return nil, err
+	}
+	defer cancel()
+	ctx, stop := startWatch(ctx, runner, log, d)
+	defer stop()
+
+	startTime := time.Now()
+	query, rows, err := queryRows(ctx, runner, log, builder, d)
+	if err != nil {
+		return nil, err
+	}
+	count, err := loadRecords(rows, records, newTagStoreFor(runner))
+	if err != nil {
+		return nil, log.EventErrKv("dbr.exec.load.scan", err, kvs{
+			"sql": query,
+		})
+	}
+	return &Result{
+		Result:   returningResult(count),
+		SQL:      query,
+		Duration: time.Since(startTime),
+		Attempts: attemptsOf(runner),
+	}, nil
 }
@@ -0,0 +1,172 @@
+// Package dbrbson stores the null types of dbr as BSON null or value.
+// It is a separate module, so that dbr does not depend on the mongo driver.
+package dbrbson
+
+import (
+	"database/sql"
+	"fmt"
+	"math"
+	"reflect"
+	"time"
+
+	"github.com/jiyeyuran/dbr/v2"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsoncodec"
+	"go.mongodb.org/mongo-driver/bson/bsonrw"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+)
+
+// NewRegistry returns the default registry of bson with the codecs of the
+// null types, which can be set in the client options of mongo.
+func NewRegistry() *bsoncodec.Registry {
+	r := bson.NewRegistry()
+	Register(r)
+	return r
+}
+
+// Register registers the codecs of the null types in r.
+func Register(r *bsoncodec.Registry) {
+	for t, c := range codecs {
+		r.RegisterTypeEncoder(t, c)
+		r.RegisterTypeDecoder(t, c)
+	}
+}
+
+// nullCodec encodes a null type as BSON null or its value,
+// and decodes the value into dest before it is scanned.
+type nullCodec struct {
+	// value returns the value of the null type, or false if it is NULL
+	value func(n interface{}) (interface{}, bool, error)
+	dest  reflect.Type
+}
+
+var codecs = map[reflect.Type]nullCodec{
+	reflect.TypeOf(dbr.NullString{}): {
+		value: func(n interface{}) (interface{}, bool, error) {
+			v := n.(dbr.NullString)
+			return v.String, v.Valid, nil
+		},
+		dest: reflect.TypeOf(""),
+	},
+	reflect.TypeOf(dbr.NullInt64{}): {
+		value: func(n interface{}) (interface{}, bool, error) {
+			v := n.(dbr.NullInt64)
+			return v.Int64, v.Valid, nil
+		},
+		dest: reflect.TypeOf(int64(0)),
+	},
+	reflect.TypeOf(dbr.NullInt32{}): {
+		value: func(n interface{}) (interface{}, bool, error) {
+			v := n.(dbr.NullInt32)
+			return v.Int32, v.Valid, nil
+		},
+		dest: reflect.TypeOf(int64(0)),
+	},
+	reflect.TypeOf(dbr.NullInt16{}): {
+		value: func(n interface{}) (interface{}, bool, error) {
+			v := n.(dbr.NullInt16)
+			return int32(v.Int16), v.Valid, nil
+		},
+		dest: reflect.TypeOf(int64(0)),
+	},
+	reflect.TypeOf(dbr.NullByte{}): {
+		value: func(n interface{}) (interface{}, bool, error) {
+			v := n.(dbr.NullByte)
+			return int32(v.Byte), v.Valid, nil
+		},
+		dest: reflect.TypeOf(int64(0)),
+	},
+	// BSON has no unsigned integer, so the value must not overflow int64.
+	reflect.TypeOf(dbr.NullUint64{}): {
+		value: func(n interface{}) (interface{}, bool, error) {
+			v := n.(dbr.NullUint64)
+			if v.Valid && v.Uint64 > math.MaxInt64 {
+				return nil, false, fmt.Errorf("dbrbson: %d overflows BSON int64", v.Uint64)
+			}
+			return int64(v.Uint64), v.Valid, nil
+		},
+		dest: reflect.TypeOf(int64(0)),
+	},
+	reflect.TypeOf(dbr.NullUint32{}): {
+		value: func(n interface{}) (interface{}, bool, error) {
+			v := n.(dbr.NullUint32)
+			return int64(v.Uint32), v.Valid, nil
+		},
+		dest: reflect.TypeOf(int64(0)),
+	},
+	reflect.TypeOf(dbr.NullBytes{}): {
+		value: func(n interface{}) (interface{}, bool, error) {
+			v := n.(dbr.NullBytes)
+			return v.Bytes, v.Valid, nil
+		},
+		dest: reflect.TypeOf([]byte(nil)),
+	},
+	reflect.TypeOf(dbr.NullFloat64{}): {
+		value: func(n interface{}) (interface{}, bool, error) {
+			v := n.(dbr.NullFloat64)
+			return v.Float64, v.Valid, nil
+		},
+		dest: reflect.TypeOf(float64(0)),
+	},
+	// BSON datetime has millisecond precision.
+	reflect.TypeOf(dbr.NullTime{}): {
+		value: func(n interface{}) (interface{}, bool, error) {
+			v := n.(dbr.NullTime)
+			return v.Time, v.Valid, nil
+		},
+		dest: reflect.TypeOf(time.Time{}),
+	},
+	reflect.TypeOf(dbr.NullBool{}): {
+		value: func(n interface{}) (interface{}, bool, error) {
+			v := n.(dbr.NullBool)
+			return v.Bool, v.Valid, nil
+		},
+		dest: reflect.TypeOf(false),
+	},
+}
+
+// EncodeValue implements bsoncodec.ValueEncoder.
+func (c nullCodec) EncodeValue(ec bsoncodec.EncodeContext, vw bsonrw.ValueWriter, val reflect.Value) error {
+	v, valid, err := c.value(val.Interface())
+	if err != nil {
+		return err
+	}
+	if !valid {
+		return vw.WriteNull()
+	}
+	rv := reflect.ValueOf(v)
+	enc, err := ec.LookupEncoder(rv.Type())
+	if err != nil {
+		return err
+	}
+	return enc.EncodeValue(ec, vw, rv)
+}
+
+// DecodeValue implements bsoncodec.ValueDecoder.
+func (c nullCodec) DecodeValue(dc bsoncodec.DecodeContext, vr bsonrw.ValueReader, val reflect.Value) error {
+	if !val.CanAddr() {
+		return bsoncodec.ValueDecoderError{Name: "nullCodec", Types: []reflect.Type{val.Type()}, Received: val}
+	}
+	s := val.Addr().Interface().(sql.Scanner)
+	switch vr.Type() {
+	case bsontype.Null:
+		if err := vr.ReadNull(); err != nil {
+			return err
+		}
+		return s.Scan(nil)
+	case bsontype.Undefined:
+		if err := vr.ReadUndefined(); err != nil {
+			return err
+		}
+		return s.Scan(nil)
+	}
+	dest := reflect.New(c.dest).Elem()
+	dec, err := dc.LookupDecoder(c.dest)
+	if err != nil {
+		return err
+	}
+	if err := dec.DecodeValue(dc, vr, dest); err != nil {
+		return err
+	}
+	return s.Scan(dest.Interface())
+}
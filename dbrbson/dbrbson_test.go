@@ -0,0 +1,83 @@
+package dbrbson
+
+import (
+	"bytes"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/jiyeyuran/dbr/v2"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsonrw"
+)
+
+func marshal(v interface{}) ([]byte, error) {
+	buf := new(bytes.Buffer)
+	vw, err := bsonrw.NewBSONValueWriter(buf)
+	if err != nil {
+		return nil, err
+	}
+	enc, err := bson.NewEncoder(vw)
+	if err != nil {
+		return nil, err
+	}
+	err = enc.SetRegistry(NewRegistry())
+	if err != nil {
+		return nil, err
+	}
+	err = enc.Encode(v)
+	return buf.Bytes(), err
+}
+
+func unmarshal(b []byte, v interface{}) error {
+	dec, err := bson.NewDecoder(bsonrw.NewBSONDocumentReader(b))
+	if err != nil {
+		return err
+	}
+	err = dec.SetRegistry(NewRegistry())
+	if err != nil {
+		return err
+	}
+	return dec.Decode(v)
+}
+
+func TestNullTypesBSON(t *testing.T) {
+	type record struct {
+		String  dbr.NullString  `bson:"string"`
+		Int64   dbr.NullInt64   `bson:"int64"`
+		Int16   dbr.NullInt16   `bson:"int16"`
+		Uint64  dbr.NullUint64  `bson:"uint64"`
+		Bytes   dbr.NullBytes   `bson:"bytes"`
+		Float64 dbr.NullFloat64 `bson:"float64"`
+		Time    dbr.NullTime    `bson:"time"`
+		Bool    dbr.NullBool    `bson:"bool"`
+	}
+	in := record{
+		String:  dbr.NewNullString("wow"),
+		Int64:   dbr.NewNullInt64(1483272000),
+		Int16:   dbr.NewNullInt16(int64(-16)),
+		Uint64:  dbr.NewNullUint64(int64(64)),
+		Bytes:   dbr.NewNullBytes([]byte{1, 2}),
+		Float64: dbr.NewNullFloat64(1.618),
+		Time:    dbr.NewNullTime(time.Date(2009, 1, 3, 18, 15, 5, 0, time.UTC)),
+	}
+
+	b, err := marshal(in)
+	require.NoError(t, err)
+
+	var raw bson.M
+	require.NoError(t, bson.Unmarshal(b, &raw))
+	require.Equal(t, "wow", raw["string"])
+	require.Equal(t, int32(-16), raw["int16"])
+	require.Nil(t, raw["bool"])
+	require.Contains(t, raw, "bool")
+
+	var out record
+	require.NoError(t, unmarshal(b, &out))
+	out.Time.Time = out.Time.Time.UTC()
+	require.Equal(t, in, out)
+
+	_, err = marshal(struct{ N dbr.NullUint64 }{dbr.NullUint64{Uint64: math.MaxUint64, Valid: true}})
+	require.Error(t, err)
+}
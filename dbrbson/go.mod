@@ -0,0 +1,17 @@
+module github.com/jiyeyuran/dbr/v2/dbrbson
+
+go 1.18
+
+require (
+	github.com/jiyeyuran/dbr/v2 v2.0.0
+	github.com/stretchr/testify v1.4.0
+	go.mongodb.org/mongo-driver v1.17.6
+)
+
+require (
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	gopkg.in/yaml.v2 v2.2.2 // indirect
+)
+
+replace github.com/jiyeyuran/dbr/v2 => ../
@@ -0,0 +1,289 @@
+package dbr
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// Decimal is an exact fixed-point number for DECIMAL and NUMERIC columns,
+// which is the unscaled integer times 10^-scale.
+// The zero value is 0. Division is not provided, because it is not exact.
+type Decimal struct {
+	unscaled *big.Int
+	scale    int32
+}
+
+// NewDecimal creates a Decimal of unscaled * 10^-scale,
+// e.g. NewDecimal(12345, 2) is 123.45.
+func NewDecimal(unscaled int64, scale int32) Decimal {
+	return Decimal{unscaled: big.NewInt(unscaled), scale: scale}
+}
+
+// maxDecimalScale bounds the exponent and the scale of ParseDecimal,
+// so that a short string like "1e2000000000" cannot allocate a huge number.
+// It is more than the digits of DECIMAL in the databases,
+// like 16383 digits after the decimal point in postgres.
+const maxDecimalScale = 1 << 15
+
+// ParseDecimal parses s like "-123.45" or "1.2e-3" into a Decimal.
+// The exponent and the resulting scale are at most 32768 in magnitude.
+func ParseDecimal(s string) (Decimal, error) {
+	orig := s
+	var exp int64
+	if i := strings.IndexAny(s, "eE"); i >= 0 {
+		var err error
+		exp, err = strconv.ParseInt(s[i+1:], 10, 32)
+		if err != nil || exp > maxDecimalScale || exp < -maxDecimalScale {
+			return Decimal{}, fmt.Errorf("%w: %q", ErrInvalidDecimal, orig)
+		}
+		s = s[:i]
+	}
+	var scale int64
+	if i := strings.IndexByte(s, '.'); i >= 0 {
+		scale = int64(len(s) - i - 1)
+		s = s[:i] + s[i+1:]
+	}
+	digits := strings.TrimLeft(s, "+-")
+	if digits == "" || len(s)-len(digits) > 1 || strings.Trim(digits, "0123456789") != "" {
+		return Decimal{}, fmt.Errorf("%w: %q", ErrInvalidDecimal, orig)
+	}
+	unscaled, ok := new(big.Int).SetString(s, 10)
+	if !ok {
+		return Decimal{}, fmt.Errorf("%w: %q", ErrInvalidDecimal, orig)
+	}
+	d := Decimal{unscaled: unscaled}
+	scale -= exp
+	if scale > maxDecimalScale || scale < -maxDecimalScale {
+		return Decimal{}, fmt.Errorf("%w: %q", ErrInvalidDecimal, orig)
+	}
+	if scale < 0 {
+		// 1.2e3 is 1200 with scale 0
+		d.unscaled.Mul(d.unscaled, pow10(-scale))
+		scale = 0
+	}
+	d.scale = int32(scale)
+	return d, nil
+}
+
+func pow10(n int64) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(n), nil)
+}
+
+func (d Decimal) int() *big.Int {
+	if d.unscaled == nil {
+		return new(big.Int)
+	}
+	return d.unscaled
+}
+
+// Scale returns the number of digits after the decimal point.
+func (d Decimal) Scale() int32 {
+	return d.scale
+}
+
+// rescale returns the unscaled integer of d with a larger scale.
+func (d Decimal) rescale(scale int32) *big.Int {
+	if scale == d.scale {
+		return d.int()
+	}
+	return new(big.Int).Mul(d.int(), pow10(int64(scale-d.scale)))
+}
+
+func maxScale(a, b Decimal) int32 {
+	if a.scale > b.scale {
+		return a.scale
+	}
+	return b.scale
+}
+
+// Add returns d + e.
+func (d Decimal) Add(e Decimal) Decimal {
+	scale := maxScale(d, e)
+	return Decimal{unscaled: new(big.Int).Add(d.rescale(scale), e.rescale(scale)), scale: scale}
+}
+
+// Sub returns d - e.
+func (d Decimal) Sub(e Decimal) Decimal {
+	scale := maxScale(d, e)
+	return Decimal{unscaled: new(big.Int).Sub(d.rescale(scale), e.rescale(scale)), scale: scale}
+}
+
+// Mul returns d * e.
+func (d Decimal) Mul(e Decimal) Decimal {
+	return Decimal{unscaled: new(big.Int).Mul(d.int(), e.int()), scale: d.scale + e.scale}
+}
+
+// Neg returns -d.
+func (d Decimal) Neg() Decimal {
+	return Decimal{unscaled: new(big.Int).Neg(d.int()), scale: d.scale}
+}
+
+// Cmp compares d and e, and returns -1, 0 or 1 like big.Int.
+func (d Decimal) Cmp(e Decimal) int {
+	scale := maxScale(d, e)
+	return d.rescale(scale).Cmp(e.rescale(scale))
+}
+
+// Sign returns -1, 0 or 1 for negative, zero or positive d.
+func (d Decimal) Sign() int {
+	return d.int().Sign()
+}
+
+// Float64 returns the nearest float64 of d.
+func (d Decimal) Float64() float64 {
+	f, _ := strconv.ParseFloat(d.String(), 64)
+	return f
+}
+
+// String formats d without exponent, keeping the trailing zeros of the scale.
+func (d Decimal) String() string {
+	s := d.int().String()
+	if d.scale <= 0 {
+		if d.scale < 0 && d.Sign() != 0 {
+			s += strings.Repeat("0", int(-d.scale))
+		}
+		return s
+	}
+	neg := strings.HasPrefix(s, "-")
+	s = strings.TrimPrefix(s, "-")
+	if len(s) <= int(d.scale) {
+		s = strings.Repeat("0", int(d.scale)-len(s)+1) + s
+	}
+	i := len(s) - int(d.scale)
+	s = s[:i] + "." + s[i:]
+	if neg {
+		s = "-" + s
+	}
+	return s
+}
+
+// Scan implements the Scanner interface.
+func (d *Decimal) Scan(value interface{}) error {
+	var s string
+	switch v := value.(type) {
+	case []byte:
+		s = string(v)
+	case string:
+		s = v
+	case int64:
+		*d = NewDecimal(v, 0)
+		return nil
+	case float64:
+		s = strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return fmt.Errorf("%w: cannot scan %T", ErrInvalidDecimal, value)
+	}
+	parsed, err := ParseDecimal(s)
+	if err != nil {
+		return err
+	}
+	*d = parsed
+	return nil
+}
+
+// Value implements the driver Valuer interface.
+// The value is a string, so that no precision is lost in the driver.
+func (d Decimal) Value() (driver.Value, error) {
+	return d.String(), nil
+}
+
+// MarshalJSON serializes a Decimal to JSON string, because JSON numbers
+// are float64 in many languages.
+func (d Decimal) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.String())
+}
+
+// UnmarshalJSON deserializes a Decimal from JSON string or number.
+func (d *Decimal) UnmarshalJSON(b []byte) error {
+	s := strings.Trim(string(b), `"`)
+	parsed, err := ParseDecimal(s)
+	if err != nil {
+		return err
+	}
+	*d = parsed
+	return nil
+}
+
+// NullDecimal is a type that can be null or a Decimal.
+type NullDecimal struct {
+	Decimal Decimal
+	Valid   bool // Valid is true if Decimal is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (n *NullDecimal) Scan(value interface{}) error {
+	if value == nil {
+		n.Decimal, n.Valid = Decimal{}, false
+		return nil
+	}
+	err := n.Decimal.Scan(value)
+	n.Valid = err == nil
+	return err
+}
+
+// Value implements the driver Valuer interface.
+func (n NullDecimal) Value() (driver.Value, error) {
+	if !n.Valid {
+		return nil, nil
+	}
+	return n.Decimal.Value()
+}
+
+// MarshalJSON correctly serializes a NullDecimal to JSON.
+func (n NullDecimal) MarshalJSON() ([]byte, error) {
+	if n.Valid {
+		return n.Decimal.MarshalJSON()
+	}
+	return nullString, nil
+}
+
+// UnmarshalJSON correctly deserializes a NullDecimal from JSON.
+func (n *NullDecimal) UnmarshalJSON(b []byte) error {
+	if bytes.Equal(b, nullString) {
+		return n.Scan(nil)
+	}
+	err := n.Decimal.UnmarshalJSON(b)
+	n.Valid = err == nil
+	return err
+}
+
+// NewNullDecimal creates a NullDecimal with Scan().
+func NewNullDecimal(v interface{}) (n NullDecimal) {
+	n.Scan(v)
+	return
+}
+
+// NullDecimalFrom creates a valid NullDecimal.
+func NullDecimalFrom(v Decimal) NullDecimal {
+	return NullDecimal{Decimal: v, Valid: true}
+}
+
+// NullDecimalFromPtr creates a NullDecimal that is NULL if p is nil.
+func NullDecimalFromPtr(p *Decimal) (n NullDecimal) {
+	if p != nil {
+		n = NullDecimalFrom(*p)
+	}
+	return
+}
+
+// Ptr returns a pointer to the value, or nil if n is NULL.
+func (n NullDecimal) Ptr() *Decimal {
+	if !n.Valid {
+		return nil
+	}
+	v := n.Decimal
+	return &v
+}
+
+// ValueOrZero returns the value, or the zero value if n is NULL.
+func (n NullDecimal) ValueOrZero() Decimal {
+	if !n.Valid {
+		return Decimal{}
+	}
+	return n.Decimal
+}
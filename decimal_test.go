@@ -0,0 +1,99 @@
+package dbr
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/jiyeyuran/dbr/v2/dialect"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDecimal(t *testing.T) {
+	for _, test := range []struct {
+		in   string
+		want string
+	}{
+		{in: "0", want: "0"},
+		{in: "123.4500", want: "123.4500"},
+		{in: "-0.05", want: "-0.05"},
+		{in: "+7", want: "7"},
+		{in: ".5", want: "0.5"},
+		{in: "1.2e3", want: "1200"},
+		{in: "1.2E-3", want: "0.0012"},
+		{in: "18446744073709551616.000000000000000001", want: "18446744073709551616.000000000000000001"},
+	} {
+		d, err := ParseDecimal(test.in)
+		require.NoError(t, err)
+		require.Equal(t, test.want, d.String())
+	}
+
+	for _, in := range []string{"", "-", "1.2.3", "--1", "1e", "abc", "1,5"} {
+		_, err := ParseDecimal(in)
+		require.True(t, errors.Is(err, ErrInvalidDecimal), in)
+	}
+
+	// the exponent and the scale are bounded
+	d, err := ParseDecimal("1e32768")
+	require.NoError(t, err)
+	require.Equal(t, int32(0), d.Scale())
+	d, err = ParseDecimal("1e-32768")
+	require.NoError(t, err)
+	require.Equal(t, int32(32768), d.Scale())
+	for _, in := range []string{"1e2000000000", "1e-2000000000", "1e32769", "0.1e-32768", "1e99999999999"} {
+		_, err := ParseDecimal(in)
+		require.True(t, errors.Is(err, ErrInvalidDecimal), in)
+	}
+	err = json.Unmarshal([]byte("1e2000000000"), &d)
+	require.True(t, errors.Is(err, ErrInvalidDecimal))
+}
+
+func TestDecimalArithmetic(t *testing.T) {
+	a, err := ParseDecimal("0.1")
+	require.NoError(t, err)
+	b, err := ParseDecimal("0.2")
+	require.NoError(t, err)
+
+	require.Equal(t, "0.3", a.Add(b).String())
+	require.Equal(t, "-0.1", a.Sub(b).String())
+	require.Equal(t, "0.02", a.Mul(b).String())
+	require.Equal(t, "-0.1", a.Neg().String())
+	require.Equal(t, -1, a.Cmp(b))
+	require.Equal(t, 0, a.Cmp(NewDecimal(100, 3)))
+	require.Equal(t, 0.1, a.Float64())
+	require.Equal(t, "0", Decimal{}.String())
+	require.Equal(t, "1.23", NewDecimal(123, 2).String())
+	require.Equal(t, "1200", NewDecimal(12, -2).String())
+}
+
+func TestNullDecimal(t *testing.T) {
+	n := NewNullDecimal([]byte("12345678901234567890.12"))
+	require.True(t, n.Valid)
+	v, err := n.Value()
+	require.NoError(t, err)
+	require.Equal(t, "12345678901234567890.12", v)
+
+	query, err := InterpolateForDialect("?", []interface{}{n}, dialect.PostgreSQL)
+	require.NoError(t, err)
+	require.Equal(t, "'12345678901234567890.12'", query)
+
+	require.Equal(t, "3", NewNullDecimal(int64(3)).Decimal.String())
+	require.Equal(t, "1.5", NewNullDecimal(1.5).Decimal.String())
+	require.False(t, NewNullDecimal(nil).Valid)
+	require.False(t, NewNullDecimal("x").Valid)
+
+	var out struct {
+		Price NullDecimal `json:"price"`
+		Tax   NullDecimal `json:"tax"`
+		Fee   NullDecimal `json:"fee"`
+	}
+	err = json.Unmarshal([]byte(`{"price":"9.99","tax":0.75,"fee":null}`), &out)
+	require.NoError(t, err)
+	require.Equal(t, "9.99", out.Price.Decimal.String())
+	require.Equal(t, "0.75", out.Tax.Decimal.String())
+	require.False(t, out.Fee.Valid)
+
+	b, err := json.Marshal(out)
+	require.NoError(t, err)
+	require.Equal(t, `{"price":"9.99","tax":"0.75","fee":null}`, string(b))
+}
@@ -2,8 +2,9 @@ package dbr
 
 import (
 	"context"
-	"database/sql"
-	"strconv"
+	"time"
+
+	"github.com/jiyeyuran/dbr/v2/dialect"
 )
 
 // DeleteStmt builds `DELETE ...`.
@@ -14,11 +15,18 @@ type DeleteStmt struct {
 
 	raw
 
-	Table      string
-	WhereCond  []Builder
-	LimitCount int64
+	Table        string
+	IndexHint    []Builder
+	WhereCond    []Builder
+	ReturnColumn []string
+	Order        []Builder
+	LimitCount   int64
 
 	comments Comments
+	tags     CommentTags
+	joins    []*joinClause
+
+	timeout time.Duration
 }
 
 type DeleteBuilder = DeleteStmt
@@ -37,19 +45,75 @@ func (b *DeleteStmt) Build(d Dialect, buf Buffer) error {
 		return err
 	}
 
-	buf.WriteString("DELETE FROM ")
-	buf.WriteString(d.QuoteIdent(b.Table))
+	whereCond := b.WhereCond
+	joinStyle := dialect.CapabilitiesOf(d).DeleteJoinStyle
+	switch {
+	case len(b.joins) > 0 && (joinStyle == dialect.JoinTable || joinStyle == dialect.JoinFrom):
+		buf.WriteString("DELETE ")
+		buf.WriteString(quoteTable(d, b.runner, b.Table))
+		buildOutput(d, buf, "DELETED", b.ReturnColumn)
+		buf.WriteString(" FROM ")
+		buf.WriteString(quoteTable(d, b.runner, b.Table))
+		err := buildIndexHints(d, buf, b.IndexHint)
+		if err != nil {
+			return err
+		}
+		for _, j := range b.joins {
+			err := j.Build(d, buf)
+			if err != nil {
+				return err
+			}
+		}
+	case len(b.joins) > 0 && joinStyle == dialect.JoinUsing:
+		buf.WriteString("DELETE FROM ")
+		buf.WriteString(quoteTable(d, b.runner, b.Table))
+		buf.WriteString(" USING ")
+		// the join conditions are moved to WHERE
+		var joinCond []Builder
+		for i, j := range b.joins {
+			if i > 0 {
+				buf.WriteString(", ")
+			}
+			j.buildTable(d, buf)
+			if cond := j.cond(); cond != nil {
+				joinCond = append(joinCond, cond)
+			}
+		}
+		whereCond = append(joinCond, whereCond...)
+	case len(b.joins) > 0:
+		return errDialectNotSupported("DELETE with JOIN")
+	case len(b.IndexHint) > 0 && d == dialect.MySQL:
+		// index hints are only allowed in multiple-table syntax
+		buf.WriteString("DELETE ")
+		buf.WriteString(quoteTable(d, b.runner, b.Table))
+		buf.WriteString(" FROM ")
+		buf.WriteString(quoteTable(d, b.runner, b.Table))
+		err := buildIndexHints(d, buf, b.IndexHint)
+		if err != nil {
+			return err
+		}
+	default:
+		buf.WriteString("DELETE FROM ")
+		buf.WriteString(quoteTable(d, b.runner, b.Table))
+		buildOutput(d, buf, "DELETED", b.ReturnColumn)
+	}
 
-	if len(b.WhereCond) > 0 {
+	if len(whereCond) > 0 {
 		buf.WriteString(" WHERE ")
-		err := And(b.WhereCond...).Build(d, buf)
+		err := And(whereCond...).Build(d, buf)
 		if err != nil {
 			return err
 		}
 	}
-	if b.LimitCount >= 0 {
-		buf.WriteString(" LIMIT ")
-		buf.WriteString(strconv.FormatInt(b.LimitCount, 10))
+
+	err = buildReturning(d, buf, b.ReturnColumn)
+	if err != nil {
+		return err
+	}
+
+	err = buildOrderLimit(d, buf, b.Order, b.LimitCount)
+	if err != nil {
+		return err
 	}
 	return nil
 }
@@ -121,6 +185,68 @@ func (b *DeleteStmt) Where(query interface{}, value ...interface{}) *DeleteStmt
 	return b
 }
 
+// UseIndex suggests indexes to use for the table. It is only rendered for MySQL.
+func (b *DeleteStmt) UseIndex(index ...string) *DeleteStmt {
+	b.IndexHint = append(b.IndexHint, indexHint("USE", index))
+	return b
+}
+
+// ForceIndex forces indexes to use for the table. It is only rendered for MySQL.
+func (b *DeleteStmt) ForceIndex(index ...string) *DeleteStmt {
+	b.IndexHint = append(b.IndexHint, indexHint("FORCE", index))
+	return b
+}
+
+// IgnoreIndex prevents indexes from being used for the table. It is only rendered for MySQL.
+func (b *DeleteStmt) IgnoreIndex(index ...string) *DeleteStmt {
+	b.IndexHint = append(b.IndexHint, indexHint("IGNORE", index))
+	return b
+}
+
+// Join deletes the rows of the table that match another table.
+// It builds `DELETE a FROM a JOIN b ON ...` in mysql and mssql,
+// and `DELETE FROM a USING b WHERE ...` in postgres.
+// table can be Builder or string. on can be Builder or string.
+func (b *DeleteStmt) Join(table, on interface{}) *DeleteStmt {
+	b.joins = append(b.joins, &joinClause{table: joinTable(b.runner, table), on: on})
+	return b
+}
+
+// Returning specifies the returning columns for postgres/sqlite/mssql.
+// The deleted rows can be loaded with Load.
+func (b *DeleteStmt) Returning(column ...string) *DeleteStmt {
+	b.ReturnColumn = column
+	return b
+}
+
+// OrderAsc sorts the rows to delete by col in ascending order.
+// It is only supported by mysql and sqlite.
+func (b *DeleteStmt) OrderAsc(col string) *DeleteStmt {
+	b.Order = append(b.Order, order(col, asc))
+	return b
+}
+
+// OrderDesc sorts the rows to delete by col in descending order.
+// It is only supported by mysql and sqlite.
+func (b *DeleteStmt) OrderDesc(col string) *DeleteStmt {
+	b.Order = append(b.Order, order(col, desc))
+	return b
+}
+
+// OrderBy specifies columns for ordering the rows to delete.
+// col can be Builder or string. It is only supported by mysql and sqlite.
+func (b *DeleteStmt) OrderBy(col interface{}) *DeleteStmt {
+	switch col := col.(type) {
+	case string:
+		b.Order = append(b.Order, Expr(col))
+	case Builder:
+		b.Order = append(b.Order, col)
+	}
+	return b
+}
+
+// Limit limits the number of rows to delete.
+// It is only supported by mysql and sqlite.
 func (b *DeleteStmt) Limit(n uint64) *DeleteStmt {
 	b.LimitCount = int64(n)
 	return b
@@ -131,10 +257,39 @@ func (b *DeleteStmt) Comment(comment string) *DeleteStmt {
 	return b
 }
 
-func (b *DeleteStmt) Exec() (sql.Result, error) {
+// CommentTag adds a tag to the comment appended to the query like SelectStmt.CommentTag.
+func (b *DeleteStmt) CommentTag(key, value string) *DeleteStmt {
+	b.tags = b.tags.Add(key, value)
+	return b
+}
+
+func (b *DeleteStmt) commentTags(context.Context) map[string]string {
+	return b.tags
+}
+
+// Timeout cancels the statement after d like SelectStmt.Timeout.
+func (b *DeleteStmt) Timeout(d time.Duration) *DeleteStmt {
+	b.timeout = d
+	return b
+}
+
+func (b *DeleteStmt) statementTimeout() time.Duration {
+	return b.timeout
+}
+
+func (b *DeleteStmt) Exec() (*Result, error) {
 	return b.ExecContext(context.Background())
 }
 
-func (b *DeleteStmt) ExecContext(ctx context.Context) (sql.Result, error) {
+func (b *DeleteStmt) ExecContext(ctx context.Context) (*Result, error) {
 	return exec(ctx, b.runner, b.EventReceiver, b, b.Dialect)
 }
+
+func (b *DeleteStmt) LoadContext(ctx context.Context, value interface{}) error {
+	_, err := query(ctx, b.runner, b.EventReceiver, b, b.Dialect, value)
+	return err
+}
+
+func (b *DeleteStmt) Load(value interface{}) error {
+	return b.LoadContext(context.Background(), value)
+}
@@ -1,6 +1,7 @@
 package dbr
 
 import (
+	"errors"
 	"testing"
 
 	"github.com/jiyeyuran/dbr/v2/dialect"
@@ -22,3 +23,89 @@ func BenchmarkDeleteSQL(b *testing.B) {
 		DeleteFrom("table").Where(Eq("a", 1)).Build(dialect.MySQL, buf)
 	}
 }
+
+func TestDeleteIndexHint(t *testing.T) {
+	buf := NewBuffer()
+	builder := DeleteFrom("table").UseIndex("idx").Where(Eq("a", 1))
+	err := builder.Build(dialect.MySQL, buf)
+	require.NoError(t, err)
+	require.Equal(t, "DELETE `table` FROM `table` USE INDEX (`idx`) WHERE (`a` = ?)", buf.String())
+
+	buf = NewBuffer()
+	err = builder.Build(dialect.SQLite3, buf)
+	require.NoError(t, err)
+	require.Equal(t, `DELETE FROM "table" WHERE ("a" = ?)`, buf.String())
+}
+
+func TestDeleteReturning(t *testing.T) {
+	builder := DeleteFrom("session").Where(Lt("expires_at", 100)).Returning("id", "user_id")
+
+	for _, test := range []struct {
+		dialect Dialect
+		query   string
+	}{
+		{
+			dialect: dialect.PostgreSQL,
+			query:   `DELETE FROM "session" WHERE ("expires_at" < ?) RETURNING "id","user_id"`,
+		},
+		{
+			dialect: dialect.SQLite3,
+			query:   `DELETE FROM "session" WHERE ("expires_at" < ?) RETURNING "id","user_id"`,
+		},
+		{
+			dialect: dialect.MSSQL,
+			query:   `DELETE FROM [session] OUTPUT DELETED.[id],DELETED.[user_id] WHERE ([expires_at] < ?)`,
+		},
+	} {
+		buf := NewBuffer()
+		err := builder.Build(test.dialect, buf)
+		require.NoError(t, err)
+		require.Equal(t, test.query, buf.String())
+		require.Equal(t, []interface{}{100}, buf.Value())
+	}
+}
+
+func TestDeleteJoin(t *testing.T) {
+	builder := DeleteFrom("comment").
+		Join("post", "post.id = comment.post_id").
+		Where(Eq("post.deleted", true))
+
+	for _, test := range []struct {
+		dialect Dialect
+		query   string
+	}{
+		{
+			dialect: dialect.MySQL,
+			query:   "DELETE `comment` FROM `comment` JOIN `post` ON post.id = comment.post_id WHERE (`post`.`deleted` = ?)",
+		},
+		{
+			dialect: dialect.PostgreSQL,
+			query:   `DELETE FROM "comment" USING "post" WHERE (post.id = comment.post_id) AND ("post"."deleted" = ?)`,
+		},
+		{
+			dialect: dialect.MSSQL,
+			query:   `DELETE [comment] FROM [comment] JOIN [post] ON post.id = comment.post_id WHERE ([post].[deleted] = ?)`,
+		},
+	} {
+		buf := NewBuffer()
+		err := builder.Build(test.dialect, buf)
+		require.NoError(t, err)
+		require.Equal(t, test.query, buf.String())
+		require.Equal(t, []interface{}{true}, buf.Value())
+	}
+
+	err := builder.Build(dialect.SQLite3, NewBuffer())
+	require.True(t, errors.Is(err, ErrDialectNotSupported))
+}
+
+func TestDeleteOrderLimit(t *testing.T) {
+	builder := DeleteFrom("log").Where(Lt("created_at", 100)).OrderAsc("created_at").Limit(1000)
+
+	buf := NewBuffer()
+	err := builder.Build(dialect.MySQL, buf)
+	require.NoError(t, err)
+	require.Equal(t, "DELETE FROM `log` WHERE (`created_at` < ?) ORDER BY `created_at` ASC LIMIT 1000", buf.String())
+
+	err = builder.Build(dialect.PostgreSQL, NewBuffer())
+	require.True(t, errors.Is(err, ErrDialectNotSupported))
+}
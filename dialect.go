@@ -1,7 +1,18 @@
 package dbr
 
-import "github.com/jiyeyuran/dbr/v2/dialect"
+import (
+	"database/sql/driver"
+
+	"github.com/jiyeyuran/dbr/v2/dialect"
+)
 
 // Dialect abstracts database driver differences in encoding
 // types, and placeholders.
 type Dialect = dialect.Dialect
+
+// DialectValuer is a driver.Valuer whose value depends on the dialect.
+// DialectValue is preferred to Value when the query is interpolated.
+type DialectValuer interface {
+	driver.Valuer
+	DialectValue(d Dialect) (driver.Value, error)
+}
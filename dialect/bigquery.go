@@ -0,0 +1,57 @@
+package dialect
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+type bigQuery struct{}
+
+// QuoteIdent quotes the whole path like `project.dataset.table`,
+// because project names can contain hyphens.
+func (d bigQuery) QuoteIdent(s string) string {
+	return "`" + strings.Replace(s, "`", "\\`", -1) + "`"
+}
+
+func (d bigQuery) EncodeString(s string) string {
+	s = strings.Replace(s, `\`, `\\`, -1)
+	return `'` + strings.Replace(s, `'`, `\'`, -1) + `'`
+}
+
+func (d bigQuery) EncodeBool(b bool) string {
+	if b {
+		return "TRUE"
+	}
+	return "FALSE"
+}
+
+func (d bigQuery) EncodeTime(t time.Time) string {
+	return `TIMESTAMP '` + t.UTC().Format(timeFormat) + `+00'`
+}
+
+func (d bigQuery) EncodeBytes(b []byte) string {
+	return fmt.Sprintf(`FROM_HEX('%x')`, b)
+}
+
+func (d bigQuery) Placeholder(n int) string {
+	return fmt.Sprintf("@p%d", n+1)
+}
+
+func (d bigQuery) Capabilities() Capabilities {
+	return Capabilities{
+		SupportsCTE:              true,
+		SupportsRecursiveKeyword: true,
+		SupportsTableAliasAs:     true,
+		SupportsRowValues:        true,
+		SupportsFullJoin:         true,
+		UpdateJoinStyle:          JoinUsing,
+		SupportsMerge:            true,
+		SupportsTruncate:         true,
+		SupportsDefault:          true,
+		SupportsDefaultValues:    true,
+		SupportsQualify:          true,
+		ParseJSON:                true,
+		TimePrecision:            time.Microsecond,
+	}
+}
@@ -0,0 +1,199 @@
+package dialect
+
+import "time"
+
+// Capabilities describes the SQL features of a dialect, so that builders
+// can emulate a missing feature or fail fast instead of building invalid SQL.
+type Capabilities struct {
+	// SupportsReturning reports whether `RETURNING ...` is supported.
+	SupportsReturning bool
+	// SupportsOutput reports whether `OUTPUT INSERTED.*` is supported.
+	SupportsOutput bool
+	// SupportsOnConflict reports whether `ON CONFLICT ...` is supported.
+	SupportsOnConflict bool
+	// SupportsOnDuplicateKey reports whether `ON DUPLICATE KEY UPDATE ...` is supported.
+	SupportsOnDuplicateKey bool
+	// SupportsInsertIgnore reports whether `INSERT IGNORE` is supported.
+	SupportsInsertIgnore bool
+	// SupportsInsertOrIgnore reports whether `INSERT OR IGNORE` is supported.
+	SupportsInsertOrIgnore bool
+	// SupportsCTE reports whether `WITH ...` is supported.
+	SupportsCTE bool
+	// SupportsRecursiveKeyword reports whether `WITH RECURSIVE ...` is supported.
+	// Otherwise, recursion is implicit.
+	SupportsRecursiveKeyword bool
+	// SupportsTableAliasAs reports whether AS is allowed before a table alias.
+	SupportsTableAliasAs bool
+	// SupportsDistinctOn reports whether `DISTINCT ON (...)` is supported.
+	SupportsDistinctOn bool
+	// SupportsRowValues reports whether row values like `(a, b) < (?, ?)`
+	// are supported. Otherwise, they are expanded to conditions on each column.
+	SupportsRowValues bool
+	// SupportsFullJoin reports whether `FULL JOIN` is supported.
+	SupportsFullJoin bool
+	// UpdateJoinStyle is how tables are joined in UPDATE.
+	UpdateJoinStyle JoinStyle
+	// DeleteJoinStyle is how tables are joined in DELETE.
+	DeleteJoinStyle JoinStyle
+	// SupportsMerge reports whether `MERGE INTO ...` is supported.
+	SupportsMerge bool
+	// SupportsTruncate reports whether `TRUNCATE TABLE` is supported.
+	SupportsTruncate bool
+	// SupportsDefault reports whether the DEFAULT keyword is supported as a value.
+	SupportsDefault bool
+	// SupportsDefaultValues reports whether `INSERT ... DEFAULT VALUES` is
+	// supported. Otherwise, `() VALUES ()` is built.
+	SupportsDefaultValues bool
+	// SupportsForUpdate reports whether `FOR UPDATE` is supported.
+	SupportsForUpdate bool
+	// SupportsForShare reports whether `FOR SHARE` is supported.
+	SupportsForShare bool
+	// SupportsQualify reports whether `QUALIFY ...` is supported.
+	SupportsQualify bool
+	// SupportsTableSample reports whether `TABLESAMPLE ...` is supported.
+	SupportsTableSample bool
+	// SupportsUpdateLimit reports whether `ORDER BY` and `LIMIT` are supported
+	// in UPDATE and DELETE.
+	SupportsUpdateLimit bool
+	// LastInsertIDIsLastRow reports whether the last insert id of a multi-row
+	// insert is the id of the last row, instead of the first row.
+	LastInsertIDIsLastRow bool
+	// LimitStyle is how the rows of a query are limited.
+	LimitStyle LimitStyle
+	// NullsStyle is how nulls are sorted first or last.
+	NullsStyle NullsStyle
+	// SupportsWithTies reports whether `WITH TIES` is supported in limits.
+	SupportsWithTies bool
+	// MaxPlaceholders is the maximum number of placeholders in a query,
+	// or 0 if there is no known limit.
+	MaxPlaceholders int
+	// ParseJSON reports whether JSON values are built with `PARSE_JSON(...)`,
+	// because strings cannot be assigned to JSON columns.
+	ParseJSON bool
+	// BackslashEscapes reports whether backslashes escape the characters
+	// of string literals.
+	BackslashEscapes bool
+	// DollarQuotes reports whether `$tag$...$tag$` string literals are supported.
+	DollarQuotes bool
+	// HashComments reports whether # starts a line comment,
+	// and -- must be followed by a space to start one.
+	HashComments bool
+	// TwoPhaseStyle is how transactions are committed in two phases.
+	TwoPhaseStyle TwoPhaseStyle
+	// StatementTimeoutStyle is how the timeout of a statement is enforced
+	// by the server, in addition to the canceled context.
+	StatementTimeoutStyle StatementTimeoutStyle
+	// KillStyle is how a slow query is killed from another connection.
+	KillStyle KillStyle
+	// TimePrecision is the precision of the times written by EncodeTime,
+	// or 0 if the times are not truncated.
+	TimePrecision time.Duration
+}
+
+// CapabilityDialect is a Dialect that reports its capabilities.
+type CapabilityDialect interface {
+	Dialect
+	Capabilities() Capabilities
+}
+
+// CapabilitiesOf returns the capabilities of d.
+// A dialect that does not implement CapabilityDialect is assumed to
+// support only the common subset of SQL.
+func CapabilitiesOf(d Dialect) Capabilities {
+	if d, ok := d.(CapabilityDialect); ok {
+		return d.Capabilities()
+	}
+	return Capabilities{
+		SupportsCTE:              true,
+		SupportsRecursiveKeyword: true,
+		SupportsTableAliasAs:     true,
+		SupportsFullJoin:         true,
+		SupportsTruncate:         true,
+		SupportsDefault:          true,
+		SupportsDefaultValues:    true,
+		SupportsRowValues:        true,
+	}
+}
+
+// LimitStyle is how a dialect limits the rows of a query.
+type LimitStyle uint8
+
+const (
+	// LimitOffset builds `LIMIT m OFFSET n`.
+	LimitOffset LimitStyle = iota
+	// OffsetFetch builds standard `OFFSET n ROWS FETCH FIRST m ROWS ONLY`.
+	OffsetFetch
+	// TopOffsetFetch builds `SELECT TOP (m)` without offset, or OffsetFetch
+	// with the required ORDER BY.
+	TopOffsetFetch
+	// RowNum wraps the query with conditions on ROWNUM.
+	RowNum
+)
+
+// TwoPhaseStyle is how a dialect commits transactions in two phases.
+type TwoPhaseStyle uint8
+
+const (
+	// NoTwoPhase does not support two-phase commit.
+	NoTwoPhase TwoPhaseStyle = iota
+	// XA builds `XA START`, `XA PREPARE` and `XA COMMIT`.
+	XA
+	// PrepareTransaction builds `PREPARE TRANSACTION` and `COMMIT PREPARED`.
+	PrepareTransaction
+)
+
+// NullsStyle is how a dialect sorts nulls first or last.
+type NullsStyle uint8
+
+const (
+	// NullsKeyword builds `NULLS FIRST` and `NULLS LAST`.
+	NullsKeyword NullsStyle = iota
+	// NullsIsNull sorts by `ISNULL(column)` first.
+	NullsIsNull
+	// NullsCase sorts by `CASE WHEN column IS NULL THEN 1 ELSE 0 END` first.
+	NullsCase
+)
+
+// JoinStyle is how a dialect joins tables in UPDATE or DELETE.
+type JoinStyle uint8
+
+const (
+	// NoJoin does not support joins.
+	NoJoin JoinStyle = iota
+	// JoinTable joins after the table, like `UPDATE t JOIN u ON ... SET ...`
+	// and `DELETE t FROM t JOIN u ON ...`.
+	JoinTable
+	// JoinFrom joins in FROM, like `UPDATE t SET ... FROM t JOIN u ON ...`
+	// and `DELETE t FROM t JOIN u ON ...`.
+	JoinFrom
+	// JoinUsing lists the joined tables in FROM or USING, and moves the join
+	// conditions to WHERE, like `UPDATE t SET ... FROM u WHERE ...` and
+	// `DELETE FROM t USING u WHERE ...`.
+	JoinUsing
+)
+
+// StatementTimeoutStyle is how a dialect enforces the timeout of a statement.
+type StatementTimeoutStyle uint8
+
+const (
+	// NoStatementTimeout only cancels the context of the statement.
+	NoStatementTimeout StatementTimeoutStyle = iota
+	// MaxExecutionTime adds the `/*+ MAX_EXECUTION_TIME(ms) */` hint to SELECT.
+	MaxExecutionTime
+	// SetStatementTimeout sets statement_timeout locally in a transaction.
+	SetStatementTimeout
+)
+
+// KillStyle is how a dialect kills a query from another connection.
+type KillStyle uint8
+
+const (
+	// NoKill does not support killing queries.
+	NoKill KillStyle = iota
+	// KillQuery finds the query in information_schema.PROCESSLIST,
+	// and runs `KILL QUERY`.
+	KillQuery
+	// CancelBackend finds the query in pg_stat_activity,
+	// and runs pg_cancel_backend.
+	CancelBackend
+)
@@ -0,0 +1,25 @@
+package dialect
+
+import "fmt"
+
+// cockroachDB is mostly postgres.
+type cockroachDB struct {
+	postgreSQL
+}
+
+func (d cockroachDB) EncodeBytes(b []byte) string {
+	return fmt.Sprintf(`x'%x'`, b)
+}
+
+// Capabilities differs from postgres in MERGE, TABLESAMPLE, WITH TIES,
+// two-phase commit, statement timeouts and killing queries.
+func (d cockroachDB) Capabilities() Capabilities {
+	c := d.postgreSQL.Capabilities()
+	c.SupportsMerge = false
+	c.SupportsTableSample = false
+	c.SupportsWithTies = false
+	c.TwoPhaseStyle = NoTwoPhase
+	c.StatementTimeoutStyle = NoStatementTimeout
+	c.KillStyle = NoKill
+	return c
+}
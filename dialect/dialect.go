@@ -14,6 +14,14 @@ var (
 	SQLite3 Dialect = sqlite3{}
 	// MSSQL dialect
 	MSSQL Dialect = mssql{}
+	// Oracle dialect
+	Oracle Dialect = oracle{}
+	// CockroachDB dialect, which is used with postgres drivers.
+	CockroachDB Dialect = cockroachDB{}
+	// Snowflake dialect
+	Snowflake Dialect = snowflake{}
+	// BigQuery dialect of standard SQL
+	BigQuery Dialect = bigQuery{}
 )
 
 const (
@@ -33,10 +41,16 @@ type Dialect interface {
 	Placeholder(n int) string
 }
 
-func quoteIdent(s, quote string) string {
+// QuoteIdent quotes each part of a dotted identifier like `schema.table.column`
+// with quote, which is escaped by doubling. The wildcard `*` is not quoted.
+// It can be used to implement Dialect.
+func QuoteIdent(s, quote string) string {
 	part := strings.SplitN(s, ".", 2)
 	if len(part) == 2 {
-		return quoteIdent(part[0], quote) + "." + quoteIdent(part[1], quote)
+		return QuoteIdent(part[0], quote) + "." + QuoteIdent(part[1], quote)
 	}
-	return quote + s + quote
+	if s == "*" {
+		return s
+	}
+	return quote + strings.Replace(s, quote, quote+quote, -1) + quote
 }
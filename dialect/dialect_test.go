@@ -2,6 +2,7 @@ package dialect
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 )
@@ -19,6 +20,18 @@ func TestMySQL(t *testing.T) {
 			in:   "col",
 			want: "`col`",
 		},
+		{
+			in:   "schema.table.col",
+			want: "`schema`.`table`.`col`",
+		},
+		{
+			in:   "table.*",
+			want: "`table`.*",
+		},
+		{
+			in:   "co`l",
+			want: "`co``l`",
+		},
 	} {
 		require.Equal(t, test.want, MySQL.QuoteIdent(test.in))
 	}
@@ -37,6 +50,10 @@ func TestPostgreSQL(t *testing.T) {
 			in:   "col",
 			want: `"col"`,
 		},
+		{
+			in:   `co"l`,
+			want: `"co""l"`,
+		},
 	} {
 		require.Equal(t, test.want, PostgreSQL.QuoteIdent(test.in))
 	}
@@ -67,13 +84,128 @@ func TestMSSQL(t *testing.T) {
 	}{
 		{
 			in:   "table.col",
-			want: `"table"."col"`,
+			want: "[table].[col]",
 		},
 		{
 			in:   "col",
-			want: `"col"`,
+			want: "[col]",
 		},
 	} {
 		require.Equal(t, test.want, MSSQL.QuoteIdent(test.in))
 	}
+
+	require.Equal(t, "[a]]b]", MSSQL.QuoteIdent("a]b"))
+	require.Equal(t, "[t].*", MSSQL.QuoteIdent("t.*"))
+	require.Equal(t, "'2020-01-02 03:04:05.1230000'", MSSQL.EncodeTime(time.Date(2020, 1, 2, 3, 4, 5, 123000000, time.UTC)))
+	require.Equal(t, "0x0aff", MSSQL.EncodeBytes([]byte{10, 255}))
+}
+
+func TestOracle(t *testing.T) {
+	require.Equal(t, `"table"."col"`, Oracle.QuoteIdent("table.col"))
+	require.Equal(t, `'it''s'`, Oracle.EncodeString("it's"))
+	require.Equal(t, "TO_TIMESTAMP('2020-01-02 03:04:05.000000', 'YYYY-MM-DD HH24:MI:SS.FF6')", Oracle.EncodeTime(time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)))
+	require.Equal(t, "HEXTORAW('0aff')", Oracle.EncodeBytes([]byte{10, 255}))
+	require.Equal(t, ":1", Oracle.Placeholder(0))
+}
+
+func TestSnowflake(t *testing.T) {
+	require.Equal(t, `"Table"."Col"`, Snowflake.QuoteIdent("Table.Col"))
+	require.Equal(t, `'it''s a\\b'`, Snowflake.EncodeString(`it's a\b`))
+	require.Equal(t, "TO_BINARY('0aff', 'HEX')", Snowflake.EncodeBytes([]byte{10, 255}))
+}
+
+func TestBigQuery(t *testing.T) {
+	require.Equal(t, "`my-project.dataset.table`", BigQuery.QuoteIdent("my-project.dataset.table"))
+	require.Equal(t, `'it\'s a\\b'`, BigQuery.EncodeString(`it's a\b`))
+	require.Equal(t, "TIMESTAMP '2020-01-02 03:04:05.000000+00'", BigQuery.EncodeTime(time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)))
+	require.Equal(t, "FROM_HEX('0aff')", BigQuery.EncodeBytes([]byte{10, 255}))
+	require.Equal(t, "@p1", BigQuery.Placeholder(0))
+}
+
+type customDialect struct {
+	sqlite3
+}
+
+func TestCapabilitiesOf(t *testing.T) {
+	require.True(t, CapabilitiesOf(PostgreSQL).SupportsReturning)
+	require.True(t, CapabilitiesOf(PostgreSQL).SupportsMerge)
+	require.False(t, CapabilitiesOf(CockroachDB).SupportsMerge)
+	require.True(t, CapabilitiesOf(CockroachDB).SupportsOnConflict)
+	require.True(t, CapabilitiesOf(MSSQL).SupportsOutput)
+	require.False(t, CapabilitiesOf(MySQL).SupportsReturning)
+	require.Equal(t, 2100, CapabilitiesOf(MSSQL).MaxPlaceholders)
+	require.True(t, CapabilitiesOf(Snowflake).ParseJSON)
+	require.True(t, CapabilitiesOf(CockroachDB).DollarQuotes)
+	require.Equal(t, XA, CapabilitiesOf(MySQL).TwoPhaseStyle)
+	require.Equal(t, PrepareTransaction, CapabilitiesOf(PostgreSQL).TwoPhaseStyle)
+	require.Equal(t, NoTwoPhase, CapabilitiesOf(CockroachDB).TwoPhaseStyle)
+	require.False(t, CapabilitiesOf(MSSQL).SupportsRowValues)
+	require.True(t, CapabilitiesOf(MySQL).SupportsRowValues)
+	require.False(t, CapabilitiesOf(MySQL).SupportsDefaultValues)
+	require.Equal(t, NullsIsNull, CapabilitiesOf(MySQL).NullsStyle)
+	require.Equal(t, NullsCase, CapabilitiesOf(MSSQL).NullsStyle)
+	require.Equal(t, NullsKeyword, CapabilitiesOf(PostgreSQL).NullsStyle)
+	require.Equal(t, JoinTable, CapabilitiesOf(MySQL).UpdateJoinStyle)
+	require.Equal(t, JoinFrom, CapabilitiesOf(MSSQL).DeleteJoinStyle)
+	require.Equal(t, JoinUsing, CapabilitiesOf(CockroachDB).DeleteJoinStyle)
+	require.Equal(t, NoJoin, CapabilitiesOf(SQLite3).DeleteJoinStyle)
+	require.Equal(t, MaxExecutionTime, CapabilitiesOf(MySQL).StatementTimeoutStyle)
+	require.Equal(t, SetStatementTimeout, CapabilitiesOf(PostgreSQL).StatementTimeoutStyle)
+	require.Equal(t, NoStatementTimeout, CapabilitiesOf(CockroachDB).StatementTimeoutStyle)
+	require.Equal(t, KillQuery, CapabilitiesOf(MySQL).KillStyle)
+	require.Equal(t, CancelBackend, CapabilitiesOf(PostgreSQL).KillStyle)
+	require.Equal(t, NoKill, CapabilitiesOf(CockroachDB).KillStyle)
+
+	// a dialect that embeds a built-in one inherits its capabilities
+	require.Equal(t, CapabilitiesOf(SQLite3), CapabilitiesOf(customDialect{}))
+
+	var d struct{ Dialect }
+	d.Dialect = PostgreSQL
+	require.False(t, CapabilitiesOf(d).SupportsReturning)
+	require.True(t, CapabilitiesOf(d).SupportsCTE)
+}
+
+func TestSQLite3Version(t *testing.T) {
+	d, err := SQLite3Version("3.31.1")
+	require.NoError(t, err)
+	require.Equal(t, sqlite3{version: 3031001}, d)
+
+	c := CapabilitiesOf(d)
+	require.True(t, c.SupportsOnConflict)
+	require.Equal(t, NoJoin, c.UpdateJoinStyle)
+	require.False(t, c.SupportsReturning)
+	require.Equal(t, 999, c.MaxPlaceholders)
+	require.Equal(t, "TRUE", d.EncodeBool(true))
+
+	d, err = SQLite3Version("3.22")
+	require.NoError(t, err)
+	require.False(t, CapabilitiesOf(d).SupportsOnConflict)
+	require.Equal(t, "1", d.EncodeBool(true))
+
+	require.True(t, CapabilitiesOf(SQLite3).SupportsReturning)
+	require.Equal(t, "FALSE", SQLite3.EncodeBool(false))
+
+	for _, version := range []string{"", "3.x", "2.8.17", "3.1000"} {
+		_, err := SQLite3Version(version)
+		require.Error(t, err)
+	}
+}
+
+func TestSQLite3EncodeTime(t *testing.T) {
+	loc := time.FixedZone("UTC+8", 8*60*60)
+	require.Equal(t, "'2020-01-01 19:04:05.000000'", SQLite3.EncodeTime(time.Date(2020, 1, 2, 3, 4, 5, 0, loc)))
+}
+
+func TestOracleVersion(t *testing.T) {
+	d, err := OracleVersion("11.2.0.4")
+	require.NoError(t, err)
+	require.Equal(t, RowNum, CapabilitiesOf(d).LimitStyle)
+
+	d, err = OracleVersion("19c")
+	require.NoError(t, err)
+	require.Equal(t, OffsetFetch, CapabilitiesOf(d).LimitStyle)
+	require.Equal(t, OffsetFetch, CapabilitiesOf(Oracle).LimitStyle)
+
+	_, err = OracleVersion("latest")
+	require.Error(t, err)
 }
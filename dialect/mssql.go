@@ -9,7 +9,14 @@ import (
 type mssql struct{}
 
 func (d mssql) QuoteIdent(s string) string {
-	return quoteIdent(s, `"`)
+	part := strings.SplitN(s, ".", 2)
+	if len(part) == 2 {
+		return d.QuoteIdent(part[0]) + "." + d.QuoteIdent(part[1])
+	}
+	if s == "*" {
+		return s
+	}
+	return "[" + strings.Replace(s, "]", "]]", -1) + "]"
 }
 
 func (d mssql) EncodeString(s string) string {
@@ -23,14 +30,37 @@ func (d mssql) EncodeBool(b bool) string {
 	return "0"
 }
 
+// EncodeTime encodes time as datetime2 literal, which has 7 fractional digits.
 func (d mssql) EncodeTime(t time.Time) string {
-	return t.Format("'2006-01-02 15:04:05.999'")
+	return t.Format("'2006-01-02 15:04:05.0000000'")
 }
 
 func (d mssql) EncodeBytes(b []byte) string {
-	return fmt.Sprintf(`E'\\x%x'`, b)
+	return fmt.Sprintf("0x%x", b)
 }
 
 func (d mssql) Placeholder(n int) string {
 	return fmt.Sprintf("@p%d", n+1)
 }
+
+func (d mssql) Capabilities() Capabilities {
+	return Capabilities{
+		SupportsOutput:        true,
+		SupportsCTE:           true,
+		SupportsTableAliasAs:  true,
+		SupportsFullJoin:      true,
+		UpdateJoinStyle:       JoinFrom,
+		DeleteJoinStyle:       JoinFrom,
+		SupportsMerge:         true,
+		SupportsTruncate:      true,
+		SupportsDefault:       true,
+		SupportsDefaultValues: true,
+		SupportsTableSample:   true,
+		NullsStyle:            NullsCase,
+		LimitStyle:            TopOffsetFetch,
+		SupportsWithTies:      true,
+		MaxPlaceholders:       2100,
+		// datetime2
+		TimePrecision: 100 * time.Nanosecond,
+	}
+}
@@ -9,7 +9,7 @@ import (
 type mysql struct{}
 
 func (d mysql) QuoteIdent(s string) string {
-	return quoteIdent(s, "`")
+	return QuoteIdent(s, "`")
 }
 
 func (d mysql) EncodeString(s string) string {
@@ -64,3 +64,29 @@ func (d mysql) EncodeBytes(b []byte) string {
 func (d mysql) Placeholder(_ int) string {
 	return "?"
 }
+
+func (d mysql) Capabilities() Capabilities {
+	return Capabilities{
+		SupportsOnDuplicateKey:   true,
+		SupportsInsertIgnore:     true,
+		SupportsCTE:              true,
+		SupportsRecursiveKeyword: true,
+		SupportsTableAliasAs:     true,
+		SupportsRowValues:        true,
+		UpdateJoinStyle:          JoinTable,
+		DeleteJoinStyle:          JoinTable,
+		SupportsTruncate:         true,
+		SupportsDefault:          true,
+		SupportsForUpdate:        true,
+		SupportsForShare:         true,
+		SupportsUpdateLimit:      true,
+		NullsStyle:               NullsIsNull,
+		MaxPlaceholders:          65535,
+		BackslashEscapes:         true,
+		HashComments:             true,
+		TwoPhaseStyle:            XA,
+		StatementTimeoutStyle:    MaxExecutionTime,
+		KillStyle:                KillQuery,
+		TimePrecision:            time.Microsecond,
+	}
+}
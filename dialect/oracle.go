@@ -0,0 +1,75 @@
+package dialect
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// oracle is the oracle dialect of major version, where 0 means the latest version.
+type oracle struct {
+	version int
+}
+
+// OracleVersion returns the oracle dialect of version like "11.2" or "19c".
+// Before 12c, limits are built by wrapping the query with ROWNUM conditions,
+// while Oracle assumes the latest version.
+func OracleVersion(version string) (Dialect, error) {
+	major := strings.SplitN(version, ".", 2)[0]
+	n, err := strconv.Atoi(strings.TrimRight(major, "cgi"))
+	if err != nil || n <= 0 {
+		return nil, fmt.Errorf("dialect: invalid oracle version %q", version)
+	}
+	return oracle{version: n}, nil
+}
+
+func (d oracle) QuoteIdent(s string) string {
+	return QuoteIdent(s, `"`)
+}
+
+func (d oracle) EncodeString(s string) string {
+	return `'` + strings.Replace(s, `'`, `''`, -1) + `'`
+}
+
+// EncodeBool encodes bool as number, because oracle has no boolean type in SQL before 23c.
+func (d oracle) EncodeBool(b bool) string {
+	if b {
+		return "1"
+	}
+	return "0"
+}
+
+func (d oracle) EncodeTime(t time.Time) string {
+	return `TO_TIMESTAMP('` + t.Format(timeFormat) + `', 'YYYY-MM-DD HH24:MI:SS.FF6')`
+}
+
+func (d oracle) EncodeBytes(b []byte) string {
+	return fmt.Sprintf(`HEXTORAW('%x')`, b)
+}
+
+func (d oracle) Placeholder(n int) string {
+	return fmt.Sprintf(":%d", n+1)
+}
+
+func (d oracle) Capabilities() Capabilities {
+	c := Capabilities{
+		SupportsCTE:           true,
+		SupportsRowValues:     true,
+		SupportsFullJoin:      true,
+		SupportsMerge:         true,
+		SupportsTruncate:      true,
+		SupportsDefault:       true,
+		SupportsDefaultValues: true,
+		SupportsForUpdate:     true,
+		LimitStyle:            OffsetFetch,
+		SupportsWithTies:      true,
+		MaxPlaceholders:       65535,
+		TimePrecision:         time.Microsecond,
+	}
+	if d.version != 0 && d.version < 12 {
+		c.LimitStyle = RowNum
+		c.SupportsWithTies = false
+	}
+	return c
+}
@@ -9,7 +9,7 @@ import (
 type postgreSQL struct{}
 
 func (d postgreSQL) QuoteIdent(s string) string {
-	return quoteIdent(s, `"`)
+	return QuoteIdent(s, `"`)
 }
 
 func (d postgreSQL) EncodeString(s string) string {
@@ -35,3 +35,32 @@ func (d postgreSQL) EncodeBytes(b []byte) string {
 func (d postgreSQL) Placeholder(n int) string {
 	return fmt.Sprintf("$%d", n+1)
 }
+
+func (d postgreSQL) Capabilities() Capabilities {
+	return Capabilities{
+		SupportsReturning:        true,
+		SupportsOnConflict:       true,
+		SupportsCTE:              true,
+		SupportsRecursiveKeyword: true,
+		SupportsTableAliasAs:     true,
+		SupportsDistinctOn:       true,
+		SupportsRowValues:        true,
+		SupportsFullJoin:         true,
+		UpdateJoinStyle:          JoinUsing,
+		DeleteJoinStyle:          JoinUsing,
+		SupportsMerge:            true,
+		SupportsTruncate:         true,
+		SupportsDefault:          true,
+		SupportsDefaultValues:    true,
+		SupportsForUpdate:        true,
+		SupportsForShare:         true,
+		SupportsTableSample:      true,
+		SupportsWithTies:         true,
+		MaxPlaceholders:          65535,
+		DollarQuotes:             true,
+		TwoPhaseStyle:            PrepareTransaction,
+		StatementTimeoutStyle:    SetStatementTimeout,
+		KillStyle:                CancelBackend,
+		TimePrecision:            time.Microsecond,
+	}
+}
@@ -0,0 +1,58 @@
+package dialect
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+type snowflake struct{}
+
+// QuoteIdent quotes identifier with double quotes, which preserves the case in snowflake.
+func (d snowflake) QuoteIdent(s string) string {
+	return QuoteIdent(s, `"`)
+}
+
+func (d snowflake) EncodeString(s string) string {
+	// backslash is an escape character in snowflake string literals
+	s = strings.Replace(s, `\`, `\\`, -1)
+	return `'` + strings.Replace(s, `'`, `''`, -1) + `'`
+}
+
+func (d snowflake) EncodeBool(b bool) string {
+	if b {
+		return "TRUE"
+	}
+	return "FALSE"
+}
+
+func (d snowflake) EncodeTime(t time.Time) string {
+	return `'` + t.Format(timeFormat) + `'`
+}
+
+func (d snowflake) EncodeBytes(b []byte) string {
+	return fmt.Sprintf(`TO_BINARY('%x', 'HEX')`, b)
+}
+
+func (d snowflake) Placeholder(_ int) string {
+	return "?"
+}
+
+func (d snowflake) Capabilities() Capabilities {
+	return Capabilities{
+		SupportsCTE:              true,
+		SupportsRecursiveKeyword: true,
+		SupportsTableAliasAs:     true,
+		SupportsRowValues:        true,
+		SupportsFullJoin:         true,
+		UpdateJoinStyle:          JoinUsing,
+		SupportsMerge:            true,
+		SupportsTruncate:         true,
+		SupportsDefault:          true,
+		SupportsDefaultValues:    true,
+		SupportsQualify:          true,
+		SupportsTableSample:      true,
+		ParseJSON:                true,
+		TimePrecision:            time.Microsecond,
+	}
+}
@@ -2,14 +2,47 @@ package dialect
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
 	"time"
 )
 
-type sqlite3 struct{}
+// sqlite3 is the sqlite dialect of version like SQLITE_VERSION_NUMBER,
+// where 0 means the latest version.
+type sqlite3 struct {
+	version int
+}
+
+// SQLite3Version returns the sqlite dialect of version like "3.31.1",
+// which can be found by `SELECT sqlite_version()`.
+// The builders emulate or reject the features added after the version,
+// while SQLite3 assumes the latest version.
+func SQLite3Version(version string) (Dialect, error) {
+	part := strings.SplitN(version, ".", 3)
+	n := 0
+	for i := 0; i < 3; i++ {
+		n *= 1000
+		if i >= len(part) {
+			continue
+		}
+		v, err := strconv.Atoi(part[i])
+		if err != nil || v < 0 || v >= 1000 {
+			return nil, fmt.Errorf("dialect: invalid sqlite version %q", version)
+		}
+		n += v
+	}
+	if n < 3000000 {
+		return nil, fmt.Errorf("dialect: invalid sqlite version %q", version)
+	}
+	return sqlite3{version: n}, nil
+}
+
+func (d sqlite3) atLeast(version int) bool {
+	return d.version == 0 || d.version >= version
+}
 
 func (d sqlite3) QuoteIdent(s string) string {
-	return quoteIdent(s, `"`)
+	return QuoteIdent(s, `"`)
 }
 
 func (d sqlite3) EncodeString(s string) string {
@@ -19,15 +52,24 @@ func (d sqlite3) EncodeString(s string) string {
 
 func (d sqlite3) EncodeBool(b bool) string {
 	// https://www.sqlite.org/lang_expr.html
+	// TRUE and FALSE are aliases of 1 and 0 since 3.23.0.
+	if !d.atLeast(3023000) {
+		if b {
+			return "1"
+		}
+		return "0"
+	}
 	if b {
-		return "1"
+		return "TRUE"
 	}
-	return "0"
+	return "FALSE"
 }
 
+// EncodeTime encodes time in UTC, because the date and time functions
+// of sqlite treat time without timezone as UTC.
 func (d sqlite3) EncodeTime(t time.Time) string {
 	// https://www.sqlite.org/lang_datefunc.html
-	return MySQL.EncodeTime(t)
+	return MySQL.EncodeTime(t.UTC())
 }
 
 func (d sqlite3) EncodeBytes(b []byte) string {
@@ -38,3 +80,30 @@ func (d sqlite3) EncodeBytes(b []byte) string {
 func (d sqlite3) Placeholder(_ int) string {
 	return "?"
 }
+
+func (d sqlite3) Capabilities() Capabilities {
+	c := Capabilities{
+		SupportsInsertOrIgnore:   true,
+		SupportsCTE:              true,
+		SupportsRecursiveKeyword: true,
+		SupportsTableAliasAs:     true,
+		SupportsRowValues:        true,
+		SupportsDefaultValues:    true,
+		SupportsUpdateLimit:      true,
+		LastInsertIDIsLastRow:    true,
+		TimePrecision:            time.Microsecond,
+		// https://www.sqlite.org/limits.html
+		MaxPlaceholders: 999,
+	}
+	// https://www.sqlite.org/changes.html
+	c.SupportsOnConflict = d.atLeast(3024000)
+	if d.atLeast(3032000) {
+		c.MaxPlaceholders = 32766
+	}
+	if d.atLeast(3033000) {
+		c.UpdateJoinStyle = JoinUsing
+	}
+	c.SupportsReturning = d.atLeast(3035000)
+	c.SupportsFullJoin = d.atLeast(3039000)
+	return c
+}
@@ -0,0 +1,247 @@
+package dbr
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// NullDuration is a type that can be null or a time.Duration.
+//
+// It scans postgres INTERVAL like "1 day 02:03:04.5", and integer or float
+// columns in Unit, which is time.Second if Unit is 0.
+// It is valued as interval "HH:MM:SS.ffffff" if Unit is 0, which is also
+// accepted by TIME in mysql, or otherwise as an integer in Unit.
+type NullDuration struct {
+	Duration time.Duration
+	Valid    bool // Valid is true if Duration is not NULL
+	Unit     time.Duration
+}
+
+func (n *NullDuration) unit() time.Duration {
+	if n.Unit <= 0 {
+		return time.Second
+	}
+	return n.Unit
+}
+
+// Scan implements the Scanner interface.
+func (n *NullDuration) Scan(value interface{}) error {
+	var err error
+	switch v := value.(type) {
+	case nil:
+		n.Duration, n.Valid = 0, false
+		return nil
+	case int64:
+		n.Duration = time.Duration(v) * n.unit()
+	case float64:
+		n.Duration = time.Duration(math.Round(v * float64(n.unit())))
+	case []byte:
+		return n.Scan(string(v))
+	case string:
+		if i, e := strconv.ParseInt(v, 10, 64); e == nil {
+			return n.Scan(i)
+		}
+		if f, e := strconv.ParseFloat(v, 64); e == nil {
+			return n.Scan(f)
+		}
+		n.Duration, err = parseInterval(v)
+	default:
+		err = fmt.Errorf("dbr: cannot scan %T into NullDuration", value)
+	}
+	n.Valid = err == nil
+	return err
+}
+
+// Value implements the driver Valuer interface.
+func (n NullDuration) Value() (driver.Value, error) {
+	if !n.Valid {
+		return nil, nil
+	}
+	if n.Unit > 0 {
+		return int64(n.Duration / n.Unit), nil
+	}
+	return formatInterval(n.Duration), nil
+}
+
+// MarshalJSON correctly serializes a NullDuration to JSON string like "1h2m3s".
+func (n NullDuration) MarshalJSON() ([]byte, error) {
+	if n.Valid {
+		return json.Marshal(n.Duration.String())
+	}
+	return nullString, nil
+}
+
+// UnmarshalJSON correctly deserializes a NullDuration from JSON string
+// like "1h2m3s", or JSON number in nanoseconds.
+func (n *NullDuration) UnmarshalJSON(b []byte) error {
+	if bytes.Equal(b, nullString) {
+		return n.Scan(nil)
+	}
+	var s json.Number
+	if err := json.Unmarshal(b, &s); err == nil {
+		d, err := strconv.ParseInt(string(s), 10, 64)
+		if err != nil {
+			return err
+		}
+		n.Duration, n.Valid = time.Duration(d), true
+		return nil
+	}
+	var str string
+	if err := json.Unmarshal(b, &str); err != nil {
+		return err
+	}
+	d, err := time.ParseDuration(str)
+	if err != nil {
+		return err
+	}
+	n.Duration, n.Valid = d, true
+	return nil
+}
+
+// NewNullDuration creates a NullDuration with Scan().
+func NewNullDuration(v interface{}) (n NullDuration) {
+	if d, ok := v.(time.Duration); ok {
+		return NullDuration{Duration: d, Valid: true}
+	}
+	n.Scan(v)
+	return
+}
+
+// NullDurationFrom creates a valid NullDuration.
+func NullDurationFrom(v time.Duration) NullDuration {
+	return NullDuration{Duration: v, Valid: true}
+}
+
+// NullDurationFromPtr creates a NullDuration that is NULL if p is nil.
+func NullDurationFromPtr(p *time.Duration) (n NullDuration) {
+	if p != nil {
+		n = NullDurationFrom(*p)
+	}
+	return
+}
+
+// Ptr returns a pointer to the value, or nil if n is NULL.
+func (n NullDuration) Ptr() *time.Duration {
+	if !n.Valid {
+		return nil
+	}
+	v := n.Duration
+	return &v
+}
+
+// ValueOrZero returns the value, or the zero value if n is NULL.
+func (n NullDuration) ValueOrZero() time.Duration {
+	if !n.Valid {
+		return 0
+	}
+	return n.Duration
+}
+
+// formatInterval formats d as "[-]HH:MM:SS[.ffffff]".
+func formatInterval(d time.Duration) string {
+	var buf strings.Builder
+	if d < 0 {
+		buf.WriteByte('-')
+		d = -d
+	}
+	h := d / time.Hour
+	m := d % time.Hour / time.Minute
+	s := d % time.Minute / time.Second
+	us := d % time.Second / time.Microsecond
+	fmt.Fprintf(&buf, "%02d:%02d:%02d", h, m, s)
+	if us > 0 {
+		fmt.Fprintf(&buf, ".%06d", us)
+	}
+	return buf.String()
+}
+
+// intervalUnit is the length of the units in postgres INTERVAL,
+// where a month is 30 days and a year is 365.25 days like EXTRACT(EPOCH).
+var intervalUnit = map[string]time.Duration{
+	"year":   time.Duration(365.25 * 24 * float64(time.Hour)),
+	"mon":    30 * 24 * time.Hour,
+	"month":  30 * 24 * time.Hour,
+	"week":   7 * 24 * time.Hour,
+	"day":    24 * time.Hour,
+	"hour":   time.Hour,
+	"min":    time.Minute,
+	"minute": time.Minute,
+	"sec":    time.Second,
+	"second": time.Second,
+}
+
+// parseInterval parses postgres INTERVAL like "1 year 2 mons 3 days -04:05:06.5"
+// or "@ 1 hour 2 mins ago".
+func parseInterval(s string) (time.Duration, error) {
+	field := strings.Fields(s)
+	if len(field) == 0 {
+		return 0, fmt.Errorf("dbr: invalid interval %q", s)
+	}
+	var d time.Duration
+	ago := false
+	for i := 0; i < len(field); i++ {
+		f := field[i]
+		switch {
+		case f == "@":
+		case f == "ago":
+			ago = true
+		case strings.Contains(f, ":"):
+			t, err := parseClock(f)
+			if err != nil {
+				return 0, fmt.Errorf("dbr: invalid interval %q", s)
+			}
+			d += t
+		default:
+			if i+1 >= len(field) {
+				return 0, fmt.Errorf("dbr: invalid interval %q", s)
+			}
+			num, err := strconv.ParseFloat(f, 64)
+			unit, ok := intervalUnit[strings.TrimSuffix(field[i+1], "s")]
+			if err != nil || !ok {
+				return 0, fmt.Errorf("dbr: invalid interval %q", s)
+			}
+			d += time.Duration(math.Round(num * float64(unit)))
+			i++
+		}
+	}
+	if ago {
+		d = -d
+	}
+	return d, nil
+}
+
+// parseClock parses "[-]HH:MM[:SS[.ffffff]]".
+func parseClock(s string) (time.Duration, error) {
+	neg := strings.HasPrefix(s, "-")
+	s = strings.TrimLeft(s, "+-")
+	part := strings.Split(s, ":")
+	if len(part) < 2 || len(part) > 3 {
+		return 0, ErrInvalidTimestring
+	}
+	h, err := strconv.ParseInt(part[0], 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	m, err := strconv.ParseInt(part[1], 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	d := time.Duration(h)*time.Hour + time.Duration(m)*time.Minute
+	if len(part) == 3 {
+		sec, err := strconv.ParseFloat(part[2], 64)
+		if err != nil {
+			return 0, err
+		}
+		d += time.Duration(math.Round(sec * float64(time.Second)))
+	}
+	if neg {
+		d = -d
+	}
+	return d, nil
+}
@@ -0,0 +1,83 @@
+package dbr
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/jiyeyuran/dbr/v2/dialect"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNullDurationScan(t *testing.T) {
+	for _, test := range []struct {
+		unit  time.Duration
+		value interface{}
+		want  time.Duration
+	}{
+		{value: "01:02:03.5", want: time.Hour + 2*time.Minute + 3500*time.Millisecond},
+		{value: []byte("1 day 02:00:00"), want: 26 * time.Hour},
+		{value: "3 days", want: 72 * time.Hour},
+		{value: "-00:00:01", want: -time.Second},
+		{value: "1 mon -1 days", want: 29 * 24 * time.Hour},
+		{value: "@ 1 hour 30 mins ago", want: -90 * time.Minute},
+		{value: int64(90), want: 90 * time.Second},
+		{value: 1.5, want: 1500 * time.Millisecond},
+		{unit: time.Millisecond, value: int64(1500), want: 1500 * time.Millisecond},
+		{unit: time.Millisecond, value: []byte("250"), want: 250 * time.Millisecond},
+	} {
+		n := NullDuration{Unit: test.unit}
+		require.NoError(t, n.Scan(test.value), "%v", test.value)
+		require.True(t, n.Valid)
+		require.Equal(t, test.want, n.Duration, "%v", test.value)
+		require.Equal(t, test.unit, n.Unit)
+	}
+
+	var n NullDuration
+	require.Error(t, n.Scan("1 fortnight"))
+	require.False(t, n.Valid)
+	require.NoError(t, n.Scan(nil))
+	require.False(t, n.Valid)
+}
+
+func TestNullDurationValue(t *testing.T) {
+	d := 26*time.Hour + 3*time.Minute + 4*time.Second + 500*time.Microsecond
+
+	v, err := NullDuration{Duration: d, Valid: true}.Value()
+	require.NoError(t, err)
+	require.Equal(t, "26:03:04.000500", v)
+
+	v, err = NullDuration{Duration: -time.Second, Valid: true}.Value()
+	require.NoError(t, err)
+	require.Equal(t, "-00:00:01", v)
+
+	v, err = NullDuration{Duration: d, Valid: true, Unit: time.Millisecond}.Value()
+	require.NoError(t, err)
+	require.Equal(t, int64(93784000), v)
+
+	v, err = NullDuration{Unit: time.Second}.Value()
+	require.NoError(t, err)
+	require.Nil(t, v)
+
+	query, err := InterpolateForDialect("?", []interface{}{NewNullDuration(90 * time.Minute)}, dialect.PostgreSQL)
+	require.NoError(t, err)
+	require.Equal(t, "'01:30:00'", query)
+}
+
+func TestNullDurationJSON(t *testing.T) {
+	var out struct {
+		Timeout NullDuration `json:"timeout"`
+		Retry   NullDuration `json:"retry"`
+	}
+	err := json.Unmarshal([]byte(`{"timeout":"1m30s","retry":null}`), &out)
+	require.NoError(t, err)
+	require.Equal(t, NullDuration{Duration: 90 * time.Second, Valid: true}, out.Timeout)
+	require.False(t, out.Retry.Valid)
+
+	b, err := json.Marshal(out)
+	require.NoError(t, err)
+	require.Equal(t, `{"timeout":"1m30s","retry":null}`, string(b))
+
+	require.NoError(t, json.Unmarshal([]byte(`1000`), &out.Retry))
+	require.Equal(t, time.Microsecond, out.Retry.Duration)
+}
@@ -0,0 +1,107 @@
+package dbr
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// EnumMember is a string type that lists its valid members, like
+//
+//	type Status string
+//
+//	func (Status) Members() []Status { return []Status{"active", "banned"} }
+type EnumMember[T any] interface {
+	~string
+	Members() []T
+}
+
+// Enum is a T that is validated on Scan, Value and UnmarshalJSON,
+// so that values not in T.Members() are never read or written.
+// Use Null[Enum[T]] for nullable columns.
+type Enum[T EnumMember[T]] struct {
+	V T
+}
+
+// NewEnum creates an Enum, or returns *EnumError if v is not a member.
+func NewEnum[T EnumMember[T]](v T) (Enum[T], error) {
+	e := Enum[T]{V: v}
+	return e, e.validate()
+}
+
+func (e Enum[T]) validate() error {
+	member := e.V.Members()
+	for _, m := range member {
+		if m == e.V {
+			return nil
+		}
+	}
+	valid := make([]string, len(member))
+	for i, m := range member {
+		valid[i] = string(m)
+	}
+	return &EnumError{Value: string(e.V), Valid: valid}
+}
+
+// String returns the value.
+func (e Enum[T]) String() string {
+	return string(e.V)
+}
+
+// Scan implements the Scanner interface.
+func (e *Enum[T]) Scan(value interface{}) error {
+	var s string
+	switch v := value.(type) {
+	case []byte:
+		s = string(v)
+	case string:
+		s = v
+	default:
+		return fmt.Errorf("%w: cannot scan %T", ErrInvalidEnum, value)
+	}
+	scanned := Enum[T]{V: T(s)}
+	if err := scanned.validate(); err != nil {
+		return err
+	}
+	*e = scanned
+	return nil
+}
+
+// Value implements the driver Valuer interface.
+func (e Enum[T]) Value() (driver.Value, error) {
+	if err := e.validate(); err != nil {
+		return nil, err
+	}
+	return string(e.V), nil
+}
+
+// MarshalJSON serializes an Enum to JSON string.
+func (e Enum[T]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(string(e.V))
+}
+
+// UnmarshalJSON deserializes an Enum from JSON string.
+func (e *Enum[T]) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+	return e.Scan(s)
+}
+
+// EnumError is returned if a value is not a member of Enum.
+// It wraps ErrInvalidEnum.
+type EnumError struct {
+	Value string
+	Valid []string
+}
+
+func (e *EnumError) Error() string {
+	return fmt.Sprintf("dbr: invalid enum %q, valid values are %s", e.Value, strings.Join(e.Valid, ", "))
+}
+
+// Unwrap returns ErrInvalidEnum.
+func (e *EnumError) Unwrap() error {
+	return ErrInvalidEnum
+}
@@ -0,0 +1,59 @@
+package dbr
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/jiyeyuran/dbr/v2/dialect"
+	"github.com/stretchr/testify/require"
+)
+
+type testStatus string
+
+func (testStatus) Members() []testStatus {
+	return []testStatus{"active", "banned"}
+}
+
+func TestEnum(t *testing.T) {
+	var e Enum[testStatus]
+	require.NoError(t, e.Scan([]byte("active")))
+	require.Equal(t, testStatus("active"), e.V)
+
+	err := e.Scan("deleted")
+	require.True(t, errors.Is(err, ErrInvalidEnum))
+	require.Equal(t, `dbr: invalid enum "deleted", valid values are active, banned`, err.Error())
+	var enumErr *EnumError
+	require.True(t, errors.As(err, &enumErr))
+	require.Equal(t, []string{"active", "banned"}, enumErr.Valid)
+	require.Equal(t, testStatus("active"), e.V)
+	require.Error(t, e.Scan(nil))
+
+	_, err = NewEnum[testStatus]("deleted")
+	require.True(t, errors.Is(err, ErrInvalidEnum))
+
+	_, err = InterpolateForDialect("?", []interface{}{Enum[testStatus]{V: "deleted"}}, dialect.MySQL)
+	require.True(t, errors.Is(err, ErrInvalidEnum))
+
+	banned, err := NewEnum[testStatus]("banned")
+	require.NoError(t, err)
+	query, err := InterpolateForDialect("?", []interface{}{banned}, dialect.MySQL)
+	require.NoError(t, err)
+	require.Equal(t, "'banned'", query)
+
+	var n Null[Enum[testStatus]]
+	require.NoError(t, n.Scan(nil))
+	require.False(t, n.Valid)
+	require.NoError(t, n.Scan("banned"))
+	require.Equal(t, NewNull(banned), n)
+	require.Error(t, n.Scan("deleted"))
+
+	var out struct {
+		Status Enum[testStatus] `json:"status"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(`{"status":"active"}`), &out))
+	b, err := json.Marshal(out)
+	require.NoError(t, err)
+	require.Equal(t, `{"status":"active"}`, string(b))
+	require.Error(t, json.Unmarshal([]byte(`{"status":"deleted"}`), &out))
+}
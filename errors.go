@@ -1,16 +1,49 @@
 package dbr
 
-import "errors"
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+)
 
 // package errors
 var (
-	ErrNotFound           = errors.New("dbr: not found")
-	ErrNotSupported       = errors.New("dbr: not supported")
-	ErrTableNotSpecified  = errors.New("dbr: table not specified")
-	ErrColumnNotSpecified = errors.New("dbr: column not specified")
-	ErrInvalidPointer     = errors.New("dbr: attempt to load into an invalid pointer")
-	ErrPlaceholderCount   = errors.New("dbr: wrong placeholder count")
-	ErrInvalidSliceLength = errors.New("dbr: length of slice is 0. length must be >= 1")
-	ErrCantConvertToTime  = errors.New("dbr: can't convert to time.Time")
-	ErrInvalidTimestring  = errors.New("dbr: invalid time string")
+	ErrNotFound            = error(notFoundError{})
+	ErrNotSupported        = errors.New("dbr: not supported")
+	ErrTableNotSpecified   = errors.New("dbr: table not specified")
+	ErrColumnNotSpecified  = errors.New("dbr: column not specified")
+	ErrInvalidPointer      = errors.New("dbr: attempt to load into an invalid pointer")
+	ErrPlaceholderCount    = errors.New("dbr: wrong placeholder count")
+	ErrInvalidSliceLength  = errors.New("dbr: length of slice is 0. length must be >= 1")
+	ErrCantConvertToTime   = errors.New("dbr: can't convert to time.Time")
+	ErrInvalidTimestring   = errors.New("dbr: invalid time string")
+	ErrDialectNotSupported = errors.New("dbr: not supported by dialect")
+	ErrInvalidCursor       = errors.New("dbr: invalid cursor")
+	ErrInvalidKeyset       = errors.New("dbr: invalid keyset")
+	ErrInvalidDecimal      = errors.New("dbr: invalid decimal")
+	ErrInvalidUUID         = errors.New("dbr: invalid uuid")
+	ErrInvalidEnum         = errors.New("dbr: invalid enum")
+	ErrUnmappedColumn      = errors.New("dbr: column not mapped")
+	ErrBatchAborted        = errors.New("dbr: batch aborted")
+	ErrTxNotPrepared       = errors.New("dbr: transaction not prepared")
+	ErrNoShard             = errors.New("dbr: no shard for key")
+	ErrReplicationStopped  = errors.New("dbr: replication stopped")
+	ErrInitNotSupported    = errors.New("dbr: Init needs a connection of Open or OpenConnector")
 )
+
+// notFoundError wraps sql.ErrNoRows,
+// so that errors.Is(ErrNotFound, sql.ErrNoRows) is true.
+type notFoundError struct{}
+
+func (notFoundError) Error() string {
+	return "dbr: not found"
+}
+
+func (notFoundError) Unwrap() error {
+	return sql.ErrNoRows
+}
+
+// errDialectNotSupported reports which clause the dialect cannot build.
+func errDialectNotSupported(clause string) error {
+	return fmt.Errorf("%w: %s", ErrDialectNotSupported, clause)
+}
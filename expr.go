@@ -1,5 +1,7 @@
 package dbr
 
+import "github.com/jiyeyuran/dbr/v2/dialect"
+
 type raw struct {
 	Query string
 	Value []interface{}
@@ -16,3 +18,13 @@ func (raw *raw) Build(_ Dialect, buf Buffer) error {
 	buf.WriteValue(raw.Value...)
 	return nil
 }
+
+// Default is the DEFAULT keyword, which sets a column to its default value
+// in InsertStmt or UpdateStmt. sqlite does not support it.
+var Default Builder = BuildFunc(func(d Dialect, buf Buffer) error {
+	if !dialect.CapabilitiesOf(d).SupportsDefault {
+		return errDialectNotSupported("DEFAULT")
+	}
+	buf.WriteString("DEFAULT")
+	return nil
+})
@@ -1,6 +1,6 @@
 module github.com/jiyeyuran/dbr/v2
 
-go 1.13
+go 1.18
 
 require (
 	github.com/DATA-DOG/go-sqlmock v1.4.1
@@ -12,3 +12,11 @@ require (
 	github.com/opentracing/opentracing-go v1.1.0
 	github.com/stretchr/testify v1.4.0
 )
+
+require (
+	github.com/davecgh/go-spew v1.1.0 // indirect
+	github.com/golang-sql/civil v0.0.0-20190719163853-cb61b32ac6fe // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	golang.org/x/crypto v0.0.0-20190325154230-a5d413f7728c // indirect
+	gopkg.in/yaml.v2 v2.2.2 // indirect
+)
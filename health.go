@@ -0,0 +1,129 @@
+package dbr
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/jiyeyuran/dbr/v2/dialect"
+)
+
+// Health is the health of a Connection.
+type Health struct {
+	// Latency is the round trip time of a ping to the primary.
+	Latency time.Duration
+	// Replicas are the health of Connection.Replicas in order.
+	Replicas []ReplicaHealth
+}
+
+// ReplicaHealth is the health of a replica.
+type ReplicaHealth struct {
+	Latency time.Duration
+	// Lag is how far the replica is behind the primary, which is -1
+	// if it is unknown, like in the dialects other than mysql and postgres.
+	Lag time.Duration
+	// Err is the error of the ping or the query of Lag,
+	// or ErrReplicationStopped if the replication of mysql is stopped.
+	Err error
+}
+
+// HealthCheck pings the primary and the replicas, and queries the
+// replication lag of the replicas, which can be used by readiness probes.
+// It returns the error of the primary, and the errors of the replicas are
+// in their ReplicaHealth.
+func (conn *Connection) HealthCheck(ctx context.Context) (*Health, error) {
+	health := new(Health)
+	latency, err := ping(ctx, conn.DB)
+	if err != nil {
+		return nil, conn.EventErr("dbr.health.error", err)
+	}
+	health.Latency = latency
+
+	if conn.Replicas == nil {
+		return health, nil
+	}
+	for _, r := range conn.Replicas.replicas {
+		rh := ReplicaHealth{Lag: -1}
+		rh.Latency, rh.Err = ping(ctx, r.DB)
+		if rh.Err == nil {
+			rh.Lag, rh.Err = replicationLag(ctx, r.DB, conn.Dialect)
+		}
+		health.Replicas = append(health.Replicas, rh)
+	}
+	return health, nil
+}
+
+func ping(ctx context.Context, db *sql.DB) (time.Duration, error) {
+	start := time.Now()
+	err := db.PingContext(ctx)
+	return time.Since(start), err
+}
+
+// replicationLag queries the replication lag of db, or -1 if it is unknown.
+func replicationLag(ctx context.Context, db *sql.DB, d Dialect) (time.Duration, error) {
+	switch d {
+	case dialect.MySQL:
+		lag, err := mysqlReplicationLag(ctx, db, "SHOW REPLICA STATUS")
+		if err != nil && !errors.Is(err, ErrReplicationStopped) {
+			// before 8.0.22
+			return mysqlReplicationLag(ctx, db, "SHOW SLAVE STATUS")
+		}
+		return lag, err
+	case dialect.PostgreSQL:
+		// the lag is 0 if all the received wal is replayed,
+		// and NULL on the primary
+		var seconds sql.NullFloat64
+		err := db.QueryRowContext(ctx, `SELECT CASE
+WHEN pg_last_wal_receive_lsn() = pg_last_wal_replay_lsn() THEN 0
+ELSE EXTRACT(EPOCH FROM now() - pg_last_xact_replay_timestamp())
+END`).Scan(&seconds)
+		if err != nil || !seconds.Valid {
+			return -1, err
+		}
+		return time.Duration(seconds.Float64 * float64(time.Second)), nil
+	}
+	return -1, nil
+}
+
+// mysqlReplicationLag reads Seconds_Behind_Source of the replica status,
+// which is NULL if the replication is stopped, and then returns
+// ErrReplicationStopped.
+func mysqlReplicationLag(ctx context.Context, db *sql.DB, query string) (time.Duration, error) {
+	rows, err := db.QueryContext(ctx, query)
+	if err != nil {
+		return -1, err
+	}
+	defer rows.Close()
+	column, err := rows.Columns()
+	if err != nil {
+		return -1, err
+	}
+	if !rows.Next() {
+		// not a replica
+		return -1, rows.Err()
+	}
+	value := make([]sql.RawBytes, len(column))
+	dest := make([]interface{}, len(column))
+	for i := range value {
+		dest[i] = &value[i]
+	}
+	err = rows.Scan(dest...)
+	if err != nil {
+		return -1, err
+	}
+	for i, name := range column {
+		if name == "Seconds_Behind_Source" || name == "Seconds_Behind_Master" {
+			if value[i] == nil {
+				return -1, ErrReplicationStopped
+			}
+			seconds, err := strconv.ParseInt(string(value[i]), 10, 64)
+			if err != nil {
+				return -1, err
+			}
+			return time.Duration(seconds) * time.Second, nil
+		}
+	}
+	return -1, nil
+}
@@ -0,0 +1,68 @@
+package dbr
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"testing"
+	"time"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/jiyeyuran/dbr/v2/dialect"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHealthCheck(t *testing.T) {
+	sess, _ := newMockSession(t, dialect.MySQL)
+	conn := sess.Connection
+	replica1, mock1, err := sqlmock.New()
+	require.NoError(t, err)
+	replica2, mock2, err := sqlmock.New()
+	require.NoError(t, err)
+	replica3, mock3, err := sqlmock.New(sqlmock.MonitorPingsOption(true))
+	require.NoError(t, err)
+
+	conn.Replicas = NewReplicaPool(replica1, replica2, replica3)
+
+	mock1.ExpectQuery("SHOW REPLICA STATUS").
+		WillReturnRows(sqlmock.NewRows([]string{"Replica_IO_State", "Seconds_Behind_Source"}).AddRow("Waiting for source", 3))
+	mock2.ExpectQuery("SHOW REPLICA STATUS").WillReturnError(errors.New("syntax error"))
+	mock2.ExpectQuery("SHOW SLAVE STATUS").
+		WillReturnRows(sqlmock.NewRows([]string{"Slave_IO_State", "Seconds_Behind_Master"}).AddRow("", nil))
+	mock3.ExpectPing().WillReturnError(driver.ErrBadConn)
+
+	health, err := conn.HealthCheck(context.Background())
+	require.NoError(t, err)
+	require.Len(t, health.Replicas, 3)
+	require.NoError(t, health.Replicas[0].Err)
+	require.Equal(t, 3*time.Second, health.Replicas[0].Lag)
+	require.Equal(t, ErrReplicationStopped, health.Replicas[1].Err)
+	require.Equal(t, time.Duration(-1), health.Replicas[1].Lag)
+	require.Error(t, health.Replicas[2].Err)
+	require.NoError(t, mock1.ExpectationsWereMet())
+	require.NoError(t, mock2.ExpectationsWereMet())
+	require.NoError(t, mock3.ExpectationsWereMet())
+
+	replica, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	conn.Dialect = dialect.PostgreSQL
+	conn.Replicas = NewReplicaPool(replica)
+	mock.ExpectQuery("pg_last_xact_replay_timestamp").
+		WillReturnRows(sqlmock.NewRows([]string{"lag"}).AddRow(1.5))
+	health, err = conn.HealthCheck(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 1500*time.Millisecond, health.Replicas[0].Lag)
+	require.NoError(t, mock.ExpectationsWereMet())
+
+	// stopped with SHOW REPLICA STATUS
+	replica, mock, err = sqlmock.New()
+	require.NoError(t, err)
+	conn.Dialect = dialect.MySQL
+	conn.Replicas = NewReplicaPool(replica)
+	mock.ExpectQuery("SHOW REPLICA STATUS").
+		WillReturnRows(sqlmock.NewRows([]string{"Replica_IO_State", "Seconds_Behind_Source"}).AddRow("", nil))
+	health, err = conn.HealthCheck(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, ErrReplicationStopped, health.Replicas[0].Err)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,166 @@
+package dbr
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/jiyeyuran/dbr/v2/dialect"
+)
+
+// HStore is a key/value column like hstore in postgres,
+// where a NULL value is invalid NullString.
+// In other dialects, it is stored as JSON object in TEXT or JSON columns.
+type HStore map[string]NullString
+
+// Scan implements the Scanner interface.
+// The value can be hstore like `"a"=>"1", "b"=>NULL`, or JSON object.
+func (h *HStore) Scan(value interface{}) error {
+	var s string
+	switch v := value.(type) {
+	case nil:
+		*h = nil
+		return nil
+	case []byte:
+		s = string(v)
+	case string:
+		s = v
+	default:
+		return fmt.Errorf("dbr: cannot scan %T into HStore", value)
+	}
+	if strings.HasPrefix(strings.TrimSpace(s), "{") {
+		m := make(HStore)
+		if err := json.Unmarshal([]byte(s), &m); err != nil {
+			return err
+		}
+		*h = m
+		return nil
+	}
+	m, err := parseHStore(s)
+	if err != nil {
+		return err
+	}
+	*h = m
+	return nil
+}
+
+// Value implements the driver Valuer interface.
+// The value is hstore like `"a"=>"1", "b"=>NULL`.
+func (h HStore) Value() (driver.Value, error) {
+	if h == nil {
+		return nil, nil
+	}
+	key := make([]string, 0, len(h))
+	for k := range h {
+		key = append(key, k)
+	}
+	sort.Strings(key)
+	pair := make([]string, len(key))
+	for i, k := range key {
+		v := "NULL"
+		if h[k].Valid {
+			v = quoteHStore(h[k].String)
+		}
+		pair[i] = quoteHStore(k) + "=>" + v
+	}
+	return strings.Join(pair, ", "), nil
+}
+
+// DialectValue implements DialectValuer.
+// The value is JSON object except in postgres.
+func (h HStore) DialectValue(d Dialect) (driver.Value, error) {
+	if h == nil || d == dialect.PostgreSQL {
+		return h.Value()
+	}
+	b, err := json.Marshal(map[string]NullString(h))
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
+
+func quoteHStore(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	return `"` + s + `"`
+}
+
+// parseHStore parses hstore like `"a"=>"1", b=>NULL`.
+func parseHStore(s string) (HStore, error) {
+	m := make(HStore)
+	p := hstoreParser{s: s}
+	p.skipSpace()
+	for p.i < len(p.s) {
+		key, quoted, err := p.token()
+		if err != nil || key == "" && !quoted {
+			return nil, fmt.Errorf("dbr: invalid hstore %q", s)
+		}
+		p.skipSpace()
+		if !strings.HasPrefix(p.s[p.i:], "=>") {
+			return nil, fmt.Errorf("dbr: invalid hstore %q", s)
+		}
+		p.i += 2
+		p.skipSpace()
+		value, quoted, err := p.token()
+		if err != nil || value == "" && !quoted {
+			return nil, fmt.Errorf("dbr: invalid hstore %q", s)
+		}
+		if !quoted && strings.EqualFold(value, "NULL") {
+			m[key] = NullString{}
+		} else {
+			m[key] = NewNullString(value)
+		}
+		p.skipSpace()
+		if p.i < len(p.s) {
+			if p.s[p.i] != ',' {
+				return nil, fmt.Errorf("dbr: invalid hstore %q", s)
+			}
+			p.i++
+			p.skipSpace()
+		}
+	}
+	return m, nil
+}
+
+type hstoreParser struct {
+	s string
+	i int
+}
+
+func (p *hstoreParser) skipSpace() {
+	for p.i < len(p.s) && (p.s[p.i] == ' ' || p.s[p.i] == '\t' || p.s[p.i] == '\n') {
+		p.i++
+	}
+}
+
+// token reads a quoted string, or an unquoted word until `=>`, `,` or space.
+func (p *hstoreParser) token() (string, bool, error) {
+	var b strings.Builder
+	if p.i < len(p.s) && p.s[p.i] == '"' {
+		for p.i++; p.i < len(p.s); p.i++ {
+			switch p.s[p.i] {
+			case '\\':
+				p.i++
+				if p.i < len(p.s) {
+					b.WriteByte(p.s[p.i])
+				}
+			case '"':
+				p.i++
+				return b.String(), true, nil
+			default:
+				b.WriteByte(p.s[p.i])
+			}
+		}
+		return "", true, fmt.Errorf("dbr: unterminated hstore string")
+	}
+	for ; p.i < len(p.s); p.i++ {
+		c := p.s[p.i]
+		if c == ',' || c == ' ' || c == '\t' || c == '\n' || strings.HasPrefix(p.s[p.i:], "=>") {
+			break
+		}
+		b.WriteByte(c)
+	}
+	return b.String(), false, nil
+}
@@ -0,0 +1,53 @@
+package dbr
+
+import (
+	"testing"
+
+	"github.com/jiyeyuran/dbr/v2/dialect"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHStore(t *testing.T) {
+	var h HStore
+	require.NoError(t, h.Scan([]byte(`"color"=>"red", "size"=>NULL, "quote"=>"a\"b", plain=>word`)))
+	require.Equal(t, HStore{
+		"color": NewNullString("red"),
+		"size":  {},
+		"quote": NewNullString(`a"b`),
+		"plain": NewNullString("word"),
+	}, h)
+
+	require.NoError(t, h.Scan(`{"color":"red","size":null}`))
+	require.Equal(t, HStore{"color": NewNullString("red"), "size": {}}, h)
+
+	require.NoError(t, h.Scan(""))
+	require.Equal(t, HStore{}, h)
+	require.NoError(t, h.Scan(nil))
+	require.Nil(t, h)
+	require.Error(t, h.Scan(`"a"=>`))
+	require.Error(t, h.Scan(`"a"=>"1" "b"=>"2"`))
+
+	h = HStore{"size": {}, "color": NewNullString(`r"\d`)}
+	v, err := h.Value()
+	require.NoError(t, err)
+	require.Equal(t, `"color"=>"r\"\\d", "size"=>NULL`, v)
+
+	builder := Update("item").Set("attrs", HStore{"color": NewNullString("red"), "size": {}})
+	for _, test := range []struct {
+		dialect Dialect
+		query   string
+	}{
+		{
+			dialect: dialect.PostgreSQL,
+			query:   `UPDATE "item" SET "attrs" = '"color"=>"red", "size"=>NULL'`,
+		},
+		{
+			dialect: dialect.MySQL,
+			query:   "UPDATE `item` SET `attrs` = '{\\\"color\\\":\\\"red\\\",\\\"size\\\":null}'",
+		},
+	} {
+		query, err := InterpolateForDialect("?", []interface{}{builder}, test.dialect)
+		require.NoError(t, err)
+		require.Equal(t, test.query, query)
+	}
+}
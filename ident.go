@@ -1,6 +1,13 @@
 package dbr
 
-// I is quoted identifier
+import (
+	"strings"
+	"unicode"
+)
+
+// I is quoted identifier.
+// Dotted identifier like "schema.table.column" is quoted by parts,
+// so it is safe to quote column names from user input.
 type I string
 
 // Build quotes string with dialect.
@@ -23,3 +30,40 @@ func as(expr interface{}, alias string) Builder {
 		return nil
 	})
 }
+
+// ident builds s with quoteIdent.
+func ident(s string) Builder {
+	return BuildFunc(func(d Dialect, buf Buffer) error {
+		buf.WriteString(quoteIdent(d, s))
+		return nil
+	})
+}
+
+// quoteIdent quotes s if it is a name, which can be dotted like "t.id" or
+// "t.*", and returns the other expressions like "COUNT(*)" or "t AS a"
+// as they are.
+func quoteIdent(d Dialect, s string) string {
+	if !isIdent(s) {
+		return s
+	}
+	return d.QuoteIdent(s)
+}
+
+func isIdent(s string) bool {
+	part := strings.Split(s, ".")
+	for i, p := range part {
+		if p == "*" && i == len(part)-1 {
+			continue
+		}
+		if p == "" {
+			return false
+		}
+		for j, r := range p {
+			if r == '_' || unicode.IsLetter(r) || j > 0 && (unicode.IsDigit(r) || r == '$') {
+				continue
+			}
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,34 @@
+package dbr
+
+import "github.com/jiyeyuran/dbr/v2/dialect"
+
+// indexHint builds `USE INDEX (...)` and alike for mysql.
+// Other dialects do not have index hints, so they are ignored.
+func indexHint(kind string, index []string) Builder {
+	return BuildFunc(func(d Dialect, buf Buffer) error {
+		if d != dialect.MySQL {
+			return nil
+		}
+		buf.WriteString(" ")
+		buf.WriteString(kind)
+		buf.WriteString(" INDEX (")
+		for i, idx := range index {
+			if i > 0 {
+				buf.WriteString(", ")
+			}
+			buf.WriteString(d.QuoteIdent(idx))
+		}
+		buf.WriteString(")")
+		return nil
+	})
+}
+
+func buildIndexHints(d Dialect, buf Buffer, hints []Builder) error {
+	for _, hint := range hints {
+		err := hint.Build(d, buf)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,132 @@
+package dbr
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"strings"
+)
+
+// initDB returns the pool of the connections set up by init, which runs init
+// once on each new connection. The sessions with the same init share the pool.
+func (conn *Connection) initDB(init []string) (*sql.DB, error) {
+	if conn.connector == nil {
+		return nil, ErrInitNotSupported
+	}
+	key := strings.Join(init, "\x00")
+	conn.initMu.Lock()
+	defer conn.initMu.Unlock()
+	if db, ok := conn.initDBs[key]; ok {
+		return db, nil
+	}
+	db := sql.OpenDB(&initConnector{
+		Connector: conn.connector,
+		init:      append([]string(nil), init...),
+		log:       conn.EventReceiver,
+	})
+	db.SetMaxOpenConns(conn.DB.Stats().MaxOpenConnections)
+	if conn.initDBs == nil {
+		conn.initDBs = make(map[string]*sql.DB)
+	}
+	conn.initDBs[key] = db
+	return db, nil
+}
+
+// closeInitDBs closes the pools of initDB.
+func (conn *Connection) closeInitDBs() {
+	conn.initMu.Lock()
+	defer conn.initMu.Unlock()
+	for key, db := range conn.initDBs {
+		db.Close()
+		delete(conn.initDBs, key)
+	}
+}
+
+// initConnector runs init on the new connections of Connector.
+type initConnector struct {
+	driver.Connector
+	init []string
+	log  EventReceiver
+}
+
+// Connect implements driver.Connector.
+func (c *initConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := c.Connector.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, query := range c.init {
+		err := execConn(ctx, conn, query)
+		if err != nil {
+			conn.Close()
+			return nil, c.log.EventErrKv("dbr.init.error", err, kvs{
+				"sql": query,
+			})
+		}
+	}
+	return conn, nil
+}
+
+// execConn executes query without arguments on conn of the driver.
+func execConn(ctx context.Context, conn driver.Conn, query string) error {
+	if execer, ok := conn.(driver.ExecerContext); ok {
+		_, err := execer.ExecContext(ctx, query, nil)
+		if err != driver.ErrSkip {
+			return err
+		}
+	}
+	var stmt driver.Stmt
+	var err error
+	if preparer, ok := conn.(driver.ConnPrepareContext); ok {
+		stmt, err = preparer.PrepareContext(ctx, query)
+	} else {
+		stmt, err = conn.Prepare(query)
+	}
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+	if execer, ok := stmt.(driver.StmtExecContext); ok {
+		_, err = execer.ExecContext(ctx, nil)
+	} else {
+		_, err = stmt.Exec(nil)
+	}
+	return err
+}
+
+// pool returns the pool of sess, whose connections are set up by Init.
+func (sess *Session) pool() (*sql.DB, error) {
+	if len(sess.Init) == 0 {
+		return sess.Connection.DB, nil
+	}
+	return sess.Connection.initDB(sess.Init)
+}
+
+// ExecContext executes a query without returning rows,
+// on a connection set up by Init if it is set.
+func (sess *Session) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	db, err := sess.pool()
+	if err != nil {
+		return nil, err
+	}
+	return db.ExecContext(ctx, query, args...)
+}
+
+// QueryContext executes a query that returns rows,
+// on a connection set up by Init if it is set.
+func (sess *Session) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	db, err := sess.pool()
+	if err != nil {
+		return nil, err
+	}
+	return db.QueryContext(ctx, query, args...)
+}
+
+// stmtCache disables the cache with Init,
+// since the cached statements are prepared on the pool without Init.
+func (sess *Session) stmtCache() *stmtCache {
+	if len(sess.Init) > 0 {
+		return nil
+	}
+	return sess.stmts
+}
@@ -0,0 +1,83 @@
+package dbr
+
+import (
+	"errors"
+	"regexp"
+	"testing"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/jiyeyuran/dbr/v2/dialect"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSessionInit(t *testing.T) {
+	sess, mock := newInitSession(t, dialect.PostgreSQL)
+	sess.EnableStmtCache(10)
+	sess.Init = []string{"SET time_zone = '+00:00'", "SET search_path = tenant_x"}
+
+	// Init runs once on the new connection of the pool of Init,
+	// which is reused by the queries and transactions
+	mock.ExpectExec(regexp.QuoteMeta("SET time_zone = '+00:00'")).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta("SET search_path = tenant_x")).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT "id" FROM "suggestions" WHERE (id = 1)`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT "id" FROM "suggestions" WHERE (id = 2)`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2))
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "suggestions"`)).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	var id int64
+	require.NoError(t, sess.Select("id").From("suggestions").Where("id = ?", 1).LoadOne(&id))
+	require.Equal(t, int64(1), id)
+	require.NoError(t, sess.Select("id").From("suggestions").Where("id = ?", 2).LoadOne(&id))
+	require.Equal(t, int64(2), id)
+	tx, err := sess.Begin()
+	require.NoError(t, err)
+	_, err = tx.DeleteFrom("suggestions").Exec()
+	require.NoError(t, err)
+	require.NoError(t, tx.Commit())
+	require.NoError(t, mock.ExpectationsWereMet())
+
+	db, err := sess.pool()
+	require.NoError(t, err)
+	require.Equal(t, 1, db.Stats().OpenConnections)
+	require.Equal(t, 1, db.Stats().Idle)
+
+	// the sessions with the same Init share the pool
+	same := sess.Connection.NewSession(nil)
+	same.Init = []string{"SET time_zone = '+00:00'", "SET search_path = tenant_x"}
+	sameDB, err := same.pool()
+	require.NoError(t, err)
+	require.Equal(t, db, sameDB)
+
+	// and the sessions without Init never see the variables,
+	// which cache the statements
+	mock.ExpectPrepare(regexp.QuoteMeta(`DELETE FROM "suggestions"`)).
+		ExpectExec().WillReturnResult(sqlmock.NewResult(0, 1))
+	_, err = sess.Connection.NewSession(nil).DeleteFrom("suggestions").Exec()
+	require.NoError(t, err)
+	require.NoError(t, mock.ExpectationsWereMet())
+
+	// the statement fails if Init fails
+	other := sess.Connection.NewSession(nil)
+	other.Init = []string{"SET time_zone = '+00:00'", "SET search_path = tenant_y"}
+	mock.ExpectExec(regexp.QuoteMeta("SET time_zone = '+00:00'")).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta("SET search_path = tenant_y")).WillReturnError(errors.New("schema does not exist"))
+	_, err = other.DeleteFrom("suggestions").Exec()
+	require.EqualError(t, err, "schema does not exist")
+	require.NoError(t, mock.ExpectationsWereMet())
+	otherDB, err := other.pool()
+	require.NoError(t, err)
+	require.Equal(t, 0, otherDB.Stats().OpenConnections)
+}
+
+func TestSessionInitNotSupported(t *testing.T) {
+	sess, _ := newMockSession(t, dialect.PostgreSQL)
+	sess.Init = []string{"SET time_zone = '+00:00'"}
+
+	_, err := sess.DeleteFrom("suggestions").Exec()
+	require.Equal(t, ErrInitNotSupported, err)
+	_, err = sess.Begin()
+	require.True(t, errors.Is(err, ErrInitNotSupported))
+}
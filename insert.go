@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"reflect"
 	"strings"
+	"time"
 
 	"github.com/jiyeyuran/dbr/v2/dialect"
 )
@@ -17,13 +18,23 @@ type InsertStmt struct {
 
 	raw
 
-	Table        string
-	Column       []string
-	Value        [][]interface{}
-	Ignored      bool
-	ReturnColumn []string
-	RecordID     *int64
-	comments     Comments
+	Table           string
+	Column          []string
+	Value           [][]interface{}
+	Source          Builder
+	Ignored         bool
+	IsDefaultValues bool
+	Conflict        *ConflictStmt
+	ReturnColumn    []string
+	RecordID        *int64
+	comments        Comments
+	tags            CommentTags
+
+	// records are the structs of Value, where the returning columns are loaded.
+	records       []reflect.Value
+	autoIncrement string
+
+	timeout time.Duration
 }
 
 type InsertBuilder = InsertStmt
@@ -37,7 +48,7 @@ func (b *InsertStmt) Build(d Dialect, buf Buffer) error {
 		return ErrTableNotSpecified
 	}
 
-	if len(b.Column) == 0 {
+	if len(b.Column) == 0 && b.Source == nil && !b.IsDefaultValues {
 		return ErrColumnNotSpecified
 	}
 
@@ -46,61 +57,82 @@ func (b *InsertStmt) Build(d Dialect, buf Buffer) error {
 		return err
 	}
 
-	if b.Ignored {
-		buf.WriteString("INSERT IGNORE INTO ")
+	caps := dialect.CapabilitiesOf(d)
+	if b.Ignored || b.Conflict.ignored(d) {
+		switch {
+		case caps.SupportsInsertIgnore:
+			buf.WriteString("INSERT IGNORE INTO ")
+		case caps.SupportsOnConflict:
+			// built as ON CONFLICT DO NOTHING
+			buf.WriteString("INSERT INTO ")
+		case caps.SupportsInsertOrIgnore:
+			buf.WriteString("INSERT OR IGNORE INTO ")
+		default:
+			return errDialectNotSupported("INSERT IGNORE")
+		}
 	} else {
 		buf.WriteString("INSERT INTO ")
 	}
 
-	buf.WriteString(d.QuoteIdent(b.Table))
+	buf.WriteString(quoteTable(d, b.runner, b.Table))
 
 	var placeholderBuf strings.Builder
 	placeholderBuf.WriteString("(")
-	buf.WriteString(" (")
-	for i, col := range b.Column {
-		if i > 0 {
-			buf.WriteString(",")
-			placeholderBuf.WriteString(",")
-		}
-		buf.WriteString(d.QuoteIdent(col))
-		placeholderBuf.WriteString(placeholder)
-	}
-	buf.WriteString(")")
-
-	if d == dialect.MSSQL && len(b.ReturnColumn) > 0 {
-		buf.WriteString(" OUTPUT ")
-		for i, col := range b.ReturnColumn {
+	if len(b.Column) > 0 {
+		buf.WriteString(" (")
+		for i, col := range b.Column {
 			if i > 0 {
 				buf.WriteString(",")
+				placeholderBuf.WriteString(",")
 			}
-			buf.WriteString("INSERTED." + d.QuoteIdent(col))
+			buf.WriteString(d.QuoteIdent(col))
+			placeholderBuf.WriteString(placeholder)
 		}
+		buf.WriteString(")")
 	}
 
-	buf.WriteString(" VALUES ")
-	placeholderBuf.WriteString(")")
-	placeholderStr := placeholderBuf.String()
+	buildOutput(d, buf, "INSERTED", b.ReturnColumn)
 
-	for i, tuple := range b.Value {
-		if i > 0 {
-			buf.WriteString(", ")
+	if b.IsDefaultValues && len(b.Column) == 0 && b.Source == nil {
+		if dialect.CapabilitiesOf(d).SupportsDefaultValues {
+			buf.WriteString(" DEFAULT VALUES")
+		} else {
+			buf.WriteString(" () VALUES ()")
 		}
-		buf.WriteString(placeholderStr)
-
-		buf.WriteValue(tuple...)
-	}
+	} else if b.Source != nil {
+		buf.WriteString(" ")
+		err := b.Source.Build(d, buf)
+		if err != nil {
+			return err
+		}
+	} else {
+		buf.WriteString(" VALUES ")
+		placeholderBuf.WriteString(")")
+		placeholderStr := placeholderBuf.String()
 
-	if d != dialect.MSSQL && len(b.ReturnColumn) > 0 {
-		buf.WriteString(" RETURNING ")
-		for i, col := range b.ReturnColumn {
+		timeOpts := timeOptionsOf(b.runner)
+		for i, tuple := range b.Value {
 			if i > 0 {
-				buf.WriteString(",")
+				buf.WriteString(", ")
 			}
-			buf.WriteString(d.QuoteIdent(col))
+			buf.WriteString(placeholderStr)
+
+			for _, v := range tuple {
+				buf.WriteValue(timeOpts.written(v))
+			}
+		}
+	}
+
+	if b.Conflict != nil {
+		err := b.Conflict.Build(d, buf)
+		if err != nil {
+			return err
 		}
+	} else if b.Ignored && !caps.SupportsInsertIgnore && caps.SupportsOnConflict {
+		buf.WriteString(" ON CONFLICT DO NOTHING")
 	}
 
-	return nil
+	return buildReturning(d, buf, b.ReturnColumn)
 }
 
 // InsertInto creates an InsertStmt.
@@ -167,14 +199,50 @@ func (b *InsertStmt) Comment(comment string) *InsertStmt {
 	return b
 }
 
-// Ignore any insertion errors
+// CommentTag adds a tag to the comment appended to the query like SelectStmt.CommentTag.
+func (b *InsertStmt) CommentTag(key, value string) *InsertStmt {
+	b.tags = b.tags.Add(key, value)
+	return b
+}
+
+func (b *InsertStmt) commentTags(context.Context) map[string]string {
+	return b.tags
+}
+
+// Ignore skips the rows that conflict with existing ones.
+// It builds `INSERT IGNORE` in mysql, and `ON CONFLICT DO NOTHING` in postgres and sqlite.
 func (b *InsertStmt) Ignore() *InsertStmt {
 	b.Ignored = true
 	return b
 }
 
+// DefaultValues inserts a row with default values for all columns.
+func (b *InsertStmt) DefaultValues() *InsertStmt {
+	b.IsDefaultValues = true
+	return b
+}
+
+// FromSelect inserts the rows from a query instead of Values.
+func (b *InsertStmt) FromSelect(sel *SelectStmt) *InsertStmt {
+	b.Source = sel
+	return b
+}
+
+// OnConflict handles the conflict on unique columns with DoUpdate.
+// The columns are ignored in mysql, which checks all unique keys.
+func (b *InsertStmt) OnConflict(column ...string) *ConflictStmt {
+	b.Conflict = &ConflictStmt{
+		stmt:   b,
+		Column: column,
+	}
+	return b.Conflict
+}
+
 // Values adds a tuple to be inserted.
 // The order of the tuple should match Columns.
+//
+// A value can be Builder like Expr("NOW()"), which is built
+// as SQL instead of being quoted.
 func (b *InsertStmt) Values(value ...interface{}) *InsertStmt {
 	b.Value = append(b.Value, value)
 	return b
@@ -183,34 +251,77 @@ func (b *InsertStmt) Values(value ...interface{}) *InsertStmt {
 // Record adds a tuple for columns from a struct.
 //
 // If there is a field called "Id" or "ID" in the struct,
-// it will be set to LastInsertId.
+// it will be set to LastInsertId, or loaded with Returning in postgres.
 //
 // If no Columns are specified, the columns will be set by the
 // struct fields excluding non exported fields.
+//
+// Fields with `omitempty` tag option are skipped if they are zero,
+// so the database defaults apply. Fields with `readonly` are always skipped.
+//
+// A field with `pk` tag option is used instead of "id".
+//
+// A field with `autoincrement` tag option like `db:"id,autoincrement"` is set
+// for every record after Exec, with RETURNING in postgres and mssql, or
+// LastInsertId in mysql and sqlite which assumes that the ids are consecutive.
 func (b *InsertStmt) Record(structValue interface{}) *InsertStmt {
+	return b.record(structValue, false)
+}
+
+// RecordOmitEmpty is like Record, but all zero fields are skipped.
+//
+// The columns are decided by the first record, so it is
+// not suitable for inserting multiple records with different zero fields.
+func (b *InsertStmt) RecordOmitEmpty(structValue interface{}) *InsertStmt {
+	return b.record(structValue, true)
+}
+
+func (b *InsertStmt) record(structValue interface{}, omitEmpty bool) *InsertStmt {
 	v := reflect.Indirect(reflect.ValueOf(structValue))
 
 	if v.Kind() == reflect.Struct {
-		s := newTagStore()
+		s := newTagStoreFor(b.runner)
+
+		idColumn := "id"
+		autoIncrement := false
+		for i, opt := range s.options(v.Type()) {
+			if opt.Contains("autoincrement") {
+				idColumn = s.get(v.Type())[i]
+				autoIncrement = true
+				break
+			}
+			if opt.Contains("pk") && idColumn == "id" {
+				idColumn = s.get(v.Type())[i]
+			}
+		}
 
 		// We still have no columns specified
 		// Use the struct fields excluding non exported fields
 		if len(b.Column) == 0 {
 			fields := s.get(v.Type())
+			opts := s.options(v.Type())
 			for i, field := range fields {
-				if field == "id" {
-					if idField := v.Field(i); idField.IsZero() {
+				if opts[i].Contains("readonly") {
+					continue
+				}
+				if field == idColumn || omitEmpty || opts[i].Contains("omitempty") {
+					if v.Field(i).IsZero() {
 						continue
 					}
 				}
-				if field != "" {
+				if field == "" {
+					continue
+				}
+				if column, _ := fieldColumns(v.Field(i), field, opts[i], false); column != nil {
+					b.Column = append(b.Column, column...)
+				} else {
 					b.Column = append(b.Column, field)
 				}
 			}
 		}
 
 		found := make([]interface{}, len(b.Column)+1)
-		s.findValueByName(v, append(b.Column, "id"), found, false)
+		s.findValueByName(v, append(b.Column, idColumn), found, false)
 
 		value := found[:len(found)-1]
 		for i, v := range value {
@@ -222,30 +333,73 @@ func (b *InsertStmt) Record(structValue interface{}) *InsertStmt {
 		if v.CanSet() {
 			switch idField := found[len(found)-1].(type) {
 			case reflect.Value:
-				if idField.Kind() == reflect.Int64 {
+				if autoIncrement {
+					b.autoIncrement = idColumn
+					// try to add returning id in PostgreSQL and MSSQL
+					switch b.Dialect {
+					case dialect.PostgreSQL, dialect.CockroachDB, dialect.MSSQL:
+						b.addReturning(idColumn)
+					}
+				} else if idField.Kind() == reflect.Int64 {
 					b.RecordID = idField.Addr().Interface().(*int64)
 					// try to add returning id in PostgreSQL
-					if b.Dialect == dialect.PostgreSQL {
-						found := false
-						for _, column := range b.ReturnColumn {
-							if strings.ToLower(column) == "id" {
-								found = true
-								break
-							}
-						}
-						if !found {
-							b.ReturnColumn = append(b.ReturnColumn, "id")
-						}
+					if b.Dialect == dialect.PostgreSQL || b.Dialect == dialect.CockroachDB {
+						b.addReturning(idColumn)
 					}
 				}
 			}
 		}
+		for len(b.records) < len(b.Value) {
+			b.records = append(b.records, reflect.Value{})
+		}
+		if v.CanSet() {
+			b.records = append(b.records, v)
+		} else {
+			b.records = append(b.records, reflect.Value{})
+		}
 		b.Values(value...)
 	}
 	return b
 }
 
-// Returning specifies the returning columns for postgres/mssql.
+func (b *InsertStmt) addReturning(column string) {
+	for _, col := range b.ReturnColumn {
+		if strings.EqualFold(col, column) {
+			return
+		}
+	}
+	b.ReturnColumn = append(b.ReturnColumn, column)
+}
+
+// setAutoIncrement sets the autoincrement field of records from the first id.
+// It assumes the ids are consecutive, like mysql with innodb_autoinc_lock_mode 0 or 1.
+func (b *InsertStmt) setAutoIncrement(firstID int64) {
+	s := newTagStoreFor(b.runner)
+	found := make([]interface{}, 1)
+	for i, record := range b.records {
+		if !record.IsValid() {
+			continue
+		}
+		found[0] = nil
+		s.findValueByName(record, []string{b.autoIncrement}, found, false)
+		field, ok := found[0].(reflect.Value)
+		if !ok {
+			continue
+		}
+		id := firstID + int64(i)
+		switch field.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			field.SetInt(id)
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			field.SetUint(uint64(id))
+		}
+	}
+}
+
+// Returning specifies the returning columns for postgres/sqlite/mssql.
+//
+// With Exec, the returning columns are loaded back into the structs added by Record.
+// Otherwise, use Load to load them into another value.
 func (b *InsertStmt) Returning(column ...string) *InsertStmt {
 	b.ReturnColumn = column
 	return b
@@ -266,11 +420,26 @@ func (b *InsertStmt) Pair(column string, value interface{}) *InsertStmt {
 	return b
 }
 
-func (b *InsertStmt) Exec() (sql.Result, error) {
+// Timeout cancels the statement after d like SelectStmt.Timeout.
+func (b *InsertStmt) Timeout(d time.Duration) *InsertStmt {
+	b.timeout = d
+	return b
+}
+
+func (b *InsertStmt) statementTimeout() time.Duration {
+	return b.timeout
+}
+
+func (b *InsertStmt) Exec() (*Result, error) {
 	return b.ExecContext(context.Background())
 }
 
-func (b *InsertStmt) ExecContext(ctx context.Context) (sql.Result, error) {
+func (b *InsertStmt) ExecContext(ctx context.Context) (*Result, error) {
+	if len(b.ReturnColumn) > 0 && len(b.records) > 0 {
+		b.RecordID = nil
+		return execReturning(ctx, b.runner, b.EventReceiver, b, b.Dialect, b.records)
+	}
+
 	result, err := exec(ctx, b.runner, b.EventReceiver, b, b.Dialect)
 	if err != nil {
 		return nil, err
@@ -283,9 +452,91 @@ func (b *InsertStmt) ExecContext(ctx context.Context) (sql.Result, error) {
 		b.RecordID = nil
 	}
 
+	if b.autoIncrement != "" {
+		if id, err := result.LastInsertId(); err == nil {
+			if dialect.CapabilitiesOf(b.Dialect).LastInsertIDIsLastRow {
+				// sqlite returns the id of the last row
+				id -= int64(len(b.Value) - 1)
+			}
+			b.setAutoIncrement(id)
+		}
+	}
+
 	return result, nil
 }
 
+// ExecChunked executes the statement in chunks of batchSize rows,
+// to stay under the limits of placeholders and packet size.
+//
+// If batchSize is 0, it is derived from the placeholder limit of the dialect.
+// The chunks are not atomic unless the statement is created by Tx.
+// It stops at the first failed chunk.
+// The Result has the SQL of the last chunk and the Duration of all chunks.
+func (b *InsertStmt) ExecChunked(ctx context.Context, batchSize int) (*Result, error) {
+	if max := dialect.CapabilitiesOf(b.Dialect).MaxPlaceholders; batchSize <= 0 && max > 0 && len(b.Column) > 0 {
+		batchSize = max / len(b.Column)
+	}
+	if batchSize <= 0 || len(b.Value) <= batchSize || b.Source != nil {
+		return b.ExecContext(ctx)
+	}
+
+	var total chunkedResult
+	var query string
+	var duration time.Duration
+	for start := 0; start < len(b.Value); start += batchSize {
+		end := start + batchSize
+		if end > len(b.Value) {
+			end = len(b.Value)
+		}
+
+		chunk := *b
+		chunk.Value = b.Value[start:end]
+		if start < len(b.records) {
+			chunk.records = b.records[start:]
+			if end < len(b.records) {
+				chunk.records = b.records[start:end]
+			}
+		} else {
+			chunk.records = nil
+		}
+		if end < len(b.Value) {
+			// RecordID belongs to the last record
+			chunk.RecordID = nil
+		}
+
+		result, err := chunk.ExecContext(ctx)
+		if err != nil {
+			return nil, err
+		}
+		n, err := result.RowsAffected()
+		if err != nil {
+			return nil, err
+		}
+		total.Result = result.Result
+		total.rowsAffected += n
+		query = result.SQL
+		duration += result.Duration
+	}
+	b.RecordID = nil
+	return &Result{
+		Result:   total,
+		SQL:      query,
+		Duration: duration,
+		Attempts: attemptsOf(b.runner),
+	}, nil
+}
+
+// chunkedResult sums up RowsAffected of all chunks.
+// LastInsertId is from the last chunk.
+type chunkedResult struct {
+	sql.Result
+	rowsAffected int64
+}
+
+func (r chunkedResult) RowsAffected() (int64, error) {
+	return r.rowsAffected, nil
+}
+
 func (b *InsertStmt) LoadContext(ctx context.Context, value interface{}) error {
 	_, err := query(ctx, b.runner, b.EventReceiver, b, b.Dialect, value)
 	return err
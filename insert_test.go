@@ -1,8 +1,13 @@
 package dbr
 
 import (
+	"context"
+	"errors"
+	"regexp"
 	"testing"
+	"time"
 
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
 	"github.com/jiyeyuran/dbr/v2/dialect"
 	"github.com/stretchr/testify/require"
 )
@@ -37,6 +42,307 @@ func TestInsertStmtAutoColumn(t *testing.T) {
 	require.Equal(t, []interface{}{1, "one", 2, "two"}, buf.Value())
 }
 
+func TestInsertRecordOmitEmpty(t *testing.T) {
+	type user struct {
+		ID        int64
+		Name      string
+		Role      string `db:"role,omitempty"`
+		CreatedAt string `db:"created_at,omitempty"`
+	}
+
+	buf := NewBuffer()
+	err := InsertInto("user").Record(&user{Role: "admin"}).Build(dialect.MySQL, buf)
+	require.NoError(t, err)
+	require.Equal(t, "INSERT INTO `user` (`name`,`role`) VALUES (?,?)", buf.String())
+	require.Equal(t, []interface{}{"", "admin"}, buf.Value())
+
+	buf = NewBuffer()
+	err = InsertInto("user").RecordOmitEmpty(&user{Role: "admin"}).Build(dialect.MySQL, buf)
+	require.NoError(t, err)
+	require.Equal(t, "INSERT INTO `user` (`role`) VALUES (?)", buf.String())
+	require.Equal(t, []interface{}{"admin"}, buf.Value())
+}
+
+func TestInsertDefault(t *testing.T) {
+	for _, test := range []struct {
+		dialect Dialect
+		query   string
+	}{
+		{
+			dialect: dialect.MySQL,
+			query:   "INSERT INTO `counter` () VALUES ()",
+		},
+		{
+			dialect: dialect.PostgreSQL,
+			query:   `INSERT INTO "counter" DEFAULT VALUES`,
+		},
+		{
+			dialect: dialect.SQLite3,
+			query:   `INSERT INTO "counter" DEFAULT VALUES`,
+		},
+	} {
+		buf := NewBuffer()
+		err := InsertInto("counter").DefaultValues().Build(test.dialect, buf)
+		require.NoError(t, err)
+		require.Equal(t, test.query, buf.String())
+	}
+
+	builder := InsertInto("user").Columns("name", "role").
+		Values("alice", Default).
+		Values("bob", "admin")
+	query, err := InterpolateForDialect("?", []interface{}{builder}, dialect.PostgreSQL)
+	require.NoError(t, err)
+	require.Equal(t, `INSERT INTO "user" ("name","role") VALUES ('alice',DEFAULT), ('bob','admin')`, query)
+
+	_, err = InterpolateForDialect("?", []interface{}{builder}, dialect.SQLite3)
+	require.True(t, errors.Is(err, ErrDialectNotSupported))
+}
+
+func TestInsertExprValues(t *testing.T) {
+	builder := InsertInto("event").Columns("id", "name", "created_at").
+		Values(Expr("UUID()"), "start", Expr("NOW()")).
+		Values(Expr("UUID()"), Expr("CONCAT(?, ?)", "st", "op"), time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC))
+
+	buf := NewBuffer()
+	err := builder.Build(dialect.MySQL, buf)
+	require.NoError(t, err)
+	require.Equal(t, "INSERT INTO `event` (`id`,`name`,`created_at`) VALUES (?,?,?), (?,?,?)", buf.String())
+
+	query, err := InterpolateForDialect("?", []interface{}{builder}, dialect.MySQL)
+	require.NoError(t, err)
+	require.Equal(t, "INSERT INTO `event` (`id`,`name`,`created_at`) VALUES (UUID(),'start',NOW()), (UUID(),CONCAT('st', 'op'),'2020-01-02 03:04:05.000000')", query)
+}
+
+func TestInsertFromSelect(t *testing.T) {
+	sel := Select("id", "name").From("user").Where(Lt("created_at", "2020-01-01"))
+	builder := InsertInto("archive").Columns("id", "name").FromSelect(sel)
+
+	buf := NewBuffer()
+	err := builder.Build(dialect.MySQL, buf)
+	require.NoError(t, err)
+	require.Equal(t, "INSERT INTO `archive` (`id`,`name`) SELECT `id`, `name` FROM `user` WHERE (`created_at` < ?)", buf.String())
+	require.Equal(t, []interface{}{"2020-01-01"}, buf.Value())
+
+	buf = NewBuffer()
+	err = InsertInto("archive").FromSelect(sel).Build(dialect.PostgreSQL, buf)
+	require.NoError(t, err)
+	require.Equal(t, `INSERT INTO "archive" SELECT "id", "name" FROM "user" WHERE ("created_at" < ?)`, buf.String())
+	require.Equal(t, []interface{}{"2020-01-01"}, buf.Value())
+}
+
+func TestInsertIgnore(t *testing.T) {
+	builder := InsertInto("tag").Columns("name").Values("go").Ignore()
+
+	for _, test := range []struct {
+		dialect Dialect
+		query   string
+	}{
+		{
+			dialect: dialect.MySQL,
+			query:   "INSERT IGNORE INTO `tag` (`name`) VALUES (?)",
+		},
+		{
+			dialect: dialect.PostgreSQL,
+			query:   `INSERT INTO "tag" ("name") VALUES (?) ON CONFLICT DO NOTHING`,
+		},
+		{
+			dialect: dialect.SQLite3,
+			query:   `INSERT INTO "tag" ("name") VALUES (?) ON CONFLICT DO NOTHING`,
+		},
+	} {
+		buf := NewBuffer()
+		err := builder.Build(test.dialect, buf)
+		require.NoError(t, err)
+		require.Equal(t, test.query, buf.String())
+	}
+
+	err := builder.Build(dialect.MSSQL, NewBuffer())
+	require.True(t, errors.Is(err, ErrDialectNotSupported))
+
+	builder = InsertInto("tag").Columns("name").Values("go").OnConflict("name").DoNothing()
+
+	buf := NewBuffer()
+	err = builder.Build(dialect.PostgreSQL, buf)
+	require.NoError(t, err)
+	require.Equal(t, `INSERT INTO "tag" ("name") VALUES (?) ON CONFLICT ("name") DO NOTHING`, buf.String())
+
+	buf = NewBuffer()
+	err = builder.Build(dialect.MySQL, buf)
+	require.NoError(t, err)
+	require.Equal(t, "INSERT IGNORE INTO `tag` (`name`) VALUES (?)", buf.String())
+
+	// DoUpdate without values is DoNothing
+	builder = InsertInto("tag").Columns("name").Values("go").OnConflict("name").DoUpdate(map[string]interface{}{})
+	buf = NewBuffer()
+	err = builder.Build(dialect.PostgreSQL, buf)
+	require.NoError(t, err)
+	require.Equal(t, `INSERT INTO "tag" ("name") VALUES (?) ON CONFLICT ("name") DO NOTHING`, buf.String())
+	buf = NewBuffer()
+	err = builder.Build(dialect.MySQL, buf)
+	require.NoError(t, err)
+	require.Equal(t, "INSERT IGNORE INTO `tag` (`name`) VALUES (?)", buf.String())
+
+	// DoUpdate needs the conflict columns except in mysql
+	builder = InsertInto("tag").Columns("name").Values("go").OnConflict().DoUpdate(map[string]interface{}{
+		"name": Excluded("name"),
+	})
+	err = builder.Build(dialect.PostgreSQL, NewBuffer())
+	require.True(t, errors.Is(err, ErrColumnNotSpecified))
+	err = builder.Build(dialect.SQLite3, NewBuffer())
+	require.True(t, errors.Is(err, ErrColumnNotSpecified))
+	buf = NewBuffer()
+	err = builder.Build(dialect.MySQL, buf)
+	require.NoError(t, err)
+	require.Equal(t, "INSERT INTO `tag` (`name`) VALUES (?) ON DUPLICATE KEY UPDATE `name` = ?", buf.String())
+}
+
+func TestInsertOnConflict(t *testing.T) {
+	builder := InsertInto("user").Columns("email", "name").Values("a@b.c", "alice").
+		OnConflict("email").DoUpdate(map[string]interface{}{
+		"name":       Excluded("name"),
+		"updated_at": Expr("NOW()"),
+	})
+
+	for _, test := range []struct {
+		dialect Dialect
+		query   string
+	}{
+		{
+			dialect: dialect.MySQL,
+			query:   "INSERT INTO `user` (`email`,`name`) VALUES ('a@b.c','alice') ON DUPLICATE KEY UPDATE `name` = VALUES(`name`), `updated_at` = NOW()",
+		},
+		{
+			dialect: dialect.PostgreSQL,
+			query:   `INSERT INTO "user" ("email","name") VALUES ('a@b.c','alice') ON CONFLICT ("email") DO UPDATE SET "name" = EXCLUDED."name", "updated_at" = NOW()`,
+		},
+		{
+			dialect: dialect.SQLite3,
+			query:   `INSERT INTO "user" ("email","name") VALUES ('a@b.c','alice') ON CONFLICT ("email") DO UPDATE SET "name" = EXCLUDED."name", "updated_at" = NOW()`,
+		},
+	} {
+		query, err := InterpolateForDialect("?", []interface{}{builder}, test.dialect)
+		require.NoError(t, err)
+		require.Equal(t, test.query, query)
+	}
+
+	err := builder.Build(dialect.MSSQL, NewBuffer())
+	require.True(t, errors.Is(err, ErrDialectNotSupported))
+}
+
+func TestInsertReturningRecord(t *testing.T) {
+	sess, mock := newMockSession(t, dialect.PostgreSQL)
+
+	type person struct {
+		ID      int64  `db:"id"`
+		Name    string `db:"name"`
+		Created string `db:"created"`
+	}
+	alice := &person{Name: "alice"}
+	bob := &person{Name: "bob"}
+
+	mock.ExpectQuery(`INSERT INTO "person"`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created"}).
+			AddRow(1, "today").
+			AddRow(2, "tomorrow"))
+	result, err := sess.InsertInto("person").Columns("name").
+		Record(alice).
+		Record(bob).
+		Returning("id", "created").
+		Exec()
+	require.NoError(t, err)
+	n, err := result.RowsAffected()
+	require.NoError(t, err)
+	require.EqualValues(t, 2, n)
+	require.Equal(t, &person{ID: 1, Name: "alice", Created: "today"}, alice)
+	require.Equal(t, &person{ID: 2, Name: "bob", Created: "tomorrow"}, bob)
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestInsertAutoIncrement(t *testing.T) {
+	sess, mock := newMockSession(t, dialect.MySQL)
+
+	type person struct {
+		Key  uint64 `db:"key,autoincrement"`
+		Name string `db:"name"`
+	}
+	people := []*person{{Name: "alice"}, {Name: "bob"}, {Name: "carol"}}
+
+	builder := sess.InsertInto("person")
+	for _, p := range people {
+		builder.Record(p)
+	}
+
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `person` (`name`) VALUES ('alice'), ('bob'), ('carol')")).
+		WillReturnResult(sqlmock.NewResult(10, 3))
+	_, err := builder.Exec()
+	require.NoError(t, err)
+	require.Equal(t, []*person{
+		{Key: 10, Name: "alice"},
+		{Key: 11, Name: "bob"},
+		{Key: 12, Name: "carol"},
+	}, people)
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestInsertExecChunked(t *testing.T) {
+	sess, mock := newMockSession(t, dialect.MySQL)
+
+	builder := sess.InsertInto("table").Columns("a", "b")
+	for i := 0; i < 5; i++ {
+		builder.Values(i, "x")
+	}
+
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `table` (`a`,`b`) VALUES (0,'x'), (1,'x')")).
+		WillReturnResult(sqlmock.NewResult(1, 2))
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `table` (`a`,`b`) VALUES (2,'x'), (3,'x')")).
+		WillReturnResult(sqlmock.NewResult(3, 2))
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `table` (`a`,`b`) VALUES (4,'x')")).
+		WillReturnResult(sqlmock.NewResult(5, 1))
+
+	result, err := builder.ExecChunked(context.Background(), 2)
+	require.NoError(t, err)
+	n, err := result.RowsAffected()
+	require.NoError(t, err)
+	require.EqualValues(t, 5, n)
+	id, err := result.LastInsertId()
+	require.NoError(t, err)
+	require.EqualValues(t, 5, id)
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpsert(t *testing.T) {
+	type product struct {
+		ID    int64  `db:"id"`
+		SKU   string `db:"sku"`
+		Name  string `db:"name"`
+		Price int    `db:"price"`
+	}
+	builder := Upsert("product", &product{SKU: "a-1", Name: "apple", Price: 3}, "sku")
+
+	query, err := InterpolateForDialect("?", []interface{}{builder}, dialect.PostgreSQL)
+	require.NoError(t, err)
+	require.Equal(t, `INSERT INTO "product" ("sku","name","price") VALUES ('a-1','apple',3) ON CONFLICT ("sku") DO UPDATE SET "name" = EXCLUDED."name", "price" = EXCLUDED."price"`, query)
+
+	query, err = InterpolateForDialect("?", []interface{}{builder}, dialect.MySQL)
+	require.NoError(t, err)
+	require.Equal(t, "INSERT INTO `product` (`sku`,`name`,`price`) VALUES ('a-1','apple',3) ON DUPLICATE KEY UPDATE `name` = VALUES(`name`), `price` = VALUES(`price`)", query)
+
+	// the pk columns are the conflict columns by default
+	type item struct {
+		SKU  string `db:"sku,pk"`
+		Name string `db:"name"`
+	}
+	query, err = InterpolateForDialect("?", []interface{}{Upsert("item", &item{SKU: "a-1", Name: "apple"})}, dialect.PostgreSQL)
+	require.NoError(t, err)
+	require.Equal(t, `INSERT INTO "item" ("sku","name") VALUES ('a-1','apple') ON CONFLICT ("sku") DO UPDATE SET "name" = EXCLUDED."name"`, query)
+
+	_, err = InterpolateForDialect("?", []interface{}{Upsert("product", &product{SKU: "a-1"})}, dialect.PostgreSQL)
+	require.True(t, errors.Is(err, ErrColumnNotSpecified))
+}
+
 func TestPostgresReturning(t *testing.T) {
 	sess := postgresSession
 	reset(t, sess)
@@ -71,3 +377,92 @@ func BenchmarkInsertRecordSQL(b *testing.B) {
 		}).Build(dialect.MySQL, buf)
 	}
 }
+
+func TestInsertReturningNotSupported(t *testing.T) {
+	builder := InsertInto("person").Columns("name").Values("alice").Returning("id")
+
+	err := builder.Build(dialect.MySQL, NewBuffer())
+	require.True(t, errors.Is(err, ErrDialectNotSupported))
+
+	buf := NewBuffer()
+	err = builder.Build(dialect.MSSQL, buf)
+	require.NoError(t, err)
+	require.Equal(t, "INSERT INTO [person] ([name]) OUTPUT INSERTED.[id] VALUES (?)", buf.String())
+}
+
+func TestInsertTooManyPlaceholders(t *testing.T) {
+	sess, _ := newMockSession(t, dialect.MSSQL)
+
+	builder := sess.InsertInto("file").Columns("data")
+	for i := 0; i < 2101; i++ {
+		builder.Values([]byte{byte(i)})
+	}
+	_, err := builder.Exec()
+	require.True(t, errors.Is(err, ErrDialectNotSupported))
+}
+
+func TestInsertSQLite3Version(t *testing.T) {
+	sqlite3, err := dialect.SQLite3Version("3.22.0")
+	require.NoError(t, err)
+
+	buf := NewBuffer()
+	err = InsertInto("tag").Columns("name").Values("go").Ignore().Build(sqlite3, buf)
+	require.NoError(t, err)
+	require.Equal(t, `INSERT OR IGNORE INTO "tag" ("name") VALUES (?)`, buf.String())
+
+	err = InsertInto("tag").Columns("name").Values("go").OnConflict("name").DoNothing().Build(sqlite3, NewBuffer())
+	require.True(t, errors.Is(err, ErrDialectNotSupported))
+
+	err = InsertInto("tag").Columns("name").Values("go").Returning("id").Build(sqlite3, NewBuffer())
+	require.True(t, errors.Is(err, ErrDialectNotSupported))
+}
+
+func TestInsertOnConflictWhere(t *testing.T) {
+	builder := InsertInto("user").Columns("email", "name").Values("a@b.c", "alice").
+		OnConflict("email").Where(Eq("deleted", false)).DoUpdate(map[string]interface{}{
+		"name": Excluded("name"),
+	})
+
+	for _, test := range []struct {
+		dialect Dialect
+		query   string
+	}{
+		{
+			dialect: dialect.PostgreSQL,
+			query:   `INSERT INTO "user" ("email","name") VALUES ('a@b.c','alice') ON CONFLICT ("email") WHERE ("deleted" = FALSE) DO UPDATE SET "name" = EXCLUDED."name"`,
+		},
+		{
+			dialect: dialect.SQLite3,
+			query:   `INSERT INTO "user" ("email","name") VALUES ('a@b.c','alice') ON CONFLICT ("email") WHERE ("deleted" = FALSE) DO UPDATE SET "name" = EXCLUDED."name"`,
+		},
+		{
+			dialect: dialect.MySQL,
+			query:   "INSERT INTO `user` (`email`,`name`) VALUES ('a@b.c','alice') ON DUPLICATE KEY UPDATE `name` = VALUES(`name`)",
+		},
+	} {
+		query, err := InterpolateForDialect("?", []interface{}{builder}, test.dialect)
+		require.NoError(t, err)
+		require.Equal(t, test.query, query)
+	}
+}
+
+func TestInsertRecordTagOptions(t *testing.T) {
+	sess, _ := newMockSession(t, dialect.MySQL)
+	sess.TagName = "sql"
+
+	type user struct {
+		UserID    int64  `sql:"user_id,pk"`
+		Name      string `sql:"full_name"`
+		CreatedAt string `sql:"created_at,readonly"`
+	}
+	u := &user{Name: "alice", CreatedAt: "now"}
+	builder := sess.InsertInto("user").Record(u)
+	require.Equal(t, []string{"full_name"}, builder.Column)
+	require.Equal(t, &u.UserID, builder.RecordID)
+
+	u.UserID = 7
+	builder = sess.InsertInto("user").Record(u)
+	query, err := InterpolateForDialect("?", []interface{}{builder}, dialect.MySQL)
+	require.NoError(t, err)
+	require.Equal(t, "INSERT INTO `user` (`user_id`,`full_name`) VALUES (7,'alice')", query)
+}
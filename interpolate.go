@@ -12,7 +12,12 @@ type interpolator struct {
 	Buffer
 	Dialect
 	IgnoreBinary bool
-	N            int
+	// Prepared writes placeholders of all values instead of interpolating
+	// them, so that the query can be prepared once for different values.
+	Prepared bool
+	N        int
+	// Time is the TimeOptions of the session, or nil.
+	Time *TimeOptions
 }
 
 // InterpolateForDialect replaces placeholder
@@ -91,7 +96,8 @@ func (i *interpolator) interpolate(query string, value []interface{}, topLevel b
 }
 
 var (
-	typeTime = reflect.TypeOf(time.Time{})
+	typeTime          = reflect.TypeOf(time.Time{})
+	typeDialectValuer = reflect.TypeOf((*DialectValuer)(nil)).Elem()
 )
 
 func (i *interpolator) encodePlaceholder(value interface{}, topLevel bool) error {
@@ -103,7 +109,7 @@ func (i *interpolator) encodePlaceholder(value interface{}, topLevel bool) error
 		}
 		paren := false
 		switch value.(type) {
-		case *SelectStmt, *union:
+		case *SelectStmt, *UnionStmt:
 			paren = !topLevel
 		}
 		if paren {
@@ -119,7 +125,33 @@ func (i *interpolator) encodePlaceholder(value interface{}, topLevel bool) error
 		return nil
 	}
 
-	if valuer, ok := value.(driver.Valuer); ok {
+	if v := reflect.ValueOf(value); v.Kind() == reflect.Ptr && v.IsNil() &&
+		(v.Type().Elem().Implements(typeValuer) || v.Type().Elem().Implements(typeDialectValuer)) {
+		// like database/sql, a nil pointer is NULL
+		// instead of calling the value method with it
+		i.WriteString("NULL")
+		return nil
+	}
+
+	if v := reflect.ValueOf(value); v.IsValid() && v.Kind() != reflect.Ptr {
+		if pt := reflect.PtrTo(v.Type()); pt.Implements(typeValuer) || pt.Implements(typeDialectValuer) {
+			if _, ok := value.(driver.Valuer); !ok {
+				// the value method is declared on the pointer,
+				// like a custom id type of Record
+				p := reflect.New(v.Type())
+				p.Elem().Set(v)
+				value = p.Interface()
+			}
+		}
+	}
+
+	if valuer, ok := value.(DialectValuer); ok {
+		var err error
+		value, err = valuer.DialectValue(i.Dialect)
+		if err != nil {
+			return err
+		}
+	} else if valuer, ok := value.(driver.Valuer); ok {
 		// get driver.Valuer's data
 		var err error
 		value, err = valuer.Value()
@@ -128,11 +160,23 @@ func (i *interpolator) encodePlaceholder(value interface{}, topLevel bool) error
 		}
 	}
 
+	if t, ok := value.(time.Time); ok && i.Time != nil {
+		value = i.Time.apply(t)
+	}
+
 	if value == nil {
 		i.WriteString("NULL")
 		return nil
 	}
 	v := reflect.ValueOf(value)
+	if i.Prepared {
+		if arg, ok := driverArg(v); ok {
+			i.WriteString(i.Placeholder(i.N))
+			i.N++
+			i.WriteValue(arg)
+			return nil
+		}
+	}
 	switch v.Kind() {
 	case reflect.String:
 		i.WriteString(i.EncodeString(v.String()))
@@ -185,3 +229,29 @@ func (i *interpolator) encodePlaceholder(value interface{}, topLevel bool) error
 	}
 	return ErrNotSupported
 }
+
+// driverArg converts v to the driver value of a placeholder,
+// or returns false if it is not a single value, like a slice of IN.
+func driverArg(v reflect.Value) (interface{}, bool) {
+	switch v.Kind() {
+	case reflect.String:
+		return v.String(), true
+	case reflect.Bool:
+		return v.Bool(), true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int(), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return v.Uint(), true
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), true
+	case reflect.Struct:
+		if v.Type() == typeTime {
+			return v.Interface(), true
+		}
+	case reflect.Slice:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			return v.Bytes(), true
+		}
+	}
+	return nil, false
+}
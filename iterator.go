@@ -1,6 +1,7 @@
 package dbr
 
 import (
+	"context"
 	"database/sql"
 	"reflect"
 )
@@ -64,12 +65,11 @@ func (m *recordMeta) scan(rows *sql.Rows, value interface{}) (err error) {
 
 	// Before scanning, set nil pointer to dummy dest.
 	// After that, reset pointers to nil for the next batch.
-	for i := range m.ptr {
-		if m.ptr[i] == nil {
-			m.ptr[i] = dummyDest
-		}
+	err = m.ts.fillDummy(m.columns, m.ptr)
+	if err != nil {
+		return
 	}
-	err = rows.Scan(m.ptr...)
+	err = scanRow(rows, m.ptr, m.ts.time)
 	if err != nil {
 		return
 	}
@@ -91,7 +91,7 @@ func (m *recordMeta) scan(rows *sql.Rows, value interface{}) (err error) {
 	return
 }
 
-func newRecordMeta(column []string, value interface{}) (meta *recordMeta, err error) {
+func newRecordMeta(column []string, value interface{}, s *tagStore) (meta *recordMeta, err error) {
 	ptr := make([]interface{}, len(column))
 
 	var v reflect.Value
@@ -116,7 +116,6 @@ func newRecordMeta(column []string, value interface{}) (meta *recordMeta, err er
 		v.Set(reflect.MakeMap(v.Type()))
 	}
 
-	s := newTagStore()
 	return &recordMeta{
 		elemType:      elemType,
 		isSlice:       isSlice,
@@ -133,6 +132,7 @@ type iteratorInternals struct {
 	rows       *sql.Rows
 	recordMeta *recordMeta
 	columns    []string
+	tagStore   *tagStore
 }
 
 // Next prepares the next result row for reading with the Scan method.
@@ -147,7 +147,7 @@ func (i *iteratorInternals) Next() bool {
 func (i *iteratorInternals) Scan(value interface{}) (err error) {
 	// First scan
 	if i.recordMeta == nil {
-		i.recordMeta, err = newRecordMeta(i.columns, value)
+		i.recordMeta, err = newRecordMeta(i.columns, value, i.tagStore)
 		if err != nil {
 			return err
 		}
@@ -170,3 +170,38 @@ func (i *iteratorInternals) Close() error {
 func (i *iteratorInternals) Err() error {
 	return i.rows.Err()
 }
+
+// iterate executes the query and returns the Iterator.
+func iterate(ctx context.Context, runner runner, log EventReceiver, builder Builder, d Dialect) (Iterator, error) {
+	_, rows, err := queryRows(ctx, runner, log, builder, d)
+	if err != nil {
+		if rows != nil {
+			rows.Close()
+		}
+		return nil, err
+	}
+	columns, err := rows.Columns()
+	if err != nil {
+		rows.Close()
+		return nil, err
+	}
+	return &iteratorInternals{
+		rows:     rows,
+		columns:  columns,
+		tagStore: newTagStoreFor(runner),
+	}, nil
+}
+
+// forEach scans each row of iter into dest and calls fn, and closes iter.
+func forEach(iter Iterator, dest interface{}, fn func() error) error {
+	defer iter.Close()
+	for iter.Next() {
+		if err := iter.Scan(dest); err != nil {
+			return err
+		}
+		if err := fn(); err != nil {
+			return err
+		}
+	}
+	return iter.Err()
+}
@@ -0,0 +1,37 @@
+package dbr
+
+import (
+	"testing"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/jiyeyuran/dbr/v2/dialect"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIterator(t *testing.T) {
+	sess, mock := newMockSession(t, dialect.MySQL)
+
+	type suggestion struct {
+		ID    int64
+		Title string
+	}
+	mock.ExpectQuery("SELECT `id`, `title` FROM `a` UNION SELECT `id`, `title` FROM `b`").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "title"}).AddRow(1, "a").AddRow(2, "b"))
+	iter, err := Union(
+		sess.Select("id", "title").From("a"),
+		sess.Select("id", "title").From("b"),
+	).Iterate()
+	require.NoError(t, err)
+
+	var got []suggestion
+	for iter.Next() {
+		var s suggestion
+		require.NoError(t, iter.Scan(&s))
+		got = append(got, s)
+	}
+	require.NoError(t, iter.Err())
+	require.NoError(t, iter.Close())
+	require.Equal(t, []suggestion{{1, "a"}, {2, "b"}}, got)
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
@@ -1,5 +1,7 @@
 package dbr
 
+import "github.com/jiyeyuran/dbr/v2/dialect"
+
 type joinType uint8
 
 const (
@@ -7,6 +9,7 @@ const (
 	left
 	right
 	full
+	cross
 )
 
 func join(t joinType, table interface{}, on interface{}) Builder {
@@ -18,7 +21,12 @@ func join(t joinType, table interface{}, on interface{}) Builder {
 		case right:
 			buf.WriteString("RIGHT ")
 		case full:
+			if !dialect.CapabilitiesOf(d).SupportsFullJoin {
+				return errDialectNotSupported("FULL JOIN")
+			}
 			buf.WriteString("FULL ")
+		case cross:
+			buf.WriteString("CROSS ")
 		}
 		buf.WriteString("JOIN ")
 		switch table := table.(type) {
@@ -28,6 +36,9 @@ func join(t joinType, table interface{}, on interface{}) Builder {
 			buf.WriteString(placeholder)
 			buf.WriteValue(table)
 		}
+		if t == cross {
+			return nil
+		}
 		buf.WriteString(" ON ")
 		switch on := on.(type) {
 		case string:
@@ -39,3 +50,35 @@ func join(t joinType, table interface{}, on interface{}) Builder {
 		return nil
 	})
 }
+
+// joinClause is an inner join in UpdateStmt and DeleteStmt.
+// Unlike SelectStmt, the syntax depends on the dialect, so the table
+// and the condition are kept apart.
+type joinClause struct {
+	table interface{}
+	on    interface{}
+}
+
+func (j *joinClause) Build(d Dialect, buf Buffer) error {
+	return join(inner, j.table, j.on).Build(d, buf)
+}
+
+func (j *joinClause) buildTable(d Dialect, buf Buffer) {
+	switch table := j.table.(type) {
+	case string:
+		buf.WriteString(d.QuoteIdent(table))
+	default:
+		buf.WriteString(placeholder)
+		buf.WriteValue(table)
+	}
+}
+
+func (j *joinClause) cond() Builder {
+	switch on := j.on.(type) {
+	case string:
+		return Expr(on)
+	case Builder:
+		return on
+	}
+	return nil
+}
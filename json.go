@@ -0,0 +1,189 @@
+package dbr
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+
+	"github.com/jiyeyuran/dbr/v2/dialect"
+)
+
+// JSONText is raw JSON for TEXT, JSON and JSONB columns.
+// The empty JSONText is JSON null.
+type JSONText []byte
+
+// NewJSONText encodes v as JSONText.
+func NewJSONText(v interface{}) (JSONText, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return JSONText(b), nil
+}
+
+// String returns the raw JSON.
+func (j JSONText) String() string {
+	if len(j) == 0 {
+		return string(nullString)
+	}
+	return string(j)
+}
+
+// UnmarshalInto decodes the raw JSON into dest.
+func (j JSONText) UnmarshalInto(dest interface{}) error {
+	return json.Unmarshal([]byte(j.String()), dest)
+}
+
+// Scan implements the Scanner interface.
+func (j *JSONText) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case []byte:
+		// the driver may reuse the bytes
+		*j = append((*j)[:0], v...)
+	case string:
+		*j = append((*j)[:0], v...)
+	case nil:
+		*j = nil
+	default:
+		return fmt.Errorf("dbr: cannot scan %T into JSONText", value)
+	}
+	if len(*j) > 0 && !json.Valid(*j) {
+		return fmt.Errorf("dbr: invalid JSON %q", *j)
+	}
+	return nil
+}
+
+// Value implements the driver Valuer interface.
+// The value is a string, which is accepted by JSON columns of the drivers.
+func (j JSONText) Value() (driver.Value, error) {
+	return j.String(), nil
+}
+
+// Build builds the JSON value for the dialect, which is `PARSE_JSON(...)`
+// in snowflake and bigquery, where strings cannot be assigned to JSON columns.
+func (j JSONText) Build(d Dialect, buf Buffer) error {
+	if dialect.CapabilitiesOf(d).ParseJSON {
+		buf.WriteString("PARSE_JSON(")
+		buf.WriteString(placeholder)
+		buf.WriteString(")")
+	} else {
+		buf.WriteString(placeholder)
+	}
+	buf.WriteValue(j.String())
+	return nil
+}
+
+// MarshalJSON returns the raw JSON.
+func (j JSONText) MarshalJSON() ([]byte, error) {
+	return []byte(j.String()), nil
+}
+
+// UnmarshalJSON copies the raw JSON.
+func (j *JSONText) UnmarshalJSON(b []byte) error {
+	*j = append((*j)[:0], b...)
+	return nil
+}
+
+// NullJSON is a type that can be null or a JSONText.
+// Unlike JSONText, SQL NULL and JSON null are distinguished.
+type NullJSON struct {
+	JSON  JSONText
+	Valid bool // Valid is true if JSON is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (n *NullJSON) Scan(value interface{}) error {
+	if value == nil {
+		n.JSON, n.Valid = nil, false
+		return nil
+	}
+	err := n.JSON.Scan(value)
+	n.Valid = err == nil
+	return err
+}
+
+// Value implements the driver Valuer interface.
+func (n NullJSON) Value() (driver.Value, error) {
+	if !n.Valid {
+		return nil, nil
+	}
+	return n.JSON.Value()
+}
+
+// Build builds NULL or the JSON value for the dialect.
+func (n NullJSON) Build(d Dialect, buf Buffer) error {
+	if !n.Valid {
+		buf.WriteString("NULL")
+		return nil
+	}
+	return n.JSON.Build(d, buf)
+}
+
+// UnmarshalInto decodes the JSON into dest, which is left unchanged if n is NULL.
+func (n NullJSON) UnmarshalInto(dest interface{}) error {
+	if !n.Valid {
+		return nil
+	}
+	return n.JSON.UnmarshalInto(dest)
+}
+
+// MarshalJSON correctly serializes a NullJSON to JSON.
+func (n NullJSON) MarshalJSON() ([]byte, error) {
+	if n.Valid {
+		return n.JSON.MarshalJSON()
+	}
+	return nullString, nil
+}
+
+// UnmarshalJSON correctly deserializes a NullJSON from JSON.
+func (n *NullJSON) UnmarshalJSON(b []byte) error {
+	if bytes.Equal(b, nullString) {
+		return n.Scan(nil)
+	}
+	err := n.JSON.UnmarshalJSON(b)
+	n.Valid = err == nil
+	return err
+}
+
+// NewNullJSON encodes v as NullJSON, which is NULL if v is nil.
+func NewNullJSON(v interface{}) (NullJSON, error) {
+	if v == nil {
+		return NullJSON{}, nil
+	}
+	j, err := NewJSONText(v)
+	if err != nil {
+		return NullJSON{}, err
+	}
+	return NullJSON{JSON: j, Valid: true}, nil
+}
+
+// NullJSONFrom creates a valid NullJSON.
+func NullJSONFrom(v JSONText) NullJSON {
+	return NullJSON{JSON: v, Valid: true}
+}
+
+// NullJSONFromPtr creates a NullJSON that is NULL if p is nil.
+func NullJSONFromPtr(p *JSONText) (n NullJSON) {
+	if p != nil {
+		n = NullJSONFrom(*p)
+	}
+	return
+}
+
+// Ptr returns a pointer to the value, or nil if n is NULL.
+func (n NullJSON) Ptr() *JSONText {
+	if !n.Valid {
+		return nil
+	}
+	v := n.JSON
+	return &v
+}
+
+// ValueOrZero returns the value, or the zero value if n is NULL.
+func (n NullJSON) ValueOrZero() JSONText {
+	if !n.Valid {
+		return nil
+	}
+	return n.JSON
+}
@@ -0,0 +1,77 @@
+package dbr
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/jiyeyuran/dbr/v2/dialect"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJSONText(t *testing.T) {
+	var j JSONText
+	require.NoError(t, j.Scan([]byte(`{"tags":["a","b"]}`)))
+
+	var dest struct {
+		Tags []string `json:"tags"`
+	}
+	require.NoError(t, j.UnmarshalInto(&dest))
+	require.Equal(t, []string{"a", "b"}, dest.Tags)
+
+	require.Error(t, j.Scan("{"))
+	require.NoError(t, j.Scan(nil))
+	require.Equal(t, "null", j.String())
+
+	builder := InsertInto("event").Columns("payload").Values(JSONText(`{"a":1}`))
+	for _, test := range []struct {
+		dialect Dialect
+		query   string
+	}{
+		{
+			dialect: dialect.PostgreSQL,
+			query:   `INSERT INTO "event" ("payload") VALUES ('{"a":1}')`,
+		},
+		{
+			dialect: dialect.BigQuery,
+			query:   "INSERT INTO `event` (`payload`) VALUES (PARSE_JSON('{\"a\":1}'))",
+		},
+	} {
+		query, err := InterpolateForDialect("?", []interface{}{builder}, test.dialect)
+		require.NoError(t, err)
+		require.Equal(t, test.query, query)
+	}
+}
+
+func TestNullJSON(t *testing.T) {
+	n, err := NewNullJSON(map[string]int{"a": 1})
+	require.NoError(t, err)
+	require.Equal(t, NullJSON{JSON: JSONText(`{"a":1}`), Valid: true}, n)
+
+	var m map[string]int
+	require.NoError(t, n.UnmarshalInto(&m))
+	require.Equal(t, map[string]int{"a": 1}, m)
+
+	var scanned NullJSON
+	require.NoError(t, scanned.Scan("null"))
+	require.True(t, scanned.Valid)
+	require.NoError(t, scanned.Scan(nil))
+	require.False(t, scanned.Valid)
+
+	query, err := InterpolateForDialect("?", []interface{}{NullJSON{}}, dialect.MySQL)
+	require.NoError(t, err)
+	require.Equal(t, "NULL", query)
+
+	var out struct {
+		Meta  NullJSON `json:"meta"`
+		Extra NullJSON `json:"extra"`
+	}
+	in := `{"meta":{"a":[1,2]},"extra":null}`
+	err = json.Unmarshal([]byte(in), &out)
+	require.NoError(t, err)
+	require.Equal(t, JSONText(`{"a":[1,2]}`), out.Meta.JSON)
+	require.False(t, out.Extra.Valid)
+
+	b, err := json.Marshal(out)
+	require.NoError(t, err)
+	require.Equal(t, in, string(b))
+}
@@ -0,0 +1,209 @@
+package dbr
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// Asc and Desc are directions for Seek.
+const (
+	Asc  direction = asc
+	Desc direction = desc
+)
+
+// KeysetColumn is a column, and its value in the last row of the previous page.
+type KeysetColumn struct {
+	Column string
+	Value  interface{}
+}
+
+// Keyset is a position in the result ordered by its columns.
+// Columns should be unique together, like (created_at, id).
+type Keyset []KeysetColumn
+
+// KeysetOf creates a Keyset from columns of a struct,
+// which is usually the last record loaded.
+// It returns *UnmappedColumnsError if some columns have no struct fields.
+func KeysetOf(value interface{}, column ...string) (Keyset, error) {
+	return keysetOf(newTagStore(), value, column)
+}
+
+// KeysetOf creates a Keyset from columns of a struct,
+// with the NameMapper and TagName of the session.
+func (sess *Session) KeysetOf(value interface{}, column ...string) (Keyset, error) {
+	return keysetOf(newTagStoreFor(sess), value, column)
+}
+
+// KeysetOf creates a Keyset from columns of a struct,
+// with the NameMapper and TagName of the transaction.
+func (tx *Tx) KeysetOf(value interface{}, column ...string) (Keyset, error) {
+	return keysetOf(newTagStoreFor(tx), value, column)
+}
+
+func keysetOf(s *tagStore, value interface{}, column []string) (Keyset, error) {
+	found := make([]interface{}, len(column))
+	s.findValueByName(reflect.ValueOf(value), column, found, false)
+
+	k := make(Keyset, len(column))
+	var unmapped []string
+	for i, col := range column {
+		k[i].Column = col
+		v, ok := found[i].(reflect.Value)
+		if !ok {
+			unmapped = append(unmapped, col)
+			continue
+		}
+		k[i].Value = v.Interface()
+	}
+	if len(unmapped) > 0 {
+		return nil, &UnmappedColumnsError{Columns: unmapped}
+	}
+	return k, nil
+}
+
+func (k Keyset) columns() []string {
+	column := make([]string, len(k))
+	for i := range k {
+		column[i] = k[i].Column
+	}
+	return column
+}
+
+// values returns the values of k, or nil if k has no values, which is the
+// first page. It returns ErrInvalidKeyset if only some values are nil.
+func (k Keyset) values() ([]interface{}, error) {
+	var value []interface{}
+	var null []string
+	for i := range k {
+		if k[i].Value == nil {
+			null = append(null, k[i].Column)
+			continue
+		}
+		value = append(value, k[i].Value)
+	}
+	if len(value) == 0 {
+		return nil, nil
+	}
+	if len(null) > 0 {
+		return nil, fmt.Errorf("%w: no values of %s", ErrInvalidKeyset, strings.Join(null, ", "))
+	}
+	return value, nil
+}
+
+type cursorColumn struct {
+	Column string          `json:"c"`
+	Time   bool            `json:"t,omitempty"`
+	Value  json.RawMessage `json:"v"`
+}
+
+// Cursor encodes the keyset into an opaque string, which can be
+// passed to clients for fetching the next page.
+func (k Keyset) Cursor() (string, error) {
+	cursor := make([]cursorColumn, len(k))
+	for i, kc := range k {
+		v := kc.Value
+		if valuer, ok := v.(interface {
+			Value() (interface{}, error)
+		}); ok {
+			var err error
+			v, err = valuer.Value()
+			if err != nil {
+				return "", err
+			}
+		}
+		b, err := json.Marshal(v)
+		if err != nil {
+			return "", err
+		}
+		_, isTime := v.(time.Time)
+		cursor[i] = cursorColumn{
+			Column: kc.Column,
+			Time:   isTime,
+			Value:  b,
+		}
+	}
+	b, err := json.Marshal(cursor)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// ParseCursor decodes a cursor created by Keyset.Cursor, which must have
+// the columns in order. The columns are written into the query by Seek,
+// so a cursor from clients is never trusted for them.
+func ParseCursor(cursor string, column ...string) (Keyset, error) {
+	b, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	var cc []cursorColumn
+	err = json.Unmarshal(b, &cc)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	if len(cc) != len(column) {
+		return nil, ErrInvalidCursor
+	}
+	k := make(Keyset, len(cc))
+	for i, c := range cc {
+		if c.Column != column[i] {
+			return nil, ErrInvalidCursor
+		}
+		k[i].Column = c.Column
+		if c.Time {
+			var t time.Time
+			err = json.Unmarshal(c.Value, &t)
+			if err != nil {
+				return nil, ErrInvalidCursor
+			}
+			k[i].Value = t
+			continue
+		}
+		dec := json.NewDecoder(bytes.NewReader(c.Value))
+		dec.UseNumber()
+		err = dec.Decode(&k[i].Value)
+		if err != nil {
+			return nil, ErrInvalidCursor
+		}
+		if n, ok := k[i].Value.(json.Number); ok {
+			if v, err := n.Int64(); err == nil {
+				k[i].Value = v
+			} else if v, err := n.Float64(); err == nil {
+				k[i].Value = v
+			}
+		}
+	}
+	return k, nil
+}
+
+// Seek fetches limit rows after the keyset in the order of its columns.
+// If the keyset has no values, it fetches the first page. If only some of
+// its values are nil, the statement fails with ErrInvalidKeyset.
+//
+// Unlike Paginate, it does not scan the skipped rows, so it is
+// fast for a large set of data.
+func (b *SelectStmt) Seek(k Keyset, dir direction, limit uint64) *SelectStmt {
+	column := k.columns()
+	value, err := k.values()
+	if err != nil {
+		b.Where(BuildFunc(func(Dialect, Buffer) error {
+			return err
+		}))
+	} else if value != nil {
+		if dir == Desc {
+			b.Where(Tuple(column...).Lt(value...))
+		} else {
+			b.Where(Tuple(column...).Gt(value...))
+		}
+	}
+	for _, col := range column {
+		b.Order = append(b.Order, order(col, dir))
+	}
+	return b.Limit(limit)
+}
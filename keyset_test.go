@@ -0,0 +1,112 @@
+package dbr
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jiyeyuran/dbr/v2/dialect"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSeek(t *testing.T) {
+	createdAt := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	for _, test := range []struct {
+		builder Builder
+		query   string
+		value   []interface{}
+	}{
+		{
+			builder: Select("*").From("post").
+				Seek(Keyset{{"created_at", nil}, {"id", nil}}, Desc, 10),
+			query: "SELECT * FROM `post` ORDER BY `created_at` DESC, `id` DESC LIMIT 10",
+		},
+		{
+			builder: Select("*").From("post").
+				Seek(Keyset{{"created_at", createdAt}, {"id", 7}}, Desc, 10),
+			query: "SELECT * FROM `post` WHERE ((`created_at`, `id`) < (?, ?)) ORDER BY `created_at` DESC, `id` DESC LIMIT 10",
+			value: []interface{}{createdAt, 7},
+		},
+		{
+			builder: Select("*").From("post").Where(Eq("author_id", 1)).
+				Seek(Keyset{{"id", 7}}, Asc, 10),
+			query: "SELECT * FROM `post` WHERE (`author_id` = ?) AND ((`id`) > (?)) ORDER BY `id` ASC LIMIT 10",
+			value: []interface{}{1, 7},
+		},
+	} {
+		buf := NewBuffer()
+		err := test.builder.Build(dialect.MySQL, buf)
+		require.NoError(t, err)
+		require.Equal(t, test.query, buf.String())
+		require.Equal(t, test.value, buf.Value())
+	}
+
+	// a keyset with some values is not the first page
+	buf := NewBuffer()
+	err := Select("*").From("post").
+		Seek(Keyset{{"created_at", createdAt}, {"id", nil}}, Desc, 10).
+		Build(dialect.MySQL, buf)
+	require.True(t, errors.Is(err, ErrInvalidKeyset))
+	require.EqualError(t, err, "dbr: invalid keyset: no values of id")
+}
+
+func TestKeysetCursor(t *testing.T) {
+	type post struct {
+		ID        int64     `db:"id"`
+		Title     string    `db:"title"`
+		CreatedAt time.Time `db:"created_at"`
+	}
+	p := &post{
+		ID:        7,
+		Title:     "123",
+		CreatedAt: time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC),
+	}
+
+	k, err := KeysetOf(p, "created_at", "title", "id")
+	require.NoError(t, err)
+	require.Equal(t, Keyset{
+		{"created_at", p.CreatedAt},
+		{"title", "123"},
+		{"id", int64(7)},
+	}, k)
+
+	cursor, err := k.Cursor()
+	require.NoError(t, err)
+
+	parsed, err := ParseCursor(cursor, "created_at", "title", "id")
+	require.NoError(t, err)
+	require.Equal(t, k, parsed)
+
+	_, err = ParseCursor("not a cursor", "id")
+	require.Equal(t, ErrInvalidCursor, err)
+
+	// the columns of a tampered cursor are rejected
+	tampered, err := Keyset{{"id = 0; DROP TABLE post; --", 1}}.Cursor()
+	require.NoError(t, err)
+	_, err = ParseCursor(tampered, "id")
+	require.Equal(t, ErrInvalidCursor, err)
+	_, err = ParseCursor(cursor, "created_at", "id")
+	require.Equal(t, ErrInvalidCursor, err)
+	_, err = ParseCursor(cursor, "title", "created_at", "id")
+	require.Equal(t, ErrInvalidCursor, err)
+
+	// the columns are found with the NameMapper of the session
+	type event struct {
+		EventID   int64
+		CreatedAt time.Time
+	}
+	sess, _ := newMockSession(t, dialect.PostgreSQL)
+	sess.NameMapper = CamelCase
+	e := &event{EventID: 3, CreatedAt: p.CreatedAt}
+	k, err = sess.KeysetOf(e, "createdAt", "eventID")
+	require.NoError(t, err)
+	require.Equal(t, Keyset{
+		{"createdAt", p.CreatedAt},
+		{"eventID", int64(3)},
+	}, k)
+
+	// the columns without struct fields are reported
+	_, err = KeysetOf(p, "created_at", "score", "id", "rank")
+	require.True(t, errors.Is(err, ErrUnmappedColumn))
+	require.Equal(t, &UnmappedColumnsError{Columns: []string{"score", "rank"}}, err)
+}
@@ -0,0 +1,138 @@
+package dbr
+
+import (
+	"strconv"
+
+	"github.com/jiyeyuran/dbr/v2/dialect"
+)
+
+// limitClause builds the limit and offset of SelectStmt and UnionStmt
+// in the LimitStyle of the dialect.
+type limitClause struct {
+	limit    int64
+	offset   int64
+	withTies bool
+	// top is whether the statement can be limited by `SELECT TOP (n)`.
+	top bool
+}
+
+func (l limitClause) empty() bool {
+	return l.limit < 0 && l.offset < 0
+}
+
+// useTop reports whether the rows are limited by `TOP (n)`,
+// which does not need ORDER BY like OFFSET.
+func (l limitClause) useTop(d Dialect) bool {
+	if !l.top || dialect.CapabilitiesOf(d).LimitStyle != dialect.TopOffsetFetch {
+		return false
+	}
+	return l.withTies || (l.limit >= 0 && l.offset < 0)
+}
+
+// buildTop builds `TOP (n)` after SELECT.
+func (l limitClause) buildTop(d Dialect, buf Buffer) error {
+	if !l.useTop(d) {
+		return nil
+	}
+	if l.withTies && l.offset >= 0 {
+		return errDialectNotSupported("OFFSET with WITH TIES")
+	}
+	buf.WriteString("TOP (")
+	buf.WriteString(strconv.FormatInt(l.limit, 10))
+	buf.WriteString(") ")
+	if l.withTies {
+		buf.WriteString("WITH TIES ")
+	}
+	return nil
+}
+
+// wrap opens the subquery that is limited by ROWNUM, which is closed by build.
+func (l limitClause) wrap(d Dialect, buf Buffer) {
+	if l.empty() || l.withTies || dialect.CapabilitiesOf(d).LimitStyle != dialect.RowNum {
+		return
+	}
+	buf.WriteString("SELECT * FROM (")
+	if l.offset >= 0 {
+		buf.WriteString("SELECT dbr_q.*, ROWNUM dbr_rownum FROM (")
+	}
+}
+
+// build builds the limit after ORDER BY.
+// order is added if the dialect requires ORDER BY for OFFSET, and it should be nil
+// if the statement is already ordered.
+func (l limitClause) build(d Dialect, buf Buffer, order Builder) error {
+	if l.empty() || l.useTop(d) {
+		return nil
+	}
+	caps := dialect.CapabilitiesOf(d)
+	if l.withTies && !caps.SupportsWithTies {
+		return errDialectNotSupported("WITH TIES")
+	}
+
+	switch caps.LimitStyle {
+	case dialect.RowNum:
+		// https://blogs.oracle.com/oraclemagazine/on-rownum-and-limiting-results
+		buf.WriteString(")")
+		if l.offset < 0 {
+			buf.WriteString(" WHERE ROWNUM <= ")
+			buf.WriteString(strconv.FormatInt(l.limit, 10))
+			return nil
+		}
+		buf.WriteString(" dbr_q")
+		if l.limit >= 0 {
+			buf.WriteString(" WHERE ROWNUM <= ")
+			buf.WriteString(strconv.FormatInt(l.offset+l.limit, 10))
+		}
+		buf.WriteString(") WHERE dbr_rownum > ")
+		buf.WriteString(strconv.FormatInt(l.offset, 10))
+	case dialect.TopOffsetFetch:
+		// https://docs.microsoft.com/en-us/previous-versions/sql/sql-server-2012/ms188385(v=sql.110)
+		if order != nil {
+			// ORDER is required for OFFSET / FETCH
+			buf.WriteString(" ORDER BY ")
+			err := order.Build(d, buf)
+			if err != nil {
+				return err
+			}
+		}
+		offset := l.offset
+		if offset < 0 {
+			offset = 0
+		}
+		buildFetch(buf, offset, l.limit, l.withTies)
+	case dialect.OffsetFetch:
+		buildFetch(buf, l.offset, l.limit, l.withTies)
+	default:
+		if l.withTies {
+			buildFetch(buf, l.offset, l.limit, l.withTies)
+			return nil
+		}
+		if l.limit >= 0 {
+			buf.WriteString(" LIMIT ")
+			buf.WriteString(strconv.FormatInt(l.limit, 10))
+		}
+		if l.offset >= 0 {
+			buf.WriteString(" OFFSET ")
+			buf.WriteString(strconv.FormatInt(l.offset, 10))
+		}
+	}
+	return nil
+}
+
+// buildFetch builds standard `OFFSET n ROWS FETCH FIRST m ROWS ONLY`.
+func buildFetch(buf Buffer, offsetCount, limitCount int64, withTies bool) {
+	if offsetCount >= 0 {
+		buf.WriteString(" OFFSET ")
+		buf.WriteString(strconv.FormatInt(offsetCount, 10))
+		buf.WriteString(" ROWS")
+	}
+	if limitCount >= 0 {
+		buf.WriteString(" FETCH FIRST ")
+		buf.WriteString(strconv.FormatInt(limitCount, 10))
+		if withTies {
+			buf.WriteString(" ROWS WITH TIES")
+		} else {
+			buf.WriteString(" ROWS ONLY")
+		}
+	}
+}
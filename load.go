@@ -1,8 +1,13 @@
 package dbr
 
 import (
+	"context"
 	"database/sql"
+	"fmt"
 	"reflect"
+	"strconv"
+	"strings"
+	"time"
 )
 
 type interfaceLoader struct {
@@ -29,12 +34,21 @@ func InterfaceLoader(value interface{}, concreteType interface{}) interface{} {
 // 4. map of slice; like map, values with the same key are
 // collected with a slice.
 func Load(rows *sql.Rows, value interface{}) (int, error) {
+	return load(rows, value, newTagStore())
+}
+
+func load(rows *sql.Rows, value interface{}, s *tagStore) (int, error) {
 	defer rows.Close()
 
 	column, err := rows.Columns()
 	if err != nil {
 		return 0, err
 	}
+	if len(column) == 1 {
+		if count, ok, err := loadPrimitives(rows, value, s.time); ok {
+			return count, err
+		}
+	}
 	ptr := make([]interface{}, len(column))
 
 	var v reflect.Value
@@ -59,7 +73,6 @@ func Load(rows *sql.Rows, value interface{}) (int, error) {
 		v.Set(reflect.MakeMap(v.Type()))
 	}
 
-	s := newTagStore()
 	count := 0
 	for rows.Next() {
 		var elem, keyElem reflect.Value
@@ -93,12 +106,11 @@ func Load(rows *sql.Rows, value interface{}) (int, error) {
 
 		// Before scanning, set nil pointer to dummy dest.
 		// After that, reset pointers to nil for the next batch.
-		for i := range ptr {
-			if ptr[i] == nil {
-				ptr[i] = dummyDest
-			}
+		err = s.fillDummy(column, ptr)
+		if err != nil {
+			return 0, err
 		}
-		err = rows.Scan(ptr...)
+		err = scanRow(rows, ptr, s.time)
 		if err != nil {
 			return 0, err
 		}
@@ -125,13 +137,350 @@ func Load(rows *sql.Rows, value interface{}) (int, error) {
 	return count, rows.Err()
 }
 
+// loadPrimitives loads a single column into the common slices
+// like ids without reflection, or returns false for other values.
+// The times are converted by opts like the other loaded times.
+func loadPrimitives(rows *sql.Rows, value interface{}, opts *TimeOptions) (int, bool, error) {
+	var count int
+	var err error
+	switch v := value.(type) {
+	case *[]int64:
+		count, err = scanSlice(rows, v)
+	case *[]int:
+		count, err = scanSlice(rows, v)
+	case *[]int32:
+		count, err = scanSlice(rows, v)
+	case *[]uint64:
+		count, err = scanSlice(rows, v)
+	case *[]float64:
+		count, err = scanSlice(rows, v)
+	case *[]string:
+		count, err = scanSlice(rows, v)
+	case *[]bool:
+		count, err = scanSlice(rows, v)
+	case *[]time.Time:
+		count, err = scanTimes(rows, v, opts)
+	default:
+		return 0, false, nil
+	}
+	return count, true, err
+}
+
+func scanSlice[T any](rows *sql.Rows, dest *[]T) (int, error) {
+	if dest == nil {
+		return 0, ErrInvalidPointer
+	}
+	count := 0
+	for rows.Next() {
+		var v T
+		err := rows.Scan(&v)
+		if err != nil {
+			return 0, err
+		}
+		*dest = append(*dest, v)
+		count++
+	}
+	return count, rows.Err()
+}
+
+// scanTimes is scanSlice of times, which are scanned with opts.
+func scanTimes(rows *sql.Rows, dest *[]time.Time, opts *TimeOptions) (int, error) {
+	if dest == nil {
+		return 0, ErrInvalidPointer
+	}
+	count := 0
+	for rows.Next() {
+		var v time.Time
+		err := scanRow(rows, []interface{}{&v}, opts)
+		if err != nil {
+			return 0, err
+		}
+		*dest = append(*dest, v)
+		count++
+	}
+	return count, rows.Err()
+}
+
+// loadPairs loads the rows of two columns into map dest,
+// or a pointer to map which is allocated if it is nil.
+// The times are converted by opts like the other loaded times.
+func loadPairs(rows *sql.Rows, dest interface{}, opts *TimeOptions) (int, error) {
+	defer rows.Close()
+
+	column, err := rows.Columns()
+	if err != nil {
+		return 0, err
+	}
+	if len(column) != 2 {
+		return 0, fmt.Errorf("dbr: LoadPairs needs 2 columns, but got %d", len(column))
+	}
+	m := reflect.ValueOf(dest)
+	if m.Kind() == reflect.Ptr && !m.IsNil() && m.Elem().Kind() == reflect.Map {
+		m = m.Elem()
+		if m.IsNil() {
+			m.Set(reflect.MakeMap(m.Type()))
+		}
+	}
+	if m.Kind() != reflect.Map || m.IsNil() {
+		return 0, ErrInvalidPointer
+	}
+
+	count := 0
+	for rows.Next() {
+		key := reflect.New(m.Type().Key())
+		value := reflect.New(m.Type().Elem())
+		err := scanRow(rows, []interface{}{key.Interface(), value.Interface()}, opts)
+		if err != nil {
+			return 0, err
+		}
+		m.SetMapIndex(key.Elem(), value.Elem())
+		count++
+	}
+	return count, rows.Err()
+}
+
+// loadMaps loads each row into a map of column name to value.
+// The bytes from drivers are converted to int64, float64 or string
+// by the database type of the column, except binary columns.
+func loadMaps(rows *sql.Rows, opts *TimeOptions) ([]map[string]interface{}, error) {
+	defer rows.Close()
+
+	column, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+	typeName := make([]string, len(column))
+	if types, err := rows.ColumnTypes(); err == nil {
+		for i, t := range types {
+			typeName[i] = strings.ToUpper(t.DatabaseTypeName())
+		}
+	}
+	value := make([]interface{}, len(column))
+	ptr := make([]interface{}, len(column))
+	for i := range value {
+		ptr[i] = &value[i]
+	}
+
+	var ret []map[string]interface{}
+	for rows.Next() {
+		err = rows.Scan(ptr...)
+		if err != nil {
+			return nil, err
+		}
+		m := make(map[string]interface{}, len(column))
+		for i, col := range column {
+			m[col] = convertBytes(value[i], typeName[i])
+			if t, ok := m[col].(time.Time); ok && opts != nil {
+				m[col] = opts.apply(t)
+			}
+		}
+		ret = append(ret, m)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// convertBytes converts the bytes of database type typeName to a Go value.
+func convertBytes(value interface{}, typeName string) interface{} {
+	b, ok := value.([]byte)
+	if !ok {
+		return value
+	}
+	s := string(b)
+	switch {
+	case strings.Contains(typeName, "BLOB"), strings.Contains(typeName, "BINARY"), typeName == "BYTEA":
+		return append([]byte{}, b...)
+	case strings.Contains(typeName, "INT"), typeName == "SERIAL":
+		if v, err := strconv.ParseInt(s, 10, 64); err == nil {
+			return v
+		}
+		if v, err := strconv.ParseUint(s, 10, 64); err == nil {
+			return v
+		}
+	case strings.Contains(typeName, "FLOAT"), strings.Contains(typeName, "DOUBLE"), typeName == "REAL":
+		if v, err := strconv.ParseFloat(s, 64); err == nil {
+			return v
+		}
+	case typeName == "BOOL", typeName == "BOOLEAN":
+		if v, err := strconv.ParseBool(s); err == nil {
+			return v
+		}
+	}
+	// DECIMAL is kept as string without losing precision
+	return s
+}
+
+// Loader is a statement that loads rows, like *SelectStmt and *UnionStmt.
+type Loader interface {
+	LoadContext(ctx context.Context, value interface{}) (int, error)
+	LoadOneContext(ctx context.Context, value interface{}) error
+}
+
+// LoadAll loads all rows of stmt into a slice of T, which can be anything
+// loaded by Load, like LoadAll[User](ctx, sess.Select("*").From("user")).
+//
+// It is named LoadAll, because Load loads from sql.Rows.
+func LoadAll[T any](ctx context.Context, stmt Loader) ([]T, error) {
+	var value []T
+	_, err := stmt.LoadContext(ctx, &value)
+	if err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+// LoadOne loads the first row of stmt into T,
+// or returns ErrNotFound if there is no row.
+func LoadOne[T any](ctx context.Context, stmt Loader) (T, error) {
+	var value T
+	err := stmt.LoadOneContext(ctx, &value)
+	return value, err
+}
+
+// LoadMap loads the rows of stmt into a map keyed by the first column,
+// and the rest of columns are loaded into V like Load.
+//
+// If V is a struct with a field of `key` tag option like `db:"id,key"`,
+// all columns are loaded into V, which is keyed by the field.
+func LoadMap[K comparable, V any](ctx context.Context, stmt Loader) (map[K]V, error) {
+	if field, ok := keyField(tagStoreOf(stmt), reflect.TypeOf((*V)(nil)).Elem()); ok {
+		var value []V
+		_, err := stmt.LoadContext(ctx, &value)
+		if err != nil {
+			return nil, err
+		}
+		m := make(map[K]V, len(value))
+		for _, v := range value {
+			var k K
+			if err := keyOf(reflect.ValueOf(v), field, &k); err != nil {
+				return nil, err
+			}
+			m[k] = v
+		}
+		return m, nil
+	}
+	var m map[K]V
+	_, err := stmt.LoadContext(ctx, &m)
+	if err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// LoadMapSlice is like LoadMap, but V with the same key are collected with a slice.
+func LoadMapSlice[K comparable, V any](ctx context.Context, stmt Loader) (map[K][]V, error) {
+	if field, ok := keyField(tagStoreOf(stmt), reflect.TypeOf((*V)(nil)).Elem()); ok {
+		var value []V
+		_, err := stmt.LoadContext(ctx, &value)
+		if err != nil {
+			return nil, err
+		}
+		m := make(map[K][]V)
+		for _, v := range value {
+			var k K
+			if err := keyOf(reflect.ValueOf(v), field, &k); err != nil {
+				return nil, err
+			}
+			m[k] = append(m[k], v)
+		}
+		return m, nil
+	}
+	var m map[K][]V
+	_, err := stmt.LoadContext(ctx, &m)
+	if err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// tagStoreOf creates a tagStore with the settings of the runner of stmt.
+func tagStoreOf(stmt Loader) *tagStore {
+	switch stmt := stmt.(type) {
+	case *SelectStmt:
+		return newTagStoreFor(stmt.runner)
+	case *UnionStmt:
+		return newTagStoreFor(stmt.runner)
+	}
+	return newTagStore()
+}
+
+// keyField returns the index of the struct field with `key` tag option.
+func keyField(s *tagStore, t reflect.Type) (int, bool) {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return 0, false
+	}
+	for i, opt := range s.options(t) {
+		if opt.Contains("key") {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// keyOf copies the key field of v to key.
+func keyOf(v reflect.Value, field int, key interface{}) error {
+	v = reflect.Indirect(v)
+	dest := reflect.ValueOf(key).Elem()
+	if !v.IsValid() {
+		return ErrInvalidPointer
+	}
+	f := v.Field(field)
+	// integers are convertible to string as runes
+	if !f.Type().ConvertibleTo(dest.Type()) || (f.Kind() == reflect.String) != (dest.Kind() == reflect.String) {
+		return fmt.Errorf("dbr: cannot use %s as map key %s", f.Type(), dest.Type())
+	}
+	dest.Set(f.Convert(dest.Type()))
+	return nil
+}
+
 func reflectAlloc(typ reflect.Type) reflect.Value {
-	if typ.Kind() == reflect.Ptr {
+	if typ.Kind() == reflect.Ptr && !isScalarPtr(typ) {
 		return reflect.New(typ.Elem())
 	}
 	return reflect.New(typ).Elem()
 }
 
+// fillDummy sets nil ptr to dummyDest, or returns *UnmappedColumnsError
+// in strict mode.
+func (s *tagStore) fillDummy(column []string, ptr []interface{}) error {
+	var unmapped []string
+	for i := range ptr {
+		if ptr[i] == nil {
+			if s.strict {
+				unmapped = append(unmapped, column[i])
+			}
+			ptr[i] = dummyDest
+		}
+	}
+	if len(unmapped) > 0 {
+		for i := range ptr {
+			ptr[i] = nil
+		}
+		return &UnmappedColumnsError{Columns: unmapped}
+	}
+	return nil
+}
+
+// UnmappedColumnsError is returned by Load in strict mode
+// if some columns have no struct fields. It wraps ErrUnmappedColumn.
+type UnmappedColumnsError struct {
+	Columns []string
+}
+
+func (e *UnmappedColumnsError) Error() string {
+	return fmt.Sprintf("dbr: columns not mapped to struct fields: %s", strings.Join(e.Columns, ", "))
+}
+
+// Unwrap returns ErrUnmappedColumn.
+func (e *UnmappedColumnsError) Unwrap() error {
+	return ErrUnmappedColumn
+}
+
 type dummyScanner struct{}
 
 func (dummyScanner) Scan(interface{}) error {
@@ -142,3 +491,43 @@ var (
 	dummyDest   sql.Scanner = dummyScanner{}
 	typeScanner             = reflect.TypeOf((*sql.Scanner)(nil)).Elem()
 )
+
+// loadRecords loads each row into the struct of records in order.
+// Rows without a valid record are discarded.
+func loadRecords(rows *sql.Rows, records []reflect.Value, s *tagStore) (int, error) {
+	defer rows.Close()
+
+	column, err := rows.Columns()
+	if err != nil {
+		return 0, err
+	}
+	ptr := make([]interface{}, len(column))
+
+	count := 0
+	for rows.Next() {
+		if count < len(records) && records[count].IsValid() {
+			err := s.findPtr(records[count], column, ptr)
+			if err != nil {
+				return 0, err
+			}
+			err = s.fillDummy(column, ptr)
+			if err != nil {
+				return 0, err
+			}
+		}
+		for i := range ptr {
+			if ptr[i] == nil {
+				ptr[i] = dummyDest
+			}
+		}
+		err = scanRow(rows, ptr, s.time)
+		if err != nil {
+			return 0, err
+		}
+		for i := range ptr {
+			ptr[i] = nil
+		}
+		count++
+	}
+	return count, rows.Err()
+}
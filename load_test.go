@@ -0,0 +1,399 @@
+package dbr
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/jiyeyuran/dbr/v2/dialect"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadGeneric(t *testing.T) {
+	sess, mock := newMockSession(t, dialect.MySQL)
+	ctx := context.Background()
+
+	type suggestion struct {
+		ID    int64
+		Title string
+	}
+	mock.ExpectQuery("SELECT `id`, `title` FROM `suggestions`").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "title"}).AddRow(1, "a").AddRow(2, "b"))
+	all, err := LoadAll[suggestion](ctx, sess.Select("id", "title").From("suggestions"))
+	require.NoError(t, err)
+	require.Equal(t, []suggestion{{1, "a"}, {2, "b"}}, all)
+
+	mock.ExpectQuery("SELECT `id` FROM `suggestions`").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	id, err := LoadOne[int64](ctx, sess.Select("id").From("suggestions"))
+	require.NoError(t, err)
+	require.Equal(t, int64(1), id)
+
+	mock.ExpectQuery("SELECT `id` FROM `suggestions`").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	_, err = LoadOne[int64](ctx, sess.Select("id").From("suggestions"))
+	require.Equal(t, ErrNotFound, err)
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestLoadNestedPrefix(t *testing.T) {
+	sess, mock := newMockSession(t, dialect.MySQL)
+
+	type author struct {
+		ID   int64
+		Name string
+	}
+	type book struct {
+		ID     int64
+		Title  string
+		Author *author `db:"author,prefix"`
+		Editor author  `db:"editor,prefix=ed_"`
+	}
+	mock.ExpectQuery("SELECT `b`\\.`id`, `b`\\.`title`, a\\.id AS author_id, a\\.name AS author_name, e\\.name AS ed_name").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "title", "author_id", "author_name", "ed_name"}).
+			AddRow(1, "Go", 2, "alice", "bob"))
+	var books []book
+	_, err := sess.Select("b.id", "b.title", "a.id AS author_id", "a.name AS author_name", "e.name AS ed_name").
+		From(I("book").As("b")).
+		Join(I("author").As("a"), "a.id = b.author_id").
+		Join(I("author").As("e"), "e.id = b.editor_id").
+		Load(&books)
+	require.NoError(t, err)
+	require.Equal(t, []book{{
+		ID:     1,
+		Title:  "Go",
+		Author: &author{ID: 2, Name: "alice"},
+		Editor: author{Name: "bob"},
+	}}, books)
+
+	found := make([]interface{}, 2)
+	newTagStore().findValueByName(reflect.ValueOf(books[0]), []string{"author_name", "ed_name"}, found, false)
+	require.Equal(t, "alice", found[0].(reflect.Value).Interface())
+	require.Equal(t, "bob", found[1].(reflect.Value).Interface())
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestLoadMap(t *testing.T) {
+	sess, mock := newMockSession(t, dialect.MySQL)
+	ctx := context.Background()
+
+	mock.ExpectQuery("SELECT `id`, `title` FROM `suggestions`").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "title"}).AddRow(1, "a").AddRow(2, "b"))
+	titles, err := LoadMap[int64, string](ctx, sess.Select("id", "title").From("suggestions"))
+	require.NoError(t, err)
+	require.Equal(t, map[int64]string{1: "a", 2: "b"}, titles)
+
+	type suggestion struct {
+		ID     int64
+		UserID int32 `db:"user_id,key"`
+		Title  string
+	}
+	mock.ExpectQuery("SELECT \\* FROM `suggestions`").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "title"}).
+			AddRow(1, 7, "a").AddRow(2, 7, "b").AddRow(3, 8, "c"))
+	byUser, err := LoadMapSlice[int64, *suggestion](ctx, sess.Select("*").From("suggestions"))
+	require.NoError(t, err)
+	require.Equal(t, map[int64][]*suggestion{
+		7: {{1, 7, "a"}, {2, 7, "b"}},
+		8: {{3, 8, "c"}},
+	}, byUser)
+
+	mock.ExpectQuery("SELECT \\* FROM `suggestions`").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "title"}).AddRow(1, 7, "a"))
+	byKey, err := LoadMap[int32, suggestion](ctx, sess.Select("*").From("suggestions"))
+	require.NoError(t, err)
+	require.Equal(t, map[int32]suggestion{7: {1, 7, "a"}}, byKey)
+
+	mock.ExpectQuery("SELECT \\* FROM `suggestions`").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "title"}))
+	byKey, err = LoadMap[int32, suggestion](ctx, sess.Select("*").From("suggestions"))
+	require.NoError(t, err)
+	require.Empty(t, byKey)
+
+	// the key is found with the TagName of the session
+	type tagged struct {
+		ID     int64 `sql:"id,key"`
+		UserID int32 `sql:"user_id"`
+	}
+	sess.TagName = "sql"
+	mock.ExpectQuery("SELECT \\* FROM `suggestions`").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id"}).AddRow(1, 7))
+	byID, err := LoadMap[int64, tagged](ctx, sess.Select("*").From("suggestions"))
+	require.NoError(t, err)
+	require.Equal(t, map[int64]tagged{1: {1, 7}}, byID)
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestLoadMaps(t *testing.T) {
+	sess, mock := newMockSession(t, dialect.MySQL)
+
+	mock.ExpectQuery("SELECT `id`, `title`, `body` FROM `suggestions`").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "title", "body"}).
+			AddRow(1, []byte("a"), nil).AddRow(2, "b", []byte("c")))
+	m, err := sess.Select("id", "title", "body").From("suggestions").LoadMaps()
+	require.NoError(t, err)
+	require.Equal(t, []map[string]interface{}{
+		{"id": int64(1), "title": "a", "body": nil},
+		{"id": int64(2), "title": "b", "body": "c"},
+	}, m)
+
+	require.Equal(t, int64(-1), convertBytes([]byte("-1"), "BIGINT"))
+	require.Equal(t, uint64(18446744073709551615), convertBytes([]byte("18446744073709551615"), "UNSIGNED BIGINT"))
+	require.Equal(t, 1.5, convertBytes([]byte("1.5"), "DOUBLE"))
+	require.Equal(t, "1.50", convertBytes([]byte("1.50"), "DECIMAL"))
+	require.Equal(t, true, convertBytes([]byte("t"), "BOOL"))
+	require.Equal(t, []byte{0}, convertBytes([]byte{0}, "VARBINARY"))
+	require.Equal(t, "x", convertBytes([]byte("x"), "INT"))
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPointerFields(t *testing.T) {
+	sess, mock := newMockSession(t, dialect.MySQL)
+
+	type suggestion struct {
+		ID        int64
+		Title     *string
+		Score     *int64
+		CreatedAt *time.Time
+	}
+	now := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	title := "a"
+
+	mock.ExpectQuery("SELECT `id`, `title`, `score`, `created_at` FROM `suggestions`").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "title", "score", "created_at"}).
+			AddRow(1, "a", nil, now).AddRow(2, nil, 3, nil))
+	var all []suggestion
+	_, err := sess.Select("id", "title", "score", "created_at").From("suggestions").Load(&all)
+	require.NoError(t, err)
+	require.Equal(t, []suggestion{
+		{ID: 1, Title: &title, CreatedAt: &now},
+		{ID: 2, Score: &[]int64{3}[0]},
+	}, all)
+
+	mock.ExpectQuery("SELECT `title` FROM `suggestions`").
+		WillReturnRows(sqlmock.NewRows([]string{"title"}).AddRow("a").AddRow(nil))
+	var titles []*string
+	_, err = sess.Select("title").From("suggestions").Load(&titles)
+	require.NoError(t, err)
+	require.Equal(t, []*string{&title, nil}, titles)
+
+	mock.ExpectQuery("SELECT `created_at` FROM `suggestions`").
+		WillReturnRows(sqlmock.NewRows([]string{"created_at"}).AddRow(nil))
+	createdAt := &now
+	require.NoError(t, sess.Select("created_at").From("suggestions").LoadOne(&createdAt))
+	require.Nil(t, createdAt)
+
+	mock.ExpectQuery("SELECT `created_at` FROM `suggestions`").
+		WillReturnRows(sqlmock.NewRows([]string{"created_at"}).AddRow(now))
+	var at time.Time
+	require.NoError(t, sess.Select("created_at").From("suggestions").LoadOne(&at))
+	require.Equal(t, now, at)
+
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `suggestions` (`id`,`title`,`score`,`created_at`) VALUES (2,NULL,3,NULL)")).
+		WillReturnResult(sqlmock.NewResult(2, 1))
+	_, err = sess.InsertInto("suggestions").Columns("id", "title", "score", "created_at").Record(&all[1]).Exec()
+	require.NoError(t, err)
+
+	old := all[0]
+	old.CreatedAt = &[]time.Time{now.In(time.FixedZone("", 3600))}[0]
+	mock.ExpectExec("^" + regexp.QuoteMeta("UPDATE `suggestions` SET `title` = NULL") + "$").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	_, err = sess.UpdateRecord("suggestions", &old, &suggestion{ID: 1, CreatedAt: &now}).Exec()
+	require.NoError(t, err)
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestStrictLoad(t *testing.T) {
+	sess, mock := newMockSession(t, dialect.MySQL)
+	sess.Strict = true
+
+	type suggestion struct {
+		ID    int64
+		Title string `db:"titel"`
+	}
+	mock.ExpectQuery("SELECT `id`, `title`, `body` FROM `suggestions`").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "title", "body"}).AddRow(1, "a", "b"))
+	var all []suggestion
+	_, err := sess.Select("id", "title", "body").From("suggestions").Load(&all)
+	require.True(t, errors.Is(err, ErrUnmappedColumn))
+	var unmapped *UnmappedColumnsError
+	require.True(t, errors.As(err, &unmapped))
+	require.Equal(t, []string{"title", "body"}, unmapped.Columns)
+
+	mock.ExpectQuery("SELECT `id`, `titel` FROM `suggestions`").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "titel"}).AddRow(1, "a"))
+	var one suggestion
+	require.NoError(t, sess.Select("id", "titel").From("suggestions").LoadOne(&one))
+	require.Equal(t, suggestion{ID: 1, Title: "a"}, one)
+
+	mock.ExpectQuery("SELECT `id`, `title` FROM `suggestions`").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "title"}).AddRow(1, "a"))
+	iter, err := sess.Select("id", "title").From("suggestions").Iterate()
+	require.NoError(t, err)
+	require.True(t, iter.Next())
+	require.True(t, errors.Is(iter.Scan(&one), ErrUnmappedColumn))
+	require.NoError(t, iter.Close())
+
+	// the fields of ColumnsScanner
+	mock.ExpectQuery("SELECT \\* FROM `orders`").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "price_amount", "price_currency", "amount", "currency", "note"}).
+			AddRow(1, 1000, "USD", 5, "EUR", "x"))
+	var orders []testOrder
+	_, err = sess.Select("*").From("orders").Load(&orders)
+	require.True(t, errors.As(err, &unmapped))
+	require.Equal(t, []string{"note"}, unmapped.Columns)
+
+	mock.ExpectQuery("SELECT `id`, `titel` FROM `suggestions`").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "titel"}).AddRow(1, "a"))
+	_, err = sess.Select("id", "titel").From("suggestions").Load(&all)
+	require.NoError(t, err)
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestStrictReturning(t *testing.T) {
+	sess, mock := newMockSession(t, dialect.PostgreSQL)
+	sess.Strict = true
+
+	type person struct {
+		ID   int64  `db:"id"`
+		Name string `db:"name"`
+	}
+	mock.ExpectQuery(`INSERT INTO "person"`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created"}).AddRow(1, "today"))
+	_, err := sess.InsertInto("person").Columns("name").
+		Record(&person{Name: "alice"}).
+		Returning("id", "created").
+		Exec()
+	var unmapped *UnmappedColumnsError
+	require.True(t, errors.As(err, &unmapped))
+	require.Equal(t, []string{"created"}, unmapped.Columns)
+
+	mock.ExpectQuery(`INSERT INTO "person"`).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	alice := &person{Name: "alice"}
+	_, err = sess.InsertInto("person").Columns("name").
+		Record(alice).
+		Returning("id").
+		Exec()
+	require.NoError(t, err)
+	require.Equal(t, &person{ID: 1, Name: "alice"}, alice)
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestScannerValuerFields(t *testing.T) {
+	sess, mock := newMockSession(t, dialect.MySQL)
+
+	type base struct {
+		Code testCode
+	}
+	type item struct {
+		base
+		Name string
+	}
+	mock.ExpectQuery("SELECT `code`, `name` FROM `items`").
+		WillReturnRows(sqlmock.NewRows([]string{"code", "name"}).AddRow([]byte("a"), "b"))
+	var items []item
+	_, err := sess.Select("code", "name").From("items").Load(&items)
+	require.NoError(t, err)
+	require.Equal(t, []item{{base: base{Code: testCode{Name: "code:a"}}, Name: "b"}}, items)
+
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `items` (`code`,`name`) VALUES ('a','b')")).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	_, err = sess.InsertInto("items").Columns("code", "name").Record(&items[0]).Exec()
+	require.NoError(t, err)
+
+	// a model embedding a Scanner is loaded by the fields
+	type tagged struct {
+		testCode
+		ID int64
+	}
+	mock.ExpectQuery("SELECT `id` FROM `items`").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	var one tagged
+	require.NoError(t, sess.Select("id").From("items").LoadOne(&one))
+	require.Equal(t, tagged{ID: 1}, one)
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestLoadPairs(t *testing.T) {
+	sess, mock := newMockSession(t, dialect.MySQL)
+
+	mock.ExpectQuery("SELECT `id`, `title` FROM `suggestions`").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "title"}).AddRow(1, "a").AddRow(2, nil))
+	titles := map[int64]NullString{3: NewNullString("c")}
+	count, err := sess.Select("id", "title").From("suggestions").LoadPairs(titles)
+	require.NoError(t, err)
+	require.Equal(t, 2, count)
+	require.Equal(t, map[int64]NullString{1: NewNullString("a"), 2: {}, 3: NewNullString("c")}, titles)
+
+	mock.ExpectQuery("SELECT `title`, `id` FROM `suggestions`").
+		WillReturnRows(sqlmock.NewRows([]string{"title", "id"}).AddRow("a", 1))
+	var ids map[string]int
+	_, err = sess.Select("title", "id").From("suggestions").LoadPairs(&ids)
+	require.NoError(t, err)
+	require.Equal(t, map[string]int{"a": 1}, ids)
+
+	mock.ExpectQuery("SELECT `id` FROM `suggestions`").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	_, err = sess.Select("id").From("suggestions").LoadPairs(&ids)
+	require.Error(t, err)
+
+	mock.ExpectQuery("SELECT `title` FROM `suggestions`").
+		WillReturnRows(sqlmock.NewRows([]string{"title"}).AddRow("a").AddRow("b"))
+	all := []string{"z"}
+	count, err = sess.Select("title").From("suggestions").Load(&all)
+	require.NoError(t, err)
+	require.Equal(t, 2, count)
+	require.Equal(t, []string{"z", "a", "b"}, all)
+
+	// the times are converted by Session.Time
+	loc := time.FixedZone("UTC+8", 8*60*60)
+	sess.Time = TimeOptions{Location: loc, Truncate: time.Second}
+	now := time.Date(2020, 1, 2, 3, 4, 5, 600000000, time.UTC)
+	want := now.Truncate(time.Second).In(loc)
+	mock.ExpectQuery("SELECT `id`, `created_at` FROM `suggestions`").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow(1, now))
+	var created map[int64]time.Time
+	_, err = sess.Select("id", "created_at").From("suggestions").LoadPairs(&created)
+	require.NoError(t, err)
+	require.Equal(t, want, created[1])
+	require.Equal(t, loc, created[1].Location())
+
+	mock.ExpectQuery("SELECT `created_at` FROM `suggestions`").
+		WillReturnRows(sqlmock.NewRows([]string{"created_at"}).AddRow(now))
+	var times []time.Time
+	_, err = sess.Select("created_at").From("suggestions").Load(&times)
+	require.NoError(t, err)
+	require.Equal(t, []time.Time{want}, times)
+	require.Equal(t, loc, times[0].Location())
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+// testCode is loaded from a single column, although it has exported fields.
+type testCode struct {
+	Name string
+}
+
+func (c *testCode) Scan(value interface{}) error {
+	c.Name = "code:" + string(value.([]byte))
+	return nil
+}
+
+func (c *testCode) Value() (driver.Value, error) {
+	return strings.TrimPrefix(c.Name, "code:"), nil
+}
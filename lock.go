@@ -0,0 +1,28 @@
+package dbr
+
+import "github.com/jiyeyuran/dbr/v2/dialect"
+
+// rowLock builds row-locking clause like `FOR UPDATE`.
+type rowLock struct {
+	share      bool
+	skipLocked bool
+	noWait     bool
+}
+
+func (l *rowLock) Build(d Dialect, buf Buffer) error {
+	clause := "FOR UPDATE"
+	if l.share {
+		clause = "FOR SHARE"
+	}
+	caps := dialect.CapabilitiesOf(d)
+	if l.share && !caps.SupportsForShare || !l.share && !caps.SupportsForUpdate {
+		return errDialectNotSupported(clause)
+	}
+	buf.WriteString(clause)
+	if l.skipLocked {
+		buf.WriteString(" SKIP LOCKED")
+	} else if l.noWait {
+		buf.WriteString(" NOWAIT")
+	}
+	return nil
+}
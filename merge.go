@@ -0,0 +1,232 @@
+package dbr
+
+import (
+	"context"
+	"time"
+
+	"github.com/jiyeyuran/dbr/v2/dialect"
+)
+
+// MergeStmt builds `MERGE INTO ...` in mssql, oracle, snowflake, bigquery and postgres 15+.
+// In mysql, it is emulated with `INSERT ... SELECT ... ON DUPLICATE KEY UPDATE`.
+type MergeStmt struct {
+	runner
+	EventReceiver
+	Dialect
+
+	Table       string
+	Source      interface{}
+	SourceAlias string
+	OnCond      Builder
+
+	UpdateValue  map[string]interface{}
+	InsertColumn []string
+	InsertValue  []interface{}
+
+	timeout time.Duration
+}
+
+type MergeBuilder = MergeStmt
+
+func (b *MergeStmt) Build(d Dialect, buf Buffer) error {
+	if b.Table == "" || b.Source == nil {
+		return ErrTableNotSpecified
+	}
+	if len(b.UpdateValue) == 0 && len(b.InsertColumn) == 0 {
+		return ErrColumnNotSpecified
+	}
+	if len(b.InsertColumn) != len(b.InsertValue) {
+		return ErrPlaceholderCount
+	}
+
+	caps := dialect.CapabilitiesOf(d)
+	switch {
+	case caps.SupportsMerge:
+	case caps.SupportsOnDuplicateKey:
+		return b.buildUpsert(d, buf)
+	default:
+		return errDialectNotSupported("MERGE")
+	}
+
+	buf.WriteString("MERGE INTO ")
+	buf.WriteString(quoteTable(d, b.runner, b.Table))
+	buf.WriteString(" USING ")
+	b.buildSource(d, buf)
+	// oracle requires parentheses
+	buf.WriteString(" ON (")
+	if b.OnCond != nil {
+		err := b.OnCond.Build(d, buf)
+		if err != nil {
+			return err
+		}
+	}
+	buf.WriteString(")")
+
+	if len(b.UpdateValue) > 0 {
+		buf.WriteString(" WHEN MATCHED THEN UPDATE SET ")
+		buildAssignments(d, buf, b.UpdateValue)
+	}
+
+	if len(b.InsertColumn) > 0 {
+		buf.WriteString(" WHEN NOT MATCHED THEN INSERT ")
+		b.buildInsertValues(d, buf)
+	}
+
+	if d == dialect.MSSQL {
+		// mssql requires MERGE to be terminated
+		buf.WriteString(";")
+	}
+	return nil
+}
+
+// buildUpsert emulates MERGE in mysql, which matches rows by unique keys instead of OnCond.
+func (b *MergeStmt) buildUpsert(d Dialect, buf Buffer) error {
+	if len(b.InsertColumn) == 0 {
+		return errDialectNotSupported("MERGE without WHEN NOT MATCHED")
+	}
+
+	if len(b.UpdateValue) == 0 {
+		buf.WriteString("INSERT IGNORE INTO ")
+	} else {
+		buf.WriteString("INSERT INTO ")
+	}
+	buf.WriteString(quoteTable(d, b.runner, b.Table))
+	buf.WriteString(" (")
+	for i, col := range b.InsertColumn {
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+		buf.WriteString(d.QuoteIdent(col))
+	}
+	buf.WriteString(") SELECT ")
+	for i, v := range b.InsertValue {
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+		buf.WriteString(placeholder)
+		buf.WriteValue(v)
+	}
+	buf.WriteString(" FROM ")
+	b.buildSource(d, buf)
+
+	if len(b.UpdateValue) > 0 {
+		buf.WriteString(" ON DUPLICATE KEY UPDATE ")
+		buildAssignments(d, buf, b.UpdateValue)
+	}
+	return nil
+}
+
+func (b *MergeStmt) buildSource(d Dialect, buf Buffer) {
+	switch source := b.Source.(type) {
+	case string:
+		buf.WriteString(d.QuoteIdent(source))
+	default:
+		buf.WriteString(placeholder)
+		buf.WriteValue(source)
+	}
+	if b.SourceAlias != "" {
+		// oracle does not allow AS for table alias
+		if dialect.CapabilitiesOf(d).SupportsTableAliasAs {
+			buf.WriteString(" AS")
+		}
+		buf.WriteString(" ")
+		buf.WriteString(d.QuoteIdent(b.SourceAlias))
+	}
+}
+
+func (b *MergeStmt) buildInsertValues(d Dialect, buf Buffer) {
+	buf.WriteString("(")
+	for i, col := range b.InsertColumn {
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+		buf.WriteString(d.QuoteIdent(col))
+	}
+	buf.WriteString(") VALUES (")
+	for i, v := range b.InsertValue {
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+		buf.WriteString(placeholder)
+		buf.WriteValue(v)
+	}
+	buf.WriteString(")")
+}
+
+// MergeInto creates a MergeStmt.
+func MergeInto(table string) *MergeStmt {
+	return &MergeStmt{
+		Table: table,
+	}
+}
+
+// MergeInto creates a MergeStmt.
+func (sess *Session) MergeInto(table string) *MergeStmt {
+	b := MergeInto(table)
+	b.runner = sess
+	b.EventReceiver = sess.EventReceiver
+	b.Dialect = sess.Dialect
+	return b
+}
+
+// MergeInto creates a MergeStmt.
+func (tx *Tx) MergeInto(table string) *MergeStmt {
+	b := MergeInto(table)
+	b.runner = tx
+	b.EventReceiver = tx.EventReceiver
+	b.Dialect = tx.Dialect
+	return b
+}
+
+// Using specifies the source rows with alias.
+// source can be Builder like SelectStmt, or string.
+func (b *MergeStmt) Using(source interface{}, alias string) *MergeStmt {
+	b.Source = source
+	b.SourceAlias = alias
+	return b
+}
+
+// On specifies how the source rows match the table.
+// query can be Builder or string. value is used only if query type is string.
+// It is ignored in mysql, which matches rows by unique keys.
+func (b *MergeStmt) On(query interface{}, value ...interface{}) *MergeStmt {
+	switch query := query.(type) {
+	case string:
+		b.OnCond = Expr(query, value...)
+	case Builder:
+		b.OnCond = query
+	}
+	return b
+}
+
+// WhenMatchedUpdate updates the matched rows.
+func (b *MergeStmt) WhenMatchedUpdate(value map[string]interface{}) *MergeStmt {
+	b.UpdateValue = value
+	return b
+}
+
+// WhenNotMatchedInsert inserts the source rows that are not matched.
+// The values are usually the columns of the source like I("s.id").
+func (b *MergeStmt) WhenNotMatchedInsert(column []string, value ...interface{}) *MergeStmt {
+	b.InsertColumn = column
+	b.InsertValue = value
+	return b
+}
+
+// Timeout cancels the statement after d like SelectStmt.Timeout.
+func (b *MergeStmt) Timeout(d time.Duration) *MergeStmt {
+	b.timeout = d
+	return b
+}
+
+func (b *MergeStmt) statementTimeout() time.Duration {
+	return b.timeout
+}
+
+func (b *MergeStmt) Exec() (*Result, error) {
+	return b.ExecContext(context.Background())
+}
+
+func (b *MergeStmt) ExecContext(ctx context.Context) (*Result, error) {
+	return exec(ctx, b.runner, b.EventReceiver, b, b.Dialect)
+}
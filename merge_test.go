@@ -0,0 +1,55 @@
+package dbr
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/jiyeyuran/dbr/v2/dialect"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMergeStmt(t *testing.T) {
+	builder := MergeInto("stock").
+		Using(Select("item_id", "qty").From("delivery").Where(Eq("day", 1)), "d").
+		On("stock.item_id = d.item_id").
+		WhenMatchedUpdate(map[string]interface{}{
+			"qty": Expr("stock.qty + d.qty"),
+		}).
+		WhenNotMatchedInsert([]string{"item_id", "qty"}, I("d.item_id"), I("d.qty"))
+
+	for _, test := range []struct {
+		dialect Dialect
+		query   string
+	}{
+		{
+			dialect: dialect.PostgreSQL,
+			query: `MERGE INTO "stock" USING (SELECT "item_id", "qty" FROM "delivery" WHERE ("day" = 1)) AS "d" ON (stock.item_id = d.item_id) ` +
+				`WHEN MATCHED THEN UPDATE SET "qty" = stock.qty + d.qty ` +
+				`WHEN NOT MATCHED THEN INSERT ("item_id", "qty") VALUES ("d"."item_id", "d"."qty")`,
+		},
+		{
+			dialect: dialect.MSSQL,
+			query: `MERGE INTO [stock] USING (SELECT [item_id], [qty] FROM [delivery] WHERE ([day] = 1)) AS [d] ON (stock.item_id = d.item_id) ` +
+				`WHEN MATCHED THEN UPDATE SET [qty] = stock.qty + d.qty ` +
+				`WHEN NOT MATCHED THEN INSERT ([item_id], [qty]) VALUES ([d].[item_id], [d].[qty]);`,
+		},
+		{
+			dialect: dialect.MySQL,
+			query: "INSERT INTO `stock` (`item_id`, `qty`) SELECT `d`.`item_id`, `d`.`qty` FROM (SELECT `item_id`, `qty` FROM `delivery` WHERE (`day` = 1)) AS `d` " +
+				"ON DUPLICATE KEY UPDATE `qty` = stock.qty + d.qty",
+		},
+		{
+			dialect: dialect.Snowflake,
+			query: `MERGE INTO "stock" USING (SELECT "item_id", "qty" FROM "delivery" WHERE ("day" = 1)) AS "d" ON (stock.item_id = d.item_id) ` +
+				`WHEN MATCHED THEN UPDATE SET "qty" = stock.qty + d.qty ` +
+				`WHEN NOT MATCHED THEN INSERT ("item_id", "qty") VALUES ("d"."item_id", "d"."qty")`,
+		},
+	} {
+		query, err := InterpolateForDialect("?", []interface{}{builder}, test.dialect)
+		require.NoError(t, err)
+		require.Equal(t, test.query, query)
+	}
+
+	err := builder.Build(dialect.SQLite3, NewBuffer())
+	require.True(t, errors.Is(err, ErrDialectNotSupported))
+}
@@ -0,0 +1,178 @@
+package dbr
+
+import (
+	"bytes"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"time"
+)
+
+// Null is a type that can be null or a T, like Null[int32] or Null[MyEnum].
+// T is scanned like sql.Rows.Scan does for scalar types, and valued with
+// driver.DefaultParameterConverter.
+type Null[T any] struct {
+	V     T
+	Valid bool // Valid is true if V is not NULL
+}
+
+// NewNull creates a Null that is not null.
+func NewNull[T any](v T) Null[T] {
+	return Null[T]{V: v, Valid: true}
+}
+
+// NullFromPtr creates a Null that is null if p is nil.
+func NullFromPtr[T any](p *T) (n Null[T]) {
+	if p != nil {
+		n = NewNull(*p)
+	}
+	return
+}
+
+// Ptr returns a pointer to the value, or nil if n is null.
+func (n Null[T]) Ptr() *T {
+	if !n.Valid {
+		return nil
+	}
+	v := n.V
+	return &v
+}
+
+// ValueOrZero returns the value, or the zero value if n is null.
+func (n Null[T]) ValueOrZero() T {
+	if !n.Valid {
+		var zero T
+		return zero
+	}
+	return n.V
+}
+
+// Scan implements the Scanner interface.
+func (n *Null[T]) Scan(value interface{}) error {
+	if value == nil {
+		var zero T
+		n.V, n.Valid = zero, false
+		return nil
+	}
+	err := convertAssign(&n.V, value)
+	n.Valid = err == nil
+	return err
+}
+
+// Value implements the driver Valuer interface.
+func (n Null[T]) Value() (driver.Value, error) {
+	if !n.Valid {
+		return nil, nil
+	}
+	return driver.DefaultParameterConverter.ConvertValue(n.V)
+}
+
+// MarshalJSON correctly serializes a Null to JSON.
+func (n Null[T]) MarshalJSON() ([]byte, error) {
+	if n.Valid {
+		return json.Marshal(n.V)
+	}
+	return nullString, nil
+}
+
+// UnmarshalJSON correctly deserializes a Null from JSON.
+func (n *Null[T]) UnmarshalJSON(b []byte) error {
+	if bytes.Equal(b, nullString) {
+		return n.Scan(nil)
+	}
+	err := json.Unmarshal(b, &n.V)
+	n.Valid = err == nil
+	return err
+}
+
+// convertAssign copies a value from the driver to dest like sql.Rows.Scan,
+// which does not export it.
+func convertAssign(dest, src interface{}) error {
+	if scanner, ok := dest.(sql.Scanner); ok {
+		return scanner.Scan(src)
+	}
+
+	dv := reflect.ValueOf(dest).Elem()
+	sv := reflect.ValueOf(src)
+	if sv.Type().AssignableTo(dv.Type()) {
+		if b, ok := src.([]byte); ok {
+			// the driver may reuse the bytes
+			sv = reflect.ValueOf(append([]byte(nil), b...))
+		}
+		dv.Set(sv)
+		return nil
+	}
+
+	s, ok := asString(src)
+	switch dv.Kind() {
+	case reflect.String:
+		if ok {
+			dv.SetString(s)
+			return nil
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if ok {
+			i, err := strconv.ParseInt(s, 10, dv.Type().Bits())
+			if err != nil {
+				return fmt.Errorf("dbr: converting %T to %s: %w", src, dv.Type(), err)
+			}
+			dv.SetInt(i)
+			return nil
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if ok {
+			u, err := strconv.ParseUint(s, 10, dv.Type().Bits())
+			if err != nil {
+				return fmt.Errorf("dbr: converting %T to %s: %w", src, dv.Type(), err)
+			}
+			dv.SetUint(u)
+			return nil
+		}
+	case reflect.Float32, reflect.Float64:
+		if ok {
+			f, err := strconv.ParseFloat(s, dv.Type().Bits())
+			if err != nil {
+				return fmt.Errorf("dbr: converting %T to %s: %w", src, dv.Type(), err)
+			}
+			dv.SetFloat(f)
+			return nil
+		}
+	case reflect.Bool:
+		if ok {
+			b, err := strconv.ParseBool(s)
+			if err != nil {
+				return fmt.Errorf("dbr: converting %T to %s: %w", src, dv.Type(), err)
+			}
+			dv.SetBool(b)
+			return nil
+		}
+	}
+
+	if sv.Type().ConvertibleTo(dv.Type()) {
+		dv.Set(sv.Convert(dv.Type()))
+		return nil
+	}
+	return fmt.Errorf("dbr: unsupported scan, storing %T into %s", src, dv.Type())
+}
+
+// asString formats the scalar values from the driver.
+func asString(src interface{}) (string, bool) {
+	switch v := src.(type) {
+	case string:
+		return v, true
+	case []byte:
+		return string(v), true
+	case int64:
+		return strconv.FormatInt(v, 10), true
+	case float64:
+		return strconv.FormatFloat(v, 'g', -1, 64), true
+	case bool:
+		return strconv.FormatBool(v), true
+	case time.Time:
+		return v.Format(time.RFC3339Nano), true
+	}
+	return "", false
+}
@@ -1,5 +1,11 @@
 package dbr
 
+import (
+	"strconv"
+
+	"github.com/jiyeyuran/dbr/v2/dialect"
+)
+
 type direction bool
 
 // orderby directions
@@ -9,9 +15,47 @@ const (
 	desc           = true
 )
 
+type nullsOrder uint8
+
+// where nulls are sorted
+// by default it depends on databases
+const (
+	nullsDefault nullsOrder = iota
+	nullsFirst
+	nullsLast
+)
+
 func order(column string, dir direction) Builder {
+	return orderNulls(column, dir, nullsDefault)
+}
+
+func orderNulls(column string, dir direction, nulls nullsOrder) Builder {
 	return BuildFunc(func(d Dialect, buf Buffer) error {
-		// FIXME: no quote ident
+		column := d.QuoteIdent(column)
+		emulated := false
+		if nulls != nullsDefault {
+			// sort by whether the column is null first, where null is 1
+			switch dialect.CapabilitiesOf(d).NullsStyle {
+			case dialect.NullsIsNull:
+				emulated = true
+				buf.WriteString("ISNULL(")
+				buf.WriteString(column)
+				buf.WriteString(")")
+			case dialect.NullsCase:
+				emulated = true
+				buf.WriteString("CASE WHEN ")
+				buf.WriteString(column)
+				buf.WriteString(" IS NULL THEN 1 ELSE 0 END")
+			}
+			if emulated {
+				if nulls == nullsFirst {
+					buf.WriteString(" DESC, ")
+				} else {
+					buf.WriteString(" ASC, ")
+				}
+			}
+		}
+
 		buf.WriteString(column)
 		switch dir {
 		case asc:
@@ -19,6 +63,48 @@ func order(column string, dir direction) Builder {
 		case desc:
 			buf.WriteString(" DESC")
 		}
+
+		if !emulated {
+			switch nulls {
+			case nullsFirst:
+				buf.WriteString(" NULLS FIRST")
+			case nullsLast:
+				buf.WriteString(" NULLS LAST")
+			}
+		}
 		return nil
 	})
 }
+
+// buildOrderLimit builds `ORDER BY ... LIMIT n` in UpdateStmt and DeleteStmt,
+// which is only supported by mysql and sqlite.
+func buildOrderLimit(d Dialect, buf Buffer, order []Builder, limit int64) error {
+	if len(order) == 0 && limit < 0 {
+		return nil
+	}
+	if !dialect.CapabilitiesOf(d).SupportsUpdateLimit {
+		if len(order) > 0 {
+			return errDialectNotSupported("ORDER BY")
+		}
+		return errDialectNotSupported("LIMIT")
+	}
+
+	if len(order) > 0 {
+		buf.WriteString(" ORDER BY ")
+		for i, order := range order {
+			if i > 0 {
+				buf.WriteString(", ")
+			}
+			err := order.Build(d, buf)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	if limit >= 0 {
+		buf.WriteString(" LIMIT ")
+		buf.WriteString(strconv.FormatInt(limit, 10))
+	}
+	return nil
+}
@@ -0,0 +1,235 @@
+package dbr
+
+import (
+	"container/list"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// scanStep is a struct field in the path from a struct to a column.
+type scanStep struct {
+	index int
+	// alloc allocates the field if it is a nil pointer,
+	// like the nested structs with prefix.
+	alloc bool
+}
+
+// scanPlan is the mapping from columns to struct fields, which is the same
+// as findValueByName with retPtr, but without walking all struct fields for
+// every row.
+//
+// Each column has the candidate paths in the order of findValueByName,
+// and the first one without nil pointer on the way is used.
+type scanPlan struct {
+	path [][][]scanStep
+}
+
+type scanPlanKey struct {
+	typ     reflect.Type
+	column  string
+	tagName string
+}
+
+const scanPlanCacheSize = 1024
+
+// scanPlans caches the plans of NameMapping. The plans of the other
+// mappers are not shared, since a mapper cannot be compared with another.
+var scanPlans = newPlanCache(scanPlanCacheSize)
+
+// planCache is a LRU cache of *scanPlan by scanPlanKey.
+// A nil *scanPlan means that the struct cannot be planned.
+type planCache struct {
+	size int
+
+	mu    sync.Mutex
+	lru   *list.List // of *cachedPlan, most recently used first
+	plans map[scanPlanKey]*list.Element
+}
+
+type cachedPlan struct {
+	key  scanPlanKey
+	plan *scanPlan
+}
+
+func newPlanCache(size int) *planCache {
+	return &planCache{
+		size:  size,
+		lru:   list.New(),
+		plans: make(map[scanPlanKey]*list.Element),
+	}
+}
+
+func (c *planCache) get(key scanPlanKey) (*scanPlan, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	elem, ok := c.plans[key]
+	if !ok {
+		return nil, false
+	}
+	c.lru.MoveToFront(elem)
+	return elem.Value.(*cachedPlan).plan, true
+}
+
+func (c *planCache) put(key scanPlanKey, p *scanPlan) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if elem, ok := c.plans[key]; ok {
+		c.lru.MoveToFront(elem)
+		elem.Value.(*cachedPlan).plan = p
+		return
+	}
+	c.plans[key] = c.lru.PushFront(&cachedPlan{key: key, plan: p})
+	for c.lru.Len() > c.size {
+		elem := c.lru.Back()
+		c.lru.Remove(elem)
+		delete(c.plans, elem.Value.(*cachedPlan).key)
+	}
+}
+
+func (c *planCache) len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.lru.Len()
+}
+
+// localPlanKey identifies the columns of a query by the slice,
+// which is the same for all rows.
+type localPlanKey struct {
+	typ    reflect.Type
+	column *string
+	n      int
+}
+
+// scanPlan returns the cached plan of struct type t and columns,
+// or nil if it must be found with findValueByName.
+func (s *tagStore) scanPlan(t reflect.Type, name []string) *scanPlan {
+	local := localPlanKey{typ: t, n: len(name)}
+	if len(name) > 0 {
+		local.column = &name[0]
+	}
+	if p, ok := s.plans[local]; ok {
+		return p
+	}
+
+	// a custom mapper is planned once per tagStore
+	shared := s.nameMapping == nil
+	key := scanPlanKey{
+		typ:     t,
+		column:  strings.Join(name, "\x00"),
+		tagName: s.tag(),
+	}
+	var p *scanPlan
+	var ok bool
+	if shared {
+		p, ok = scanPlans.get(key)
+	}
+	if !ok {
+		p = &scanPlan{path: make([][][]scanStep, len(name))}
+		if !s.plan(t, name, nil, make(map[reflect.Type]bool), p.path) {
+			p = nil
+		}
+		if shared {
+			scanPlans.put(key, p)
+		}
+	}
+	if s.plans == nil {
+		s.plans = make(map[localPlanKey]*scanPlan)
+	}
+	s.plans[local] = p
+	return p
+}
+
+// plan walks type t like findValueByName walks the value, and appends
+// the paths of name to ret. It returns false if the paths depend on the
+// value, like the fields of ColumnsScanner or recursive types.
+func (s *tagStore) plan(t reflect.Type, name []string, path []scanStep, seen map[reflect.Type]bool, ret [][][]scanStep) bool {
+	switch t.Kind() {
+	case reflect.Ptr:
+		if t.Elem().Kind() == reflect.Ptr {
+			return false
+		}
+		return s.plan(t.Elem(), name, path, seen, ret)
+	case reflect.Struct:
+		if seen[t] || reflect.PtrTo(t).Implements(typeColumnsScanner) {
+			return false
+		}
+		seen[t] = true
+		defer delete(seen, t)
+
+		l := s.get(t)
+		opts := s.options(t)
+		for i := 0; i < t.NumField(); i++ {
+			tag := l[i]
+			if tag == "" {
+				continue
+			}
+			field := t.Field(i)
+			if field.PkgPath == "" && reflect.PtrTo(field.Type).Implements(typeColumnsScanner) {
+				return false
+			}
+			prefix := opts[i].prefix(tag)
+			fieldPath := append(path[:len(path):len(path)], scanStep{
+				index: i,
+				alloc: prefix != "" && field.Type.Kind() == reflect.Ptr && field.PkgPath == "",
+			})
+			for k, want := range name {
+				if want == tag {
+					ret[k] = append(ret[k], fieldPath)
+				}
+			}
+			if isValueType(field.Type) {
+				continue
+			}
+			if prefix != "" {
+				sub := make([]string, len(name))
+				for k, want := range name {
+					if strings.HasPrefix(want, prefix) {
+						sub[k] = want[len(prefix):]
+					}
+				}
+				if !s.plan(field.Type, sub, fieldPath, seen, ret) {
+					return false
+				}
+				continue
+			}
+			if !s.plan(field.Type, name, fieldPath, seen, ret) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// find sets ptr to the fields of struct value like findValueByName.
+func (p *scanPlan) find(value reflect.Value, ptr []interface{}) {
+	for k, paths := range p.path {
+		if ptr[k] != nil {
+			continue
+		}
+		for _, path := range paths {
+			if v, ok := resolvePath(value, path); ok {
+				ptr[k] = v.Addr().Interface()
+				break
+			}
+		}
+	}
+}
+
+// resolvePath returns the field of path, or false if there is a nil pointer
+// that cannot be allocated on the way.
+func resolvePath(v reflect.Value, path []scanStep) (reflect.Value, bool) {
+	for i, step := range path {
+		if v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				if !path[i-1].alloc {
+					return v, false
+				}
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(step.index)
+	}
+	return v, true
+}
@@ -0,0 +1,210 @@
+package dbr
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// On maps the columns of related records to the columns of parents,
+// like On{"post_id": "id"}.
+type On map[string]string
+
+// TableNamer names the table of related records in LoadRelated.
+// Without it, the table is the mapped name of the struct type.
+type TableNamer interface {
+	TableName() string
+}
+
+// LoadRelated loads the related records of parents with one IN query,
+// and attaches them to field of each parent.
+//
+// parents is a pointer to a slice of structs or struct pointers, and field is
+// the Go name of a slice field for has-many, or a struct field for has-one,
+// like sess.LoadRelated(&posts, "Comments", On{"post_id": "id"}).
+func (sess *Session) LoadRelated(parents interface{}, field string, on On) error {
+	return sess.LoadRelatedContext(context.Background(), parents, field, on)
+}
+
+// LoadRelatedContext is like LoadRelated, but with context.
+func (sess *Session) LoadRelatedContext(ctx context.Context, parents interface{}, field string, on On) error {
+	return loadRelated(ctx, sess.Select("*"), parents, field, on)
+}
+
+// LoadRelated loads the related records of parents with one IN query,
+// and attaches them to field of each parent.
+func (tx *Tx) LoadRelated(parents interface{}, field string, on On) error {
+	return tx.LoadRelatedContext(context.Background(), parents, field, on)
+}
+
+// LoadRelatedContext is like LoadRelated, but with context.
+func (tx *Tx) LoadRelatedContext(ctx context.Context, parents interface{}, field string, on On) error {
+	return loadRelated(ctx, tx.Select("*"), parents, field, on)
+}
+
+func loadRelated(ctx context.Context, stmt *SelectStmt, parents interface{}, field string, on On) error {
+	v := reflect.ValueOf(parents)
+	if v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Slice {
+		return ErrInvalidPointer
+	}
+	v = v.Elem()
+	parentType := v.Type().Elem()
+	if parentType.Kind() == reflect.Ptr {
+		parentType = parentType.Elem()
+	}
+	if parentType.Kind() != reflect.Struct {
+		return ErrInvalidPointer
+	}
+	f, ok := parentType.FieldByName(field)
+	if !ok {
+		return fmt.Errorf("dbr: %s has no field %s", parentType, field)
+	}
+	many := f.Type.Kind() == reflect.Slice
+	childType := f.Type
+	if many {
+		childType = childType.Elem()
+	}
+	childStruct := childType
+	if childStruct.Kind() == reflect.Ptr {
+		childStruct = childStruct.Elem()
+	}
+	if childStruct.Kind() != reflect.Struct {
+		return fmt.Errorf("dbr: %s.%s is not a struct or slice of structs", parentType, field)
+	}
+	if len(on) == 0 {
+		return fmt.Errorf("dbr: no columns to load %s.%s", parentType, field)
+	}
+
+	childColumn := make([]string, 0, len(on))
+	for column := range on {
+		childColumn = append(childColumn, column)
+	}
+	sort.Strings(childColumn)
+	parentColumn := make([]string, len(childColumn))
+	for i, column := range childColumn {
+		parentColumn[i] = on[column]
+	}
+
+	s := newTagStoreFor(stmt.runner)
+
+	// parents with the same key share the related records
+	index := make(map[string][]reflect.Value)
+	var in []interface{}
+	var cond []Builder
+	for i := 0; i < v.Len(); i++ {
+		parent := reflect.Indirect(v.Index(i))
+		if !parent.IsValid() {
+			continue
+		}
+		parent.FieldByIndex(f.Index).Set(reflect.Zero(f.Type))
+
+		value, err := relatedValues(s, parent, parentColumn)
+		if err != nil {
+			return err
+		}
+		key, ok := relatedKey(value)
+		if !ok {
+			continue
+		}
+		if _, ok := index[key]; !ok {
+			if len(childColumn) == 1 {
+				in = append(in, value[0])
+			} else {
+				eq := make([]Builder, len(childColumn))
+				for j, column := range childColumn {
+					eq[j] = Eq(column, value[j])
+				}
+				cond = append(cond, And(eq...))
+			}
+		}
+		index[key] = append(index[key], parent)
+	}
+	if len(index) == 0 {
+		return nil
+	}
+
+	table := s.mapName(childStruct.Name())
+	if namer, ok := reflect.New(childStruct).Interface().(TableNamer); ok {
+		table = namer.TableName()
+	}
+	stmt.From(table)
+	if len(childColumn) == 1 {
+		stmt.Where(Eq(childColumn[0], in))
+	} else {
+		stmt.Where(Or(cond...))
+	}
+
+	children := reflect.New(reflect.SliceOf(childType))
+	_, err := stmt.LoadContext(ctx, children.Interface())
+	if err != nil {
+		return err
+	}
+	children = children.Elem()
+	for i := 0; i < children.Len(); i++ {
+		child := children.Index(i)
+		value, err := relatedValues(s, child, childColumn)
+		if err != nil {
+			return err
+		}
+		key, ok := relatedKey(value)
+		if !ok {
+			continue
+		}
+		for _, parent := range index[key] {
+			dest := parent.FieldByIndex(f.Index)
+			if many {
+				dest.Set(reflect.Append(dest, child))
+			} else {
+				dest.Set(child)
+			}
+		}
+	}
+	return nil
+}
+
+// relatedValues returns the values of columns in the struct value.
+func relatedValues(s *tagStore, value reflect.Value, column []string) ([]interface{}, error) {
+	found := make([]interface{}, len(column))
+	s.findValueByName(value, column, found, false)
+	ret := make([]interface{}, len(column))
+	for i := range found {
+		v, ok := found[i].(reflect.Value)
+		if !ok {
+			return nil, fmt.Errorf("dbr: %s has no column %s", reflect.Indirect(value).Type(), column[i])
+		}
+		ret[i] = v.Interface()
+	}
+	return ret, nil
+}
+
+// relatedKey returns the key to match parents and related records,
+// so that int64 and NullInt64 match for example.
+// It returns false if any value is NULL.
+func relatedKey(value []interface{}) (string, bool) {
+	key := make([]string, len(value))
+	for i, v := range value {
+		if valuer, ok := v.(driver.Valuer); ok {
+			var err error
+			v, err = valuer.Value()
+			if err != nil {
+				return "", false
+			}
+		}
+		rv := reflect.ValueOf(v)
+		for rv.Kind() == reflect.Ptr && !rv.IsNil() {
+			rv = rv.Elem()
+		}
+		if !rv.IsValid() || rv.Kind() == reflect.Ptr {
+			return "", false
+		}
+		if b, ok := rv.Interface().([]byte); ok {
+			key[i] = string(b)
+			continue
+		}
+		key[i] = fmt.Sprint(rv.Interface())
+	}
+	return strings.Join(key, "\x00"), true
+}
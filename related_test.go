@@ -0,0 +1,53 @@
+package dbr
+
+import (
+	"regexp"
+	"testing"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/jiyeyuran/dbr/v2/dialect"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadRelated(t *testing.T) {
+	sess, mock := newMockSession(t, dialect.MySQL)
+
+	posts := []*relatedPost{{ID: 1}, {ID: 2}, {ID: 1}}
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `comments` WHERE (`post_id` IN (1,2))")).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "post_id", "body"}).
+			AddRow(10, 1, "a").AddRow(11, 1, "b").AddRow(12, 3, "c"))
+	require.NoError(t, sess.LoadRelated(&posts, "Comments", On{"post_id": "id"}))
+	require.Len(t, posts[0].Comments, 2)
+	require.Equal(t, "b", posts[0].Comments[1].Body)
+	require.Nil(t, posts[1].Comments)
+	require.Equal(t, posts[0].Comments, posts[2].Comments)
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `comments` WHERE (`post_id` IN (1,2))")).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "post_id", "body"}).AddRow(12, 2, "c"))
+	require.NoError(t, sess.LoadRelated(&posts, "Latest", On{"post_id": "id"}))
+	require.Nil(t, posts[0].Latest)
+	require.Equal(t, int64(12), posts[1].Latest.ID)
+
+	// no query without parents
+	require.NoError(t, sess.LoadRelated(&[]relatedPost{}, "Comments", On{"post_id": "id"}))
+	require.Error(t, sess.LoadRelated(&posts, "Author", On{"post_id": "id"}))
+	require.Error(t, sess.LoadRelated(&posts, "Comments", On{"post_id": "post_id"}))
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+type relatedComment struct {
+	ID     int64
+	PostID NullInt64
+	Body   string
+}
+
+func (relatedComment) TableName() string {
+	return "comments"
+}
+
+type relatedPost struct {
+	ID       int64
+	Comments []relatedComment `db:"-"`
+	Latest   *relatedComment  `db:"-"`
+}
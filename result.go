@@ -0,0 +1,28 @@
+package dbr
+
+import (
+	"database/sql"
+	"time"
+)
+
+// Result is the result of Exec with the metadata of the execution,
+// which is useful for logging and assertions.
+type Result struct {
+	sql.Result
+	// SQL is the executed query.
+	SQL string
+	// Duration is the time to execute the query.
+	Duration time.Duration
+	// Attempts is the number of attempts by Session.Retry, or the attempt
+	// of the transaction retried by InTx or RunInTx. It is 1 if the query
+	// is not retried.
+	Attempts int
+}
+
+// attemptsOf returns the attempt of the transaction of runner.
+func attemptsOf(runner interface{}) int {
+	if tx, ok := runner.(*Tx); ok && tx.attempt > 1 {
+		return tx.attempt
+	}
+	return 1
+}
@@ -0,0 +1,42 @@
+package dbr
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/jiyeyuran/dbr/v2/dialect"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExecResult(t *testing.T) {
+	sess, mock := newMockSession(t, dialect.MySQL)
+
+	mock.ExpectExec("UPDATE `suggestions`").
+		WillDelayFor(time.Millisecond).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	result, err := sess.Update("suggestions").Set("title", "a").Exec()
+	require.NoError(t, err)
+	require.Equal(t, "UPDATE `suggestions` SET `title` = 'a'", result.SQL)
+	require.True(t, result.Duration >= time.Millisecond)
+	require.Equal(t, 1, result.Attempts)
+	n, err := result.RowsAffected()
+	require.NoError(t, err)
+	require.Equal(t, int64(2), n)
+
+	mock.ExpectBegin()
+	mock.ExpectExec("DELETE FROM `suggestions`").WillReturnError(&testMySQLError{Number: 1213})
+	mock.ExpectRollback()
+	mock.ExpectBegin()
+	mock.ExpectExec("DELETE FROM `suggestions`").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	err = sess.InTx(context.Background(), func(tx *Tx) error {
+		result, err = tx.DeleteFrom("suggestions").Exec()
+		return err
+	}, RetryOn(Deadlock), Backoff(time.Millisecond))
+	require.NoError(t, err)
+	require.Equal(t, 2, result.Attempts)
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
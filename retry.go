@@ -0,0 +1,299 @@
+package dbr
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"math/rand"
+	"net"
+	"reflect"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/jiyeyuran/dbr/v2/dialect"
+)
+
+// TxFailure is a kind of transaction failure, which is usually gone
+// if the transaction is run again.
+type TxFailure int
+
+const (
+	// Deadlock is the failure of a deadlock victim, which is MySQL 1213,
+	// PostgreSQL 40P01 and MSSQL 1205.
+	Deadlock TxFailure = iota + 1
+	// SerializationFailure is the failure of a transaction that conflicts
+	// with the concurrent ones, which is SQLSTATE 40001.
+	SerializationFailure
+)
+
+// InTxOption configures Session.InTx.
+type InTxOption func(*inTxOptions)
+
+type inTxOptions struct {
+	txOptions  *TxOptions
+	retryOn    []TxFailure
+	maxRetries int
+	backoff    time.Duration
+}
+
+const (
+	defaultMaxRetries = 3
+	defaultBackoff    = 10 * time.Millisecond
+)
+
+// RetryOn retries the transaction if it fails with any of failure.
+func RetryOn(failure ...TxFailure) InTxOption {
+	return func(o *inTxOptions) {
+		o.retryOn = append(o.retryOn, failure...)
+	}
+}
+
+// MaxRetries sets the max number of retries, which is 3 by default.
+func MaxRetries(n int) InTxOption {
+	return func(o *inTxOptions) {
+		o.maxRetries = n
+	}
+}
+
+// Backoff sets the wait before the first retry, which is doubled for
+// every retry with jitter. It is 10ms by default.
+func Backoff(d time.Duration) InTxOption {
+	return func(o *inTxOptions) {
+		o.backoff = d
+	}
+}
+
+// WithTxOptions begins the transaction with opts.
+func WithTxOptions(opts *TxOptions) InTxOption {
+	return func(o *inTxOptions) {
+		o.txOptions = opts
+	}
+}
+
+// InTx runs fn in a transaction like Session.RunInTx.
+//
+// With RetryOn, the transaction is rolled back and fn is run again in a new
+// transaction after backoff if it fails with the failures, so fn should be
+// idempotent, like:
+//
+//	err := sess.InTx(ctx, fn, dbr.RetryOn(dbr.Deadlock, dbr.SerializationFailure), dbr.MaxRetries(3))
+func (sess *Session) InTx(ctx context.Context, fn func(tx *Tx) error, opts ...InTxOption) error {
+	o := inTxOptions{
+		maxRetries: -1,
+		backoff:    defaultBackoff,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.maxRetries < 0 {
+		o.maxRetries = defaultMaxRetries
+	}
+
+	for attempt := 0; ; attempt++ {
+		err := sess.runTx(ctx, o.txOptions, attempt+1, fn)
+		if err == nil {
+			return nil
+		}
+		if attempt >= o.maxRetries || !o.retryable(sess.Dialect, err) || ctx.Err() != nil {
+			return err
+		}
+		sess.EventKv("dbr.retry", kvs{
+			"attempt": strconv.Itoa(attempt + 1),
+			"error":   err.Error(),
+		})
+		if !sleep(ctx, jitter(o.backoff<<attempt)) {
+			return err
+		}
+	}
+}
+
+func (o *inTxOptions) retryable(d Dialect, err error) bool {
+	failure := txFailureOf(d, err)
+	if failure == 0 {
+		return false
+	}
+	for _, want := range o.retryOn {
+		if want == failure {
+			return true
+		}
+	}
+	return false
+}
+
+// sleep waits for d, or returns false if ctx is done.
+func sleep(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
+
+// jitter returns a random duration in [d/2, d].
+func jitter(d time.Duration) time.Duration {
+	if d <= 0 {
+		return 0
+	}
+	return d/2 + time.Duration(rand.Int63n(int64(d/2)+1))
+}
+
+// txFailureOf returns the TxFailure of err in dialect d, or 0 if it is not.
+func txFailureOf(d Dialect, err error) TxFailure {
+	switch sqlState(err) {
+	case "40001":
+		return SerializationFailure
+	case "40P01":
+		return Deadlock
+	}
+	switch errorNumber(err) {
+	case 1213:
+		if d == dialect.MySQL {
+			return Deadlock
+		}
+	case 1205:
+		if d == dialect.MSSQL {
+			return Deadlock
+		}
+	}
+	return 0
+}
+
+// errorNumber returns the error number of mysql and mssql errors,
+// or 0 if it is not found.
+func errorNumber(err error) int64 {
+	for ; err != nil; err = errors.Unwrap(err) {
+		v := reflect.Indirect(reflect.ValueOf(err))
+		if v.Kind() != reflect.Struct {
+			continue
+		}
+		switch number := v.FieldByName("Number"); number.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			return number.Int()
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			return int64(number.Uint())
+		}
+	}
+	return 0
+}
+
+// RetryPolicy retries the queries of a session that fail with transient
+// errors, like bad connections and failovers. The queries in transactions
+// are not retried, since the transactions are gone with the connections.
+type RetryPolicy struct {
+	// MaxAttempts is the max number of attempts including the first one.
+	// The policy is disabled if it is less than 2.
+	MaxAttempts int
+	// Backoff is the wait before the first retry, which is doubled for
+	// every retry with jitter. It is 10ms if it is 0.
+	Backoff time.Duration
+	// RetryOn reports whether err is retried. IsTransient is used if nil.
+	RetryOn func(err error) bool
+	// Writes retries Exec and the writes with returning columns, which
+	// must be idempotent. Only SELECT and UNION are retried by default.
+	Writes bool
+}
+
+func (p *RetryPolicy) retryable(err error) bool {
+	if p.RetryOn != nil {
+		return p.RetryOn(err)
+	}
+	return IsTransient(err)
+}
+
+// retrier is implemented by Session for Session.Retry.
+type retrier interface {
+	retryPolicy() *RetryPolicy
+}
+
+// retryPolicyOf returns the RetryPolicy of runner, or nil if it is disabled.
+func retryPolicyOf(runner interface{}) *RetryPolicy {
+	if r, ok := runner.(retrier); ok {
+		return r.retryPolicy()
+	}
+	return nil
+}
+
+// isRead reports whether builder only reads.
+func isRead(builder Builder) bool {
+	switch builder := builder.(type) {
+	case *SelectStmt:
+		return builder.IntoTable == ""
+	case *UnionStmt:
+		return true
+	}
+	return false
+}
+
+// retryQuery runs fn, which sends query to the database, and retries it by
+// the RetryPolicy of runner. It returns the number of attempts.
+func retryQuery(ctx context.Context, runner runner, log EventReceiver, builder Builder, query string, fn func() error) (int, error) {
+	p := retryPolicyOf(runner)
+	if p == nil || !p.Writes && !isRead(builder) {
+		return 1, fn()
+	}
+	backoff := p.Backoff
+	if backoff <= 0 {
+		backoff = defaultBackoff
+	}
+	for attempt := 1; ; attempt++ {
+		err := fn()
+		if err == nil || attempt >= p.MaxAttempts || !p.retryable(err) || ctx.Err() != nil {
+			return attempt, err
+		}
+		log.EventKv("dbr.retry", kvs{
+			"attempt": strconv.Itoa(attempt),
+			"error":   err.Error(),
+			"sql":     query,
+		})
+		if !sleep(ctx, jitter(backoff<<(attempt-1))) {
+			return attempt, err
+		}
+	}
+}
+
+// IsTransient reports whether err is likely gone if the query is sent again,
+// like bad connections, connection resets, and shutdowns of the servers
+// in failovers.
+func IsTransient(err error) bool {
+	if err == nil {
+		return false
+	}
+	if errors.Is(err, driver.ErrBadConn) ||
+		errors.Is(err, io.EOF) ||
+		errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, syscall.EPIPE) {
+		return true
+	}
+	var opErr *net.OpError
+	if errors.As(err, &opErr) && opErr.Op == "dial" {
+		return true
+	}
+	switch state := sqlState(err); {
+	case strings.HasPrefix(state, "08"):
+		// connection exception
+		return true
+	case state == "57P01", state == "57P02", state == "57P03":
+		// admin_shutdown, crash_shutdown, cannot_connect_now
+		return true
+	}
+	switch errorNumber(err) {
+	case 1053, 2006, 2013:
+		// mysql server shutdown, server has gone away, lost connection
+		return true
+	}
+	for ; err != nil; err = errors.Unwrap(err) {
+		// mysql.ErrInvalidConn
+		if err.Error() == "invalid connection" {
+			return true
+		}
+	}
+	return false
+}
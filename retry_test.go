@@ -0,0 +1,68 @@
+package dbr
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"io"
+	"testing"
+	"time"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/jiyeyuran/dbr/v2/dialect"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetryPolicy(t *testing.T) {
+	sess, mock := newMockSession(t, dialect.MySQL)
+	sess.Retry = RetryPolicy{MaxAttempts: 3, Backoff: time.Millisecond}
+
+	mock.ExpectQuery("SELECT `id` FROM `users`").WillReturnError(&testMySQLError{Number: 2013})
+	mock.ExpectQuery("SELECT `id` FROM `users`").WillReturnError(&testMySQLError{Number: 2006})
+	mock.ExpectQuery("SELECT `id` FROM `users`").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	var ids []int64
+	_, err := sess.Select("id").From("users").Load(&ids)
+	require.NoError(t, err)
+	require.Equal(t, []int64{1}, ids)
+
+	// MaxAttempts
+	for i := 0; i < 3; i++ {
+		mock.ExpectQuery("SELECT `id` FROM `users`").WillReturnError(&testMySQLError{Number: 2013})
+	}
+	_, err = sess.Select("id").From("users").Load(&ids)
+	require.Equal(t, &testMySQLError{Number: 2013}, err)
+
+	// not transient
+	mock.ExpectQuery("SELECT `id` FROM `users`").WillReturnError(&testMySQLError{Number: 1213})
+	_, err = sess.Select("id").From("users").Load(&ids)
+	require.Equal(t, &testMySQLError{Number: 1213}, err)
+
+	// writes are not retried by default
+	mock.ExpectExec("DELETE FROM `users`").WillReturnError(&pq.Error{Code: "57P01"})
+	_, err = sess.DeleteFrom("users").Exec()
+	require.Equal(t, &pq.Error{Code: "57P01"}, err)
+
+	sess.Retry.Writes = true
+	mock.ExpectExec("DELETE FROM `users`").WillReturnError(&pq.Error{Code: "08006"})
+	mock.ExpectExec("DELETE FROM `users`").WillReturnResult(sqlmock.NewResult(0, 1))
+	result, err := sess.DeleteFrom("users").Exec()
+	require.NoError(t, err)
+	require.Equal(t, 2, result.Attempts)
+
+	// transactions are not retried
+	mock.ExpectBegin()
+	mock.ExpectExec("DELETE FROM `users`").WillReturnError(&testMySQLError{Number: 2013})
+	mock.ExpectRollback()
+	tx, err := sess.Begin()
+	require.NoError(t, err)
+	_, err = tx.DeleteFrom("users").Exec()
+	require.Error(t, err)
+	require.NoError(t, tx.Rollback())
+
+	require.NoError(t, mock.ExpectationsWereMet())
+
+	require.True(t, IsTransient(fmt.Errorf("query: %w", driver.ErrBadConn)))
+	require.True(t, IsTransient(io.ErrUnexpectedEOF))
+	require.False(t, IsTransient(sql.ErrNoRows))
+}
@@ -0,0 +1,44 @@
+package dbr
+
+import "github.com/jiyeyuran/dbr/v2/dialect"
+
+// useOutput reports whether the dialect returns columns with `OUTPUT ...`
+// instead of `RETURNING ...`.
+func useOutput(d Dialect) bool {
+	caps := dialect.CapabilitiesOf(d)
+	return caps.SupportsOutput && !caps.SupportsReturning
+}
+
+// buildReturning builds `RETURNING ...` for postgres and sqlite.
+// It builds nothing if useOutput, and fails if the dialect cannot return columns.
+func buildReturning(d Dialect, buf Buffer, column []string) error {
+	if len(column) == 0 || useOutput(d) {
+		return nil
+	}
+	if !dialect.CapabilitiesOf(d).SupportsReturning {
+		return errDialectNotSupported("RETURNING")
+	}
+	buf.WriteString(" RETURNING ")
+	for i, col := range column {
+		if i > 0 {
+			buf.WriteString(",")
+		}
+		buf.WriteString(d.QuoteIdent(col))
+	}
+	return nil
+}
+
+// buildOutput builds `OUTPUT ...` for mssql, where table is INSERTED or DELETED.
+// It builds nothing unless useOutput.
+func buildOutput(d Dialect, buf Buffer, table string, column []string) {
+	if len(column) == 0 || !useOutput(d) {
+		return
+	}
+	buf.WriteString(" OUTPUT ")
+	for i, col := range column {
+		if i > 0 {
+			buf.WriteString(",")
+		}
+		buf.WriteString(table + "." + d.QuoteIdent(col))
+	}
+}
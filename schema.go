@@ -0,0 +1,85 @@
+package dbr
+
+import (
+	"strings"
+
+	"github.com/jiyeyuran/dbr/v2/dialect"
+)
+
+// WithSchema returns a session with the settings of sess, whose Schema is
+// name, like a tenant in schema-per-tenant workloads. The statements built by
+// it qualify their tables with the schema, like "tenant_x"."users", and share
+// the connections of the pool with the other sessions.
+//
+// Raw queries and the tables already qualified are not changed.
+// See UseSchema to switch the schema of the connections instead.
+func (sess *Session) WithSchema(name string) *Session {
+	s := sess.clone(sess.Connection)
+	s.Schema = name
+	return s
+}
+
+// UseSchema returns a session with the settings of sess, which switches to
+// the schema of name. It sets the search_path in postgres, and the database
+// by USE in mysql and mssql, so raw queries and subqueries use the schema too.
+//
+// The switch is a statement of Init, so the session uses a pool of the schema,
+// and the other sessions never see the schema. Prefer WithSchema unless raw queries need the schema.
+func (sess *Session) UseSchema(name string) (*Session, error) {
+	var use string
+	switch sess.Dialect {
+	case dialect.PostgreSQL, dialect.CockroachDB:
+		use = "SET search_path TO " + sess.QuoteIdent(name)
+	case dialect.MySQL, dialect.MSSQL:
+		use = "USE " + sess.QuoteIdent(name)
+	default:
+		return nil, errDialectNotSupported("schema switching")
+	}
+	s := sess.clone(sess.Connection)
+	s.Init = append(sess.Init[:len(sess.Init):len(sess.Init)], use)
+	return s, nil
+}
+
+// schemaer is implemented by Session and Tx for Session.Schema.
+type schemaer interface {
+	schema() string
+}
+
+// schemaOf returns the schema of runner, or "" if it is not set.
+func schemaOf(runner interface{}) string {
+	if s, ok := runner.(schemaer); ok {
+		return s.schema()
+	}
+	return ""
+}
+
+// qualifyTable returns the schema of runner qualifying table,
+// or "" if table is qualified already.
+func qualifyTable(runner interface{}, table string) string {
+	if strings.Contains(table, ".") {
+		return ""
+	}
+	return schemaOf(runner)
+}
+
+// quoteTable quotes table qualified by the schema of runner.
+func quoteTable(d Dialect, runner interface{}, table string) string {
+	schema := qualifyTable(runner, table)
+	if schema == "" {
+		return d.QuoteIdent(table)
+	}
+	return d.QuoteIdent(schema) + "." + d.QuoteIdent(table)
+}
+
+// joinTable qualifies the table of a join by the schema of runner,
+// which can be Builder or string.
+func joinTable(runner interface{}, table interface{}) interface{} {
+	name, ok := table.(string)
+	if !ok || qualifyTable(runner, name) == "" {
+		return table
+	}
+	return BuildFunc(func(d Dialect, buf Buffer) error {
+		buf.WriteString(quoteTable(d, runner, name))
+		return nil
+	})
+}
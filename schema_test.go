@@ -0,0 +1,74 @@
+package dbr
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/jiyeyuran/dbr/v2/dialect"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithSchema(t *testing.T) {
+	sess, mock := newMockSession(t, dialect.PostgreSQL)
+	tenant := sess.WithSchema("tenant_x")
+	require.Equal(t, "", sess.Schema)
+	require.Empty(t, tenant.Init)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT "u"."id" FROM "tenant_x".users u JOIN "tenant_x"."orders" ON o.user_id = u.id JOIN "public"."plans" ON p.id = u.plan_id`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "tenant_x"."users" ("id") VALUES (1)`)).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "tenant_x"."users" SET "name" = 'x' WHERE (id = 1)`)).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "tenant_x"."users" USING "tenant_x"."bans" WHERE (bans.user_id = users.id)`)).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	var id int64
+	err := tenant.Select("u.id").From("users u").
+		Join("orders", "o.user_id = u.id").
+		Join("public.plans", "p.id = u.plan_id").
+		LoadOne(&id)
+	require.NoError(t, err)
+	_, err = tenant.InsertInto("users").Pair("id", 1).Exec()
+	require.NoError(t, err)
+
+	err = tenant.RunInTx(context.Background(), nil, func(tx *Tx) error {
+		_, err := tx.Update("users").Set("name", "x").Where("id = ?", 1).Exec()
+		if err != nil {
+			return err
+		}
+		_, err = tx.DeleteFrom("users").Join("bans", "bans.user_id = users.id").Exec()
+		return err
+	})
+	require.NoError(t, err)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUseSchema(t *testing.T) {
+	sess, mock := newInitSession(t, dialect.PostgreSQL)
+	conn := sess.Connection
+	sess.Init = []string{"SET TIME ZONE 'UTC'"}
+
+	tenant, err := sess.UseSchema("tenant_x")
+	require.NoError(t, err)
+	require.Equal(t, []string{"SET TIME ZONE 'UTC'"}, sess.Init)
+
+	mock.ExpectExec(regexp.QuoteMeta("SET TIME ZONE 'UTC'")).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta(`SET search_path TO "tenant_x"`)).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT "id" FROM "users"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	var id int64
+	require.NoError(t, tenant.Select("id").From("users").LoadOne(&id))
+	require.NoError(t, mock.ExpectationsWereMet())
+
+	conn.Dialect = dialect.MySQL
+	tenant, err = conn.NewSession(nil).UseSchema("tenant_y")
+	require.NoError(t, err)
+	require.Equal(t, []string{"USE `tenant_y`"}, tenant.Init)
+
+	conn.Dialect = dialect.SQLite3
+	_, err = conn.NewSession(nil).UseSchema("tenant_y")
+	require.True(t, errors.Is(err, ErrDialectNotSupported))
+}
@@ -0,0 +1,239 @@
+package dbr
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/jiyeyuran/dbr/v2/dialect"
+)
+
+// ScriptError is the error of a statement in ExecScript.
+type ScriptError struct {
+	// Line is the line number of the statement in the script, starting at 1.
+	Line      int
+	Statement string
+	Err       error
+}
+
+func (e *ScriptError) Error() string {
+	return fmt.Sprintf("dbr: script line %d: %v", e.Line, e.Err)
+}
+
+// Unwrap returns Err.
+func (e *ScriptError) Unwrap() error {
+	return e.Err
+}
+
+// ExecScript executes the statements of script in order, like schema and
+// seed files. It stops at the first failed statement with *ScriptError.
+//
+// The statements are split by semicolons outside of quotes, comments and
+// PostgreSQL dollar quotes, and the delimiter can be changed by lines like
+// `DELIMITER $$` for MySQL procedures. The statements are not interpolated.
+func (sess *Session) ExecScript(ctx context.Context, script string) error {
+	return execScript(ctx, sess, sess.EventReceiver, sess.Dialect, script)
+}
+
+// ExecScript executes the statements of script in order in the transaction.
+func (tx *Tx) ExecScript(ctx context.Context, script string) error {
+	return execScript(ctx, tx, tx.EventReceiver, tx.Dialect, script)
+}
+
+func execScript(ctx context.Context, runner runner, log EventReceiver, d Dialect, script string) error {
+	for _, stmt := range splitScript(script, d) {
+		err := execScriptStmt(ctx, runner, log, stmt.query)
+		if err != nil {
+			return &ScriptError{
+				Line:      stmt.line,
+				Statement: stmt.query,
+				Err:       err,
+			}
+		}
+	}
+	return nil
+}
+
+func execScriptStmt(ctx context.Context, runner runner, log EventReceiver, query string) error {
+	ctx, cancel, err := withTimeout(ctx, runner, log, nil, nil)
+	if err != nil {
+		return err
+	}
+	defer cancel()
+
+	_, err = runner.ExecContext(ctx, query)
+	if err != nil {
+		return log.EventErrKv("dbr.exec.script", err, kvs{
+			"sql": query,
+		})
+	}
+	return nil
+}
+
+// scriptStmt is a statement of script.
+type scriptStmt struct {
+	query string
+	line  int
+}
+
+// splitScript splits script into statements.
+func splitScript(script string, d Dialect) []scriptStmt {
+	var (
+		stmts     []scriptStmt
+		delimiter = ";"
+		start     = -1 // the first byte of code in the statement
+		startLine int
+		line      = 1
+		lineStart = true
+	)
+	caps := dialect.CapabilitiesOf(d)
+	backslash := caps.BackslashEscapes
+	dollar := caps.DollarQuotes
+
+	emit := func(end int) {
+		if start >= 0 {
+			stmts = append(stmts, scriptStmt{
+				query: strings.TrimSpace(script[start:end]),
+				line:  startLine,
+			})
+		}
+		start = -1
+	}
+	// skipTo skips to the end of s, and counts the lines.
+	skipTo := func(i int, s string) int {
+		j := strings.Index(script[i:], s)
+		if j == -1 {
+			j = len(script)
+		} else {
+			j += i + len(s)
+		}
+		line += strings.Count(script[i:j], "\n")
+		return j
+	}
+
+	for i := 0; i < len(script); {
+		c := script[i]
+		if lineStart {
+			lineStart = false
+			rest := strings.TrimLeft(script[i:], " \t")
+			if start == -1 && len(rest) > 10 && strings.EqualFold(rest[:10], "DELIMITER ") {
+				end := strings.IndexByte(rest, '\n')
+				if end == -1 {
+					end = len(rest)
+				}
+				delimiter = strings.TrimSpace(rest[10:end])
+				i += len(script[i:]) - len(rest) + end
+				continue
+			}
+		}
+		if c == '\n' {
+			line++
+			lineStart = true
+			i++
+			continue
+		}
+		if c == ' ' || c == '\t' || c == '\r' {
+			i++
+			continue
+		}
+		if start == -1 && strings.HasPrefix(script[i:], delimiter) {
+			// empty statement
+			i += len(delimiter)
+			continue
+		}
+
+		switch {
+		case isLineComment(script[i:], d):
+			end := strings.IndexByte(script[i:], '\n')
+			if end == -1 {
+				end = len(script) - i
+			}
+			i += end
+			continue
+		case strings.HasPrefix(script[i:], "/*"):
+			if strings.HasPrefix(script[i:], "/*!") {
+				// mysql executable comments are code
+				break
+			}
+			i = skipTo(i+2, "*/")
+			continue
+		}
+
+		if start == -1 {
+			start, startLine = i, line
+		}
+		switch {
+		case strings.HasPrefix(script[i:], delimiter):
+			emit(i)
+			i += len(delimiter)
+		case c == '\'' || c == '"' || c == '`':
+			// E'...' is the escape string in postgres
+			escape := backslash && c != '`' ||
+				dollar && c == '\'' && i > 0 && (script[i-1] == 'E' || script[i-1] == 'e')
+			i = skipQuoted(script, i, escape, &line)
+		case c == '$' && dollar:
+			if tag, ok := dollarTag(script[i:]); ok {
+				i = skipTo(i+len(tag), tag)
+			} else {
+				i++
+			}
+		case strings.HasPrefix(script[i:], "/*"):
+			i = skipTo(i+2, "*/")
+		default:
+			i++
+		}
+	}
+	emit(len(script))
+	return stmts
+}
+
+// isLineComment reports whether s starts with a line comment.
+// In mysql, -- must be followed by a space, and # starts a comment.
+func isLineComment(s string, d Dialect) bool {
+	if dialect.CapabilitiesOf(d).HashComments {
+		return s[0] == '#' || strings.HasPrefix(s, "--") && (len(s) == 2 || s[2] <= ' ')
+	}
+	return strings.HasPrefix(s, "--")
+}
+
+// skipQuoted returns the index after the quoted string at i, which ends with
+// the same quote. Doubled quotes are escaped, and so is backslash if escape.
+func skipQuoted(script string, i int, escape bool, line *int) int {
+	quote := script[i]
+	for i++; i < len(script); i++ {
+		switch script[i] {
+		case '\n':
+			*line++
+		case '\\':
+			if escape && i+1 < len(script) {
+				i++
+				if script[i] == '\n' {
+					*line++
+				}
+			}
+		case quote:
+			if i+1 < len(script) && script[i+1] == quote {
+				i++
+				continue
+			}
+			return i + 1
+		}
+	}
+	return len(script)
+}
+
+// dollarTag returns the opening tag of a dollar-quoted string like $$ or $body$.
+func dollarTag(s string) (string, bool) {
+	for i := 1; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == '$':
+			return s[:i+1], true
+		case c == '_' || 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z':
+		case '0' <= c && c <= '9' && i > 1:
+		default:
+			return "", false
+		}
+	}
+	return "", false
+}
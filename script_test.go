@@ -0,0 +1,81 @@
+package dbr
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/jiyeyuran/dbr/v2/dialect"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitScript(t *testing.T) {
+	for _, test := range []struct {
+		dialect Dialect
+		script  string
+		want    []scriptStmt
+	}{
+		{
+			dialect: dialect.PostgreSQL,
+			script: `-- schema
+CREATE TABLE a (id int);;
+
+/* seed; */
+INSERT INTO a VALUES (1), (2);
+INSERT INTO a (name) VALUES ('x;''y', "z;")`,
+			want: []scriptStmt{
+				{query: "CREATE TABLE a (id int)", line: 2},
+				{query: "INSERT INTO a VALUES (1), (2)", line: 5},
+				{query: `INSERT INTO a (name) VALUES ('x;''y', "z;")`, line: 6},
+			},
+		},
+		{
+			dialect: dialect.PostgreSQL,
+			script: `CREATE FUNCTION f() RETURNS int AS $body$
+BEGIN
+  RETURN 1; -- $$;
+END;
+$body$ LANGUAGE plpgsql;
+SELECT E'\';', $1;`,
+			want: []scriptStmt{
+				{query: "CREATE FUNCTION f() RETURNS int AS $body$\nBEGIN\n  RETURN 1; -- $$;\nEND;\n$body$ LANGUAGE plpgsql", line: 1},
+				{query: `SELECT E'\';', $1`, line: 6},
+			},
+		},
+		{
+			dialect: dialect.MySQL,
+			script: `# procedures
+DELIMITER $$
+CREATE PROCEDURE p()
+BEGIN
+  SELECT 'a\';'; SELECT 1--1;
+END$$
+DELIMITER ;
+CALL p();`,
+			want: []scriptStmt{
+				{query: "CREATE PROCEDURE p()\nBEGIN\n  SELECT 'a\\';'; SELECT 1--1;\nEND", line: 3},
+				{query: "CALL p()", line: 8},
+			},
+		},
+	} {
+		require.Equal(t, test.want, splitScript(test.script, test.dialect))
+	}
+}
+
+func TestExecScript(t *testing.T) {
+	sess, mock := newMockSession(t, dialect.MySQL)
+
+	failed := errors.New("failed")
+	mock.ExpectExec(regexp.QuoteMeta("CREATE TABLE a (id int)")).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO a VALUES (?)")).WillReturnError(failed)
+
+	err := sess.ExecScript(context.Background(), "CREATE TABLE a (id int);\n\nINSERT INTO a VALUES (?);\nDROP TABLE a;\n")
+	var scriptErr *ScriptError
+	require.True(t, errors.As(err, &scriptErr))
+	require.Equal(t, 3, scriptErr.Line)
+	require.Equal(t, "INSERT INTO a VALUES (?)", scriptErr.Statement)
+	require.True(t, errors.Is(err, failed))
+	require.NoError(t, mock.ExpectationsWereMet())
+}
@@ -3,7 +3,9 @@ package dbr
 import (
 	"context"
 	"database/sql"
+	"reflect"
 	"strconv"
+	"time"
 
 	"github.com/jiyeyuran/dbr/v2/dialect"
 )
@@ -16,22 +18,36 @@ type SelectStmt struct {
 
 	raw
 
-	IsDistinct bool
+	IsDistinct       bool
+	DistinctOnColumn []string
 
 	Column    []interface{}
+	IntoTable string
 	Table     interface{}
+	Sample    Builder
+	AsOf      Builder
+	IndexHint []Builder
 	JoinTable []Builder
 
-	WhereCond  []Builder
-	Group      []Builder
-	HavingCond []Builder
-	Order      []Builder
-	Suffixes   []Builder
+	WhereCond   []Builder
+	Group       []Builder
+	HavingCond  []Builder
+	QualifyCond []Builder
+	Order       []Builder
+	Suffixes    []Builder
 
 	LimitCount  int64
 	OffsetCount int64
+	WithTies    bool
 
+	ctes     []*cte
+	lock     *rowLock
 	comments Comments
+	tags     CommentTags
+
+	timeout time.Duration
+	primary bool
+	replica bool
 }
 
 type SelectBuilder = SelectStmt
@@ -50,36 +66,90 @@ func (b *SelectStmt) Build(d Dialect, buf Buffer) error {
 		return err
 	}
 
+	if b.IntoTable != "" && d != dialect.MSSQL {
+		buf.WriteString("CREATE TABLE ")
+		buf.WriteString(quoteTable(d, b.runner, b.IntoTable))
+		buf.WriteString(" AS ")
+	}
+
+	limit := b.limitClause()
+	limit.wrap(d, buf)
+
+	err = buildWith(d, buf, b.ctes)
+	if err != nil {
+		return err
+	}
+
 	buf.WriteString("SELECT ")
 
-	if b.IsDistinct {
+	if b.timeout > 0 && dialect.CapabilitiesOf(d).StatementTimeoutStyle == dialect.MaxExecutionTime {
+		buf.WriteString("/*+ MAX_EXECUTION_TIME(")
+		buf.WriteString(strconv.FormatInt(durationMillis(b.timeout), 10))
+		buf.WriteString(") */ ")
+	}
+
+	if len(b.DistinctOnColumn) > 0 {
+		if !dialect.CapabilitiesOf(d).SupportsDistinctOn {
+			return errDialectNotSupported("DISTINCT ON")
+		}
+		buf.WriteString("DISTINCT ON (")
+		for i, col := range b.DistinctOnColumn {
+			if i > 0 {
+				buf.WriteString(", ")
+			}
+			buf.WriteString(d.QuoteIdent(col))
+		}
+		buf.WriteString(") ")
+	} else if b.IsDistinct {
 		buf.WriteString("DISTINCT ")
 	}
 
+	err = limit.buildTop(d, buf)
+	if err != nil {
+		return err
+	}
+
 	for i, col := range b.Column {
 		if i > 0 {
 			buf.WriteString(", ")
 		}
 		switch col := col.(type) {
 		case string:
-			// FIXME: no quote ident
-			buf.WriteString(col)
+			buf.WriteString(quoteIdent(d, col))
 		default:
 			buf.WriteString(placeholder)
 			buf.WriteValue(col)
 		}
 	}
 
+	if b.IntoTable != "" && d == dialect.MSSQL {
+		buf.WriteString(" INTO ")
+		buf.WriteString(quoteTable(d, b.runner, b.IntoTable))
+	}
+
 	if b.Table != nil {
 		buf.WriteString(" FROM ")
 		switch table := b.Table.(type) {
 		case string:
-			// FIXME: no quote ident
-			buf.WriteString(table)
+			if schema := qualifyTable(b.runner, table); schema != "" {
+				buf.WriteString(d.QuoteIdent(schema))
+				buf.WriteString(".")
+			}
+			buf.WriteString(quoteIdent(d, table))
 		default:
 			buf.WriteString(placeholder)
 			buf.WriteValue(table)
 		}
+		if b.Sample != nil {
+			err := b.Sample.Build(d, buf)
+			if err != nil {
+				return err
+			}
+		}
+		err := buildIndexHints(d, buf, b.IndexHint)
+		if err != nil {
+			return err
+		}
 		if len(b.JoinTable) > 0 {
 			for _, join := range b.JoinTable {
 				err := join.Build(d, buf)
@@ -88,6 +158,12 @@ func (b *SelectStmt) Build(d Dialect, buf Buffer) error {
 				}
 			}
 		}
+		if b.AsOf != nil {
+			err := b.AsOf.Build(d, buf)
+			if err != nil {
+				return err
+			}
+		}
 	}
 
 	if len(b.WhereCond) > 0 {
@@ -119,6 +195,17 @@ func (b *SelectStmt) Build(d Dialect, buf Buffer) error {
 		}
 	}
 
+	if len(b.QualifyCond) > 0 {
+		if !dialect.CapabilitiesOf(d).SupportsQualify {
+			return errDialectNotSupported("QUALIFY")
+		}
+		buf.WriteString(" QUALIFY ")
+		err := And(b.QualifyCond...).Build(d, buf)
+		if err != nil {
+			return err
+		}
+	}
+
 	if len(b.Order) > 0 {
 		buf.WriteString(" ORDER BY ")
 		for i, order := range b.Order {
@@ -132,17 +219,25 @@ func (b *SelectStmt) Build(d Dialect, buf Buffer) error {
 		}
 	}
 
-	if d == dialect.MSSQL {
-		b.addMSSQLLimits(buf)
-	} else {
-		if b.LimitCount >= 0 {
-			buf.WriteString(" LIMIT ")
-			buf.WriteString(strconv.FormatInt(b.LimitCount, 10))
+	var order Builder
+	if len(b.Order) == 0 && len(b.Column) > 0 {
+		switch col := b.Column[0].(type) {
+		case string:
+			order = ident(col)
+		default:
+			order = Expr(placeholder, col)
 		}
+	}
+	err = limit.build(d, buf, order)
+	if err != nil {
+		return err
+	}
 
-		if b.OffsetCount >= 0 {
-			buf.WriteString(" OFFSET ")
-			buf.WriteString(strconv.FormatInt(b.OffsetCount, 10))
+	if b.lock != nil {
+		buf.WriteString(" ")
+		err := b.lock.Build(d, buf)
+		if err != nil {
+			return err
 		}
 	}
 
@@ -159,43 +254,18 @@ func (b *SelectStmt) Build(d Dialect, buf Buffer) error {
 	return nil
 }
 
-// https://docs.microsoft.com/en-us/previous-versions/sql/sql-server-2012/ms188385(v=sql.110)
-func (b *SelectStmt) addMSSQLLimits(buf Buffer) {
-	limitCount := b.LimitCount
-	offsetCount := b.OffsetCount
-	if limitCount < 0 && offsetCount < 0 {
-		return
-	}
-	if offsetCount < 0 {
-		offsetCount = 0
-	}
-
-	if len(b.Order) == 0 {
-		// ORDER is required for OFFSET / FETCH
-		buf.WriteString(" ORDER BY ")
-		col := b.Column[0]
-		switch col := col.(type) {
-		case string:
-			// FIXME: no quote ident
-			buf.WriteString(col)
-		default:
-			buf.WriteString(placeholder)
-			buf.WriteValue(col)
-		}
-	}
-
-	buf.WriteString(" OFFSET ")
-	buf.WriteString(strconv.FormatInt(offsetCount, 10))
-	buf.WriteString(" ROWS ")
-
-	if limitCount >= 0 {
-		buf.WriteString(" FETCH FIRST ")
-		buf.WriteString(strconv.FormatInt(limitCount, 10))
-		buf.WriteString(" ROWS ONLY ")
+func (b *SelectStmt) limitClause() limitClause {
+	return limitClause{
+		limit:    b.LimitCount,
+		offset:   b.OffsetCount,
+		withTies: b.WithTies,
+		top:      true,
 	}
 }
 
 // Select creates a SelectStmt.
+// The string columns that are names like "t.id" are quoted, and the others
+// like "COUNT(*)" are written as they are.
 func Select(column ...interface{}) *SelectStmt {
 	return &SelectStmt{
 		Column:      column,
@@ -261,17 +331,76 @@ func (tx *Tx) SelectBySql(query string, value ...interface{}) *SelectStmt {
 }
 
 // From specifies table to select from.
-// table can be Builder like SelectStmt, or string.
+// table can be Builder like SelectStmt, or string, which is quoted if it is
+// a name like "schema.table".
 func (b *SelectStmt) From(table interface{}) *SelectStmt {
 	b.Table = table
 	return b
 }
 
+// ColumnsFromStruct adds the quoted columns of struct v like ColumnsOf,
+// with the NameMapper of the session.
+func (b *SelectStmt) ColumnsFromStruct(v interface{}, except ...string) *SelectStmt {
+	for _, col := range newTagStoreFor(b.runner).columnsOf(reflect.TypeOf(v), except) {
+		b.Column = append(b.Column, I(col))
+	}
+	return b
+}
+
+// Into creates table with the result.
+// It builds `SELECT ... INTO table` on mssql, and `CREATE TABLE table AS SELECT ...` on others.
+func (b *SelectStmt) Into(table string) *SelectStmt {
+	b.IntoTable = table
+	return b
+}
+
+// FromSelect specifies a subquery with alias as the table.
+func (b *SelectStmt) FromSelect(sub *SelectStmt, alias string) *SelectStmt {
+	return b.From(BuildFunc(func(d Dialect, buf Buffer) error {
+		buf.WriteString(placeholder)
+		buf.WriteValue(sub)
+		// oracle does not allow AS for table alias
+		if dialect.CapabilitiesOf(d).SupportsTableAliasAs {
+			buf.WriteString(" AS")
+		}
+		buf.WriteString(" ")
+		buf.WriteString(d.QuoteIdent(alias))
+		return nil
+	}))
+}
+
+// With adds a common table expression to the WITH clause.
+// builder can be Builder like SelectStmt, and its values are interpolated.
+func (b *SelectStmt) With(name string, builder Builder) *SelectStmt {
+	b.ctes = append(b.ctes, &cte{name: name, builder: builder})
+	return b
+}
+
+// WithRecursive adds a recursive common table expression to the WITH clause.
+// The anchor and recursive parts are combined with UNION ALL, and
+// the recursive part can refer to the expression by name.
+func (b *SelectStmt) WithRecursive(name string, column []string, anchor, recursive Builder) *SelectStmt {
+	b.ctes = append(b.ctes, &cte{
+		name:      name,
+		column:    column,
+		builder:   UnionAll(anchor, recursive),
+		recursive: true,
+	})
+	return b
+}
+
 func (b *SelectStmt) Distinct() *SelectStmt {
 	b.IsDistinct = true
 	return b
 }
 
+// DistinctOn keeps only the first row of each set of rows where col are equal.
+// The columns are quoted. It is only supported by PostgreSQL.
+func (b *SelectStmt) DistinctOn(col ...string) *SelectStmt {
+	b.DistinctOnColumn = col
+	return b
+}
+
 // Where adds a where condition.
 // query can be Builder or string. value is used only if query type is string.
 func (b *SelectStmt) Where(query interface{}, value ...interface{}) *SelectStmt {
@@ -296,10 +425,22 @@ func (b *SelectStmt) Having(query interface{}, value ...interface{}) *SelectStmt
 	return b
 }
 
-// GroupBy specifies columns for grouping.
+// Qualify adds a condition to filter the results of window functions in snowflake and bigquery.
+// query can be Builder or string. value is used only if query type is string.
+func (b *SelectStmt) Qualify(query interface{}, value ...interface{}) *SelectStmt {
+	switch query := query.(type) {
+	case string:
+		b.QualifyCond = append(b.QualifyCond, Expr(query, value...))
+	case Builder:
+		b.QualifyCond = append(b.QualifyCond, query)
+	}
+	return b
+}
+
+// GroupBy specifies columns for grouping, which are quoted.
 func (b *SelectStmt) GroupBy(col ...string) *SelectStmt {
 	for _, group := range col {
-		b.Group = append(b.Group, Expr(group))
+		b.Group = append(b.Group, I(group))
 	}
 	return b
 }
@@ -314,9 +455,39 @@ func (b *SelectStmt) OrderDesc(col string) *SelectStmt {
 	return b
 }
 
+// OrderAscNullsFirst sorts by col in ascending order with nulls first.
+func (b *SelectStmt) OrderAscNullsFirst(col string) *SelectStmt {
+	b.Order = append(b.Order, orderNulls(col, asc, nullsFirst))
+	return b
+}
+
+// OrderAscNullsLast sorts by col in ascending order with nulls last.
+func (b *SelectStmt) OrderAscNullsLast(col string) *SelectStmt {
+	b.Order = append(b.Order, orderNulls(col, asc, nullsLast))
+	return b
+}
+
+// OrderDescNullsFirst sorts by col in descending order with nulls first.
+func (b *SelectStmt) OrderDescNullsFirst(col string) *SelectStmt {
+	b.Order = append(b.Order, orderNulls(col, desc, nullsFirst))
+	return b
+}
+
+// OrderDescNullsLast sorts by col in descending order with nulls last.
+func (b *SelectStmt) OrderDescNullsLast(col string) *SelectStmt {
+	b.Order = append(b.Order, orderNulls(col, desc, nullsLast))
+	return b
+}
+
 // OrderBy specifies columns for ordering.
-func (b *SelectStmt) OrderBy(col string) *SelectStmt {
-	b.Order = append(b.Order, Expr(col))
+// col can be Builder or string.
+func (b *SelectStmt) OrderBy(col interface{}) *SelectStmt {
+	switch col := col.(type) {
+	case string:
+		b.Order = append(b.Order, Expr(col))
+	case Builder:
+		b.Order = append(b.Order, col)
+	}
 	return b
 }
 
@@ -325,6 +496,14 @@ func (b *SelectStmt) Limit(n uint64) *SelectStmt {
 	return b
 }
 
+// LimitWithTies limits to n rows, including the rows that tie with the last one in ORDER BY.
+// It is supported by postgres 13+, oracle and mssql.
+func (b *SelectStmt) LimitWithTies(n uint64) *SelectStmt {
+	b.LimitCount = int64(n)
+	b.WithTies = true
+	return b
+}
+
 func (b *SelectStmt) Offset(n uint64) *SelectStmt {
 	b.OffsetCount = int64(n)
 	return b
@@ -336,6 +515,39 @@ func (b *SelectStmt) Suffix(suffix string, value ...interface{}) *SelectStmt {
 	return b
 }
 
+// ForUpdate locks selected rows against concurrent updates with `FOR UPDATE`.
+func (b *SelectStmt) ForUpdate() *SelectStmt {
+	b.lock = &rowLock{}
+	return b
+}
+
+// ForShare locks selected rows against concurrent updates with `FOR SHARE`,
+// while still allowing other transactions to read them.
+func (b *SelectStmt) ForShare() *SelectStmt {
+	b.lock = &rowLock{share: true}
+	return b
+}
+
+// SkipLocked skips rows that cannot be locked immediately.
+// It implies ForUpdate if no lock is specified.
+func (b *SelectStmt) SkipLocked() *SelectStmt {
+	if b.lock == nil {
+		b.ForUpdate()
+	}
+	b.lock.skipLocked = true
+	return b
+}
+
+// NoWait fails instead of waiting if rows cannot be locked immediately.
+// It implies ForUpdate if no lock is specified.
+func (b *SelectStmt) NoWait() *SelectStmt {
+	if b.lock == nil {
+		b.ForUpdate()
+	}
+	b.lock.noWait = true
+	return b
+}
+
 // Paginate fetches a page in a naive way for a small set of data.
 func (b *SelectStmt) Paginate(page, perPage uint64) *SelectStmt {
 	b.Limit(perPage)
@@ -358,31 +570,81 @@ func (b *SelectStmt) Comment(comment string) *SelectStmt {
 	return b
 }
 
+// CommentTag adds a tag to the comment appended to the query,
+// like CommentTag("action", "list"). See CommentTags.
+func (b *SelectStmt) CommentTag(key, value string) *SelectStmt {
+	b.tags = b.tags.Add(key, value)
+	return b
+}
+
+func (b *SelectStmt) commentTags(context.Context) map[string]string {
+	return b.tags
+}
+
+// TableSample selects a random sample of percent of the table with method like BERNOULLI or SYSTEM.
+// It is only supported by PostgreSQL and MSSQL.
+func (b *SelectStmt) TableSample(method string, percent float64) *SelectStmt {
+	b.Sample = tableSample(method, percent)
+	return b
+}
+
+// AsOfSystemTime reads the historical data at the time in cockroachdb.
+// value can be time.Time, string like "-10s", or Builder like Expr("follower_read_timestamp()").
+func (b *SelectStmt) AsOfSystemTime(value interface{}) *SelectStmt {
+	b.AsOf = asOfSystemTime(value)
+	return b
+}
+
+// UseIndex suggests indexes to use for the table. It is only rendered for MySQL.
+func (b *SelectStmt) UseIndex(index ...string) *SelectStmt {
+	b.IndexHint = append(b.IndexHint, indexHint("USE", index))
+	return b
+}
+
+// ForceIndex forces indexes to use for the table. It is only rendered for MySQL.
+func (b *SelectStmt) ForceIndex(index ...string) *SelectStmt {
+	b.IndexHint = append(b.IndexHint, indexHint("FORCE", index))
+	return b
+}
+
+// IgnoreIndex prevents indexes from being used for the table. It is only rendered for MySQL.
+func (b *SelectStmt) IgnoreIndex(index ...string) *SelectStmt {
+	b.IndexHint = append(b.IndexHint, indexHint("IGNORE", index))
+	return b
+}
+
 // Join add inner-join.
 // on can be Builder or string.
 func (b *SelectStmt) Join(table, on interface{}) *SelectStmt {
-	b.JoinTable = append(b.JoinTable, join(inner, table, on))
+	b.JoinTable = append(b.JoinTable, join(inner, joinTable(b.runner, table), on))
 	return b
 }
 
 // LeftJoin add left-join.
 // on can be Builder or string.
 func (b *SelectStmt) LeftJoin(table, on interface{}) *SelectStmt {
-	b.JoinTable = append(b.JoinTable, join(left, table, on))
+	b.JoinTable = append(b.JoinTable, join(left, joinTable(b.runner, table), on))
 	return b
 }
 
 // RightJoin add right-join.
 // on can be Builder or string.
 func (b *SelectStmt) RightJoin(table, on interface{}) *SelectStmt {
-	b.JoinTable = append(b.JoinTable, join(right, table, on))
+	b.JoinTable = append(b.JoinTable, join(right, joinTable(b.runner, table), on))
 	return b
 }
 
 // FullJoin add full-join.
 // on can be Builder or string.
+// It is not supported by MySQL.
 func (b *SelectStmt) FullJoin(table, on interface{}) *SelectStmt {
-	b.JoinTable = append(b.JoinTable, join(full, table, on))
+	b.JoinTable = append(b.JoinTable, join(full, joinTable(b.runner, table), on))
+	return b
+}
+
+// CrossJoin add cross-join.
+func (b *SelectStmt) CrossJoin(table interface{}) *SelectStmt {
+	b.JoinTable = append(b.JoinTable, join(cross, joinTable(b.runner, table), nil))
 	return b
 }
 
@@ -391,6 +653,41 @@ func (b *SelectStmt) As(alias string) Builder {
 	return as(b, alias)
 }
 
+// Timeout cancels the statement after d, or after the timeout of the session
+// if it is shorter. MySQL also gets the MAX_EXECUTION_TIME hint, and
+// PostgreSQL sets statement_timeout if the statement runs in a transaction.
+func (b *SelectStmt) Timeout(d time.Duration) *SelectStmt {
+	b.timeout = d
+	return b
+}
+
+func (b *SelectStmt) statementTimeout() time.Duration {
+	return b.timeout
+}
+
+// OnPrimary reads from the primary instead of the replicas,
+// like reading the writes just made.
+func (b *SelectStmt) OnPrimary() *SelectStmt {
+	b.primary = true
+	return b
+}
+
+// OnReplica reads the raw query of SelectBySql from the replicas, which is
+// sent to the primary by default since it can write, like a function call.
+func (b *SelectStmt) OnReplica() *SelectStmt {
+	b.replica = true
+	return b
+}
+
+// Exec executes the statement, usually with Into.
+func (b *SelectStmt) Exec() (*Result, error) {
+	return b.ExecContext(context.Background())
+}
+
+func (b *SelectStmt) ExecContext(ctx context.Context) (*Result, error) {
+	return exec(ctx, b.runner, b.EventReceiver, b, b.Dialect)
+}
+
 // Rows executes the query and returns the rows returned, or any error encountered.
 func (b *SelectStmt) Rows() (*sql.Rows, error) {
 	return b.RowsContext(context.Background())
@@ -431,30 +728,133 @@ func (b *SelectStmt) Load(value interface{}) (int, error) {
 	return b.LoadContext(context.Background(), value)
 }
 
+// Count returns the number of rows of the statement, without the columns,
+// ORDER BY and LIMIT. The statement is counted as a subquery if it is
+// raw, distinct, grouped or limited.
+func (b *SelectStmt) Count() (int64, error) {
+	return b.CountContext(context.Background())
+}
+
+// CountContext is like Count with context.
+func (b *SelectStmt) CountContext(ctx context.Context) (int64, error) {
+	var c *SelectStmt
+	if b.raw.Query != "" || b.IsDistinct || len(b.DistinctOnColumn) > 0 ||
+		len(b.Group) > 0 || len(b.HavingCond) > 0 || len(b.QualifyCond) > 0 ||
+		b.LimitCount >= 0 || b.OffsetCount >= 0 {
+		c = b.wrap(Expr("COUNT(*)"))
+	} else {
+		copied := *b
+		c = &copied
+		c.Column = []interface{}{Expr("COUNT(*)")}
+		c.IntoTable = ""
+		c.Order = nil
+		c.lock = nil
+	}
+	var count int64
+	err := c.LoadOneContext(ctx, &count)
+	return count, err
+}
+
+// wrap returns a statement selecting column from b as a subquery, which runs
+// like b with its routing, timeout and comments.
+func (b *SelectStmt) wrap(column interface{}) *SelectStmt {
+	sub := *b
+	sub.IntoTable = ""
+	sub.comments = nil
+	sub.tags = nil
+	sub.timeout = 0
+	c := Select(column).From(sub.As("t"))
+	c.runner = b.runner
+	c.EventReceiver = b.EventReceiver
+	c.Dialect = b.Dialect
+	c.comments = b.comments
+	c.tags = b.tags
+	c.timeout = b.timeout
+	// the raw query and the lock of b are in the subquery
+	c.primary = b.primary || b.lock != nil || b.raw.Query != "" && !b.replica
+	c.replica = b.replica
+	return c
+}
+
+// Exists reports whether the statement returns any row, by selecting 1
+// with LIMIT 1. Like Count, the statement with DISTINCT, GROUP BY, HAVING,
+// QUALIFY, LIMIT or OFFSET is queried as a subquery, so that the aliases
+// of its columns are kept.
+func (b *SelectStmt) Exists() (bool, error) {
+	return b.ExistsContext(context.Background())
+}
+
+// ExistsContext is like Exists with context.
+func (b *SelectStmt) ExistsContext(ctx context.Context) (bool, error) {
+	var c *SelectStmt
+	if b.raw.Query != "" || b.IsDistinct || len(b.DistinctOnColumn) > 0 ||
+		len(b.Group) > 0 || len(b.HavingCond) > 0 || len(b.QualifyCond) > 0 ||
+		b.LimitCount >= 0 || b.OffsetCount >= 0 {
+		c = b.wrap(Expr("1")).Limit(1)
+	} else {
+		copied := *b
+		c = &copied
+		c.Column = []interface{}{Expr("1")}
+		c.IntoTable = ""
+		c.Order = nil
+		c.LimitCount = 1
+	}
+	var one int
+	count, err := c.LoadContext(ctx, &one)
+	return count > 0, err
+}
+
+// LoadPairs loads the rows of two columns into map dest, or a pointer to map,
+// with the first column as the key and the second as the value,
+// like sess.Select("id", "name").From("user").LoadPairs(names).
+func (b *SelectStmt) LoadPairs(dest interface{}) (int, error) {
+	return b.LoadPairsContext(context.Background(), dest)
+}
+
+// LoadPairsContext is like LoadPairs with context.
+func (b *SelectStmt) LoadPairsContext(ctx context.Context, dest interface{}) (int, error) {
+	var count int
+	err := queryWith(ctx, b.runner, b.EventReceiver, b, b.Dialect, func(rows *sql.Rows) (err error) {
+		count, err = loadPairs(rows, dest, timeOptionsOf(b.runner))
+		return
+	})
+	return count, err
+}
+
+// LoadMaps loads each row into a map of column name to value,
+// for ad-hoc queries without a struct.
+// Numeric and text columns are loaded as int64, float64 or string instead of bytes.
+func (b *SelectStmt) LoadMaps() ([]map[string]interface{}, error) {
+	return b.LoadMapsContext(context.Background())
+}
+
+// LoadMapsContext is like LoadMaps with context.
+func (b *SelectStmt) LoadMapsContext(ctx context.Context) ([]map[string]interface{}, error) {
+	return queryMaps(ctx, b.runner, b.EventReceiver, b, b.Dialect)
+}
+
 // Iterate executes the query and returns the Iterator, or any error encountered.
 func (b *SelectStmt) Iterate() (Iterator, error) {
 	return b.IterateContext(context.Background())
 }
 
-// IterateContext executes the query and returns the Iterator, or any error encountered.
-func (b *SelectStmt) IterateContext(ctx context.Context) (Iterator, error) {
-	_, rows, err := queryRows(ctx, b.runner, b.EventReceiver, b, b.Dialect)
-	if err != nil {
-		if rows != nil {
-			rows.Close()
-		}
-		return nil, err
-	}
-	columns, err := rows.Columns()
+// ForEach scans each row into dest and calls fn, so that a large result
+// is never loaded into memory at once. dest is reused for every row,
+// and the iteration stops at the first error returned by fn.
+func (b *SelectStmt) ForEach(dest interface{}, fn func() error) error {
+	return b.ForEachContext(context.Background(), dest, fn)
+}
+
+// ForEachContext is like ForEach with context.
+func (b *SelectStmt) ForEachContext(ctx context.Context, dest interface{}, fn func() error) error {
+	iter, err := b.IterateContext(ctx)
 	if err != nil {
-		if rows != nil {
-			rows.Close()
-		}
-		return nil, err
-	}
-	iterator := iteratorInternals{
-		rows:    rows,
-		columns: columns,
+		return err
 	}
-	return &iterator, err
+	return forEach(iter, dest, fn)
+}
+
+// IterateContext executes the query and returns the Iterator, or any error encountered.
+func (b *SelectStmt) IterateContext(ctx context.Context) (Iterator, error) {
+	return iterate(ctx, b.runner, b.EventReceiver, b, b.Dialect)
 }
@@ -1,43 +1,75 @@
 package dbr
 
+import "context"
+
 // ReturnInt64 executes the SelectStmt and returns the value as an int64.
 func (b *SelectStmt) ReturnInt64() (int64, error) {
+	return b.ReturnInt64Context(context.Background())
+}
+
+// ReturnInt64Context is like ReturnInt64 with context.
+func (b *SelectStmt) ReturnInt64Context(ctx context.Context) (int64, error) {
 	var v int64
-	err := b.LoadOne(&v)
+	err := b.LoadOneContext(ctx, &v)
 	return v, err
 }
 
 // ReturnInt64s executes the SelectStmt and returns the value as a slice of int64s.
 func (b *SelectStmt) ReturnInt64s() ([]int64, error) {
+	return b.ReturnInt64sContext(context.Background())
+}
+
+// ReturnInt64sContext is like ReturnInt64s with context.
+func (b *SelectStmt) ReturnInt64sContext(ctx context.Context) ([]int64, error) {
 	var v []int64
-	_, err := b.Load(&v)
+	_, err := b.LoadContext(ctx, &v)
 	return v, err
 }
 
 // ReturnUint64 executes the SelectStmt and returns the value as an uint64.
 func (b *SelectStmt) ReturnUint64() (uint64, error) {
+	return b.ReturnUint64Context(context.Background())
+}
+
+// ReturnUint64Context is like ReturnUint64 with context.
+func (b *SelectStmt) ReturnUint64Context(ctx context.Context) (uint64, error) {
 	var v uint64
-	err := b.LoadOne(&v)
+	err := b.LoadOneContext(ctx, &v)
 	return v, err
 }
 
 // ReturnUint64s executes the SelectStmt and returns the value as a slice of uint64s.
 func (b *SelectStmt) ReturnUint64s() ([]uint64, error) {
+	return b.ReturnUint64sContext(context.Background())
+}
+
+// ReturnUint64sContext is like ReturnUint64s with context.
+func (b *SelectStmt) ReturnUint64sContext(ctx context.Context) ([]uint64, error) {
 	var v []uint64
-	_, err := b.Load(&v)
+	_, err := b.LoadContext(ctx, &v)
 	return v, err
 }
 
 // ReturnString executes the SelectStmt and returns the value as a string.
 func (b *SelectStmt) ReturnString() (string, error) {
+	return b.ReturnStringContext(context.Background())
+}
+
+// ReturnStringContext is like ReturnString with context.
+func (b *SelectStmt) ReturnStringContext(ctx context.Context) (string, error) {
 	var v string
-	err := b.LoadOne(&v)
+	err := b.LoadOneContext(ctx, &v)
 	return v, err
 }
 
 // ReturnStrings executes the SelectStmt and returns the value as a slice of strings.
 func (b *SelectStmt) ReturnStrings() ([]string, error) {
+	return b.ReturnStringsContext(context.Background())
+}
+
+// ReturnStringsContext is like ReturnStrings with context.
+func (b *SelectStmt) ReturnStringsContext(ctx context.Context) ([]string, error) {
 	var v []string
-	_, err := b.Load(&v)
+	_, err := b.LoadContext(ctx, &v)
 	return v, err
 }
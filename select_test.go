@@ -1,11 +1,16 @@
 package dbr
 
 import (
+	"context"
+	"database/sql"
+	"errors"
+	"regexp"
 	"testing"
+	"time"
 
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
 	"github.com/jiyeyuran/dbr/v2/dialect"
 	"github.com/lib/pq"
-
 	"github.com/stretchr/testify/require"
 )
 
@@ -26,7 +31,7 @@ func TestSelectStmt(t *testing.T) {
 
 	err := builder.Build(dialect.MySQL, buf)
 	require.NoError(t, err)
-	require.Equal(t, "/* SELECT TEST */\nSELECT DISTINCT a, b FROM ? LEFT JOIN `table2` ON table.a1 = table.a2 WHERE (`c` = ?) GROUP BY d HAVING (`e` = ?) ORDER BY f ASC LIMIT 3 OFFSET 4 FOR UPDATE", buf.String())
+	require.Equal(t, "/* SELECT TEST */\nSELECT DISTINCT `a`, `b` FROM ? LEFT JOIN `table2` ON table.a1 = table.a2 WHERE (`c` = ?) GROUP BY `d` HAVING (`e` = ?) ORDER BY `f` ASC LIMIT 3 OFFSET 4 FOR UPDATE", buf.String())
 	// two functions cannot be compared
 	require.Equal(t, 3, len(buf.Value()))
 }
@@ -199,3 +204,457 @@ func TestPostgresArray(t *testing.T) {
 
 	require.Equal(t, []int64{1, 2, 3}, ns)
 }
+
+func TestSelectWith(t *testing.T) {
+	buf := NewBuffer()
+	builder := Select("*").
+		With("active", Select("id").From("users").Where(Eq("active", true))).
+		With("recent", Select("user_id").From("logins").Where(Gt("at", 1))).
+		From("active").
+		Join("recent", "active.id = recent.user_id")
+
+	err := builder.Build(dialect.MySQL, buf)
+	require.NoError(t, err)
+	require.Equal(t, "WITH `active` AS (SELECT `id` FROM `users` WHERE (`active` = ?)), `recent` AS (SELECT `user_id` FROM `logins` WHERE (`at` > ?)) SELECT * FROM `active` JOIN `recent` ON active.id = recent.user_id", buf.String())
+	require.Equal(t, []interface{}{true, 1}, buf.Value())
+}
+
+func TestSelectWithRecursive(t *testing.T) {
+	for _, test := range []struct {
+		dialect Dialect
+		query   string
+	}{
+		{
+			dialect: dialect.PostgreSQL,
+			query:   `WITH RECURSIVE "tree" ("id", "parent_id") AS (SELECT "id", "parent_id" FROM "categories" WHERE ("id" = ?) UNION ALL SELECT "c"."id", "c"."parent_id" FROM categories c JOIN "tree" ON c.parent_id = tree.id) SELECT * FROM "tree"`,
+		},
+		{
+			dialect: dialect.MSSQL,
+			query:   `WITH [tree] ([id], [parent_id]) AS (SELECT [id], [parent_id] FROM [categories] WHERE ([id] = ?) UNION ALL SELECT [c].[id], [c].[parent_id] FROM categories c JOIN [tree] ON c.parent_id = tree.id) SELECT * FROM [tree]`,
+		},
+	} {
+		buf := NewBuffer()
+		builder := Select("*").
+			WithRecursive("tree", []string{"id", "parent_id"},
+				Select("id", "parent_id").From("categories").Where(Eq("id", 1)),
+				Select("c.id", "c.parent_id").From("categories c").Join("tree", "c.parent_id = tree.id"),
+			).
+			From("tree")
+
+		err := builder.Build(test.dialect, buf)
+		require.NoError(t, err)
+		require.Equal(t, test.query, buf.String())
+		require.Equal(t, []interface{}{1}, buf.Value())
+	}
+}
+
+func TestSelectDistinctOn(t *testing.T) {
+	buf := NewBuffer()
+	builder := Select("user_id", "created_at").
+		DistinctOn("user_id").
+		From("logins").
+		OrderAsc("user_id").
+		OrderDesc("created_at")
+	err := builder.Build(dialect.PostgreSQL, buf)
+	require.NoError(t, err)
+	require.Equal(t, `SELECT DISTINCT ON ("user_id") "user_id", "created_at" FROM "logins" ORDER BY "user_id" ASC, "created_at" DESC`, buf.String())
+
+	err = builder.Build(dialect.MySQL, NewBuffer())
+	require.True(t, errors.Is(err, ErrDialectNotSupported))
+
+	buf = NewBuffer()
+	err = Select("*").DistinctOn("l.user_id").From("logins").Build(dialect.PostgreSQL, buf)
+	require.NoError(t, err)
+	require.Equal(t, `SELECT DISTINCT ON ("l"."user_id") * FROM "logins"`, buf.String())
+}
+
+func TestSelectRowLock(t *testing.T) {
+	for _, test := range []struct {
+		builder *SelectStmt
+		dialect Dialect
+		query   string
+	}{
+		{
+			builder: Select("id").From("jobs").Limit(1).ForUpdate(),
+			dialect: dialect.MySQL,
+			query:   "SELECT `id` FROM `jobs` LIMIT 1 FOR UPDATE",
+		},
+		{
+			builder: Select("id").From("jobs").Limit(1).SkipLocked(),
+			dialect: dialect.PostgreSQL,
+			query:   `SELECT "id" FROM "jobs" LIMIT 1 FOR UPDATE SKIP LOCKED`,
+		},
+		{
+			builder: Select("id").From("jobs").ForShare().NoWait(),
+			dialect: dialect.PostgreSQL,
+			query:   `SELECT "id" FROM "jobs" FOR SHARE NOWAIT`,
+		},
+	} {
+		buf := NewBuffer()
+		err := test.builder.Build(test.dialect, buf)
+		require.NoError(t, err)
+		require.Equal(t, test.query, buf.String())
+	}
+
+	err := Select("id").From("jobs").ForUpdate().Build(dialect.SQLite3, NewBuffer())
+	require.True(t, errors.Is(err, ErrDialectNotSupported))
+}
+
+func TestSelectFullAndCrossJoin(t *testing.T) {
+	buf := NewBuffer()
+	err := Select("*").From("a").
+		FullJoin("b", "a.id = b.a_id").
+		CrossJoin("c").
+		Build(dialect.PostgreSQL, buf)
+	require.NoError(t, err)
+	require.Equal(t, `SELECT * FROM "a" FULL JOIN "b" ON a.id = b.a_id CROSS JOIN "c"`, buf.String())
+
+	err = Select("*").From("a").FullJoin("b", "a.id = b.a_id").Build(dialect.MySQL, NewBuffer())
+	require.True(t, errors.Is(err, ErrDialectNotSupported))
+}
+
+func TestSelectOrderNulls(t *testing.T) {
+	for _, test := range []struct {
+		builder *SelectStmt
+		dialect Dialect
+		query   string
+	}{
+		{
+			builder: Select("*").From("t").OrderAscNullsLast("a").OrderDescNullsFirst("b"),
+			dialect: dialect.PostgreSQL,
+			query:   `SELECT * FROM "t" ORDER BY "a" ASC NULLS LAST, "b" DESC NULLS FIRST`,
+		},
+		{
+			builder: Select("*").From("t").OrderAscNullsLast("a").OrderDescNullsFirst("b"),
+			dialect: dialect.MySQL,
+			query:   "SELECT * FROM `t` ORDER BY ISNULL(`a`) ASC, `a` ASC, ISNULL(`b`) DESC, `b` DESC",
+		},
+		{
+			builder: Select("*").From("t").OrderAscNullsFirst("a"),
+			dialect: dialect.MSSQL,
+			query:   "SELECT * FROM [t] ORDER BY CASE WHEN [a] IS NULL THEN 1 ELSE 0 END DESC, [a] ASC",
+		},
+	} {
+		buf := NewBuffer()
+		err := test.builder.Build(test.dialect, buf)
+		require.NoError(t, err)
+		require.Equal(t, test.query, buf.String())
+	}
+}
+
+func TestSelectIndexHint(t *testing.T) {
+	builder := Select("*").From("t").UseIndex("a", "b").IgnoreIndex("c").Join("u", "t.id = u.t_id")
+
+	buf := NewBuffer()
+	err := builder.Build(dialect.MySQL, buf)
+	require.NoError(t, err)
+	require.Equal(t, "SELECT * FROM `t` USE INDEX (`a`, `b`) IGNORE INDEX (`c`) JOIN `u` ON t.id = u.t_id", buf.String())
+
+	buf = NewBuffer()
+	err = builder.Build(dialect.PostgreSQL, buf)
+	require.NoError(t, err)
+	require.Equal(t, `SELECT * FROM "t" JOIN "u" ON t.id = u.t_id`, buf.String())
+}
+
+func TestSelectTableSample(t *testing.T) {
+	builder := Select("*").From("events").TableSample("SYSTEM", 2.5)
+
+	buf := NewBuffer()
+	err := builder.Build(dialect.PostgreSQL, buf)
+	require.NoError(t, err)
+	require.Equal(t, `SELECT * FROM "events" TABLESAMPLE SYSTEM (2.5)`, buf.String())
+
+	buf = NewBuffer()
+	err = builder.Build(dialect.MSSQL, buf)
+	require.NoError(t, err)
+	require.Equal(t, "SELECT * FROM [events] TABLESAMPLE SYSTEM (2.5 PERCENT)", buf.String())
+
+	err = builder.Build(dialect.MySQL, NewBuffer())
+	require.True(t, errors.Is(err, ErrDialectNotSupported))
+}
+
+func TestSelectFromSelect(t *testing.T) {
+	sub := Select("author_id", "COUNT(*) AS n").From("post").
+		Where(Gt("created_at", "2020-01-01")).
+		GroupBy("author_id")
+	builder := Select("*").FromSelect(sub, "t").Where(Gt("n", 10))
+
+	query, err := InterpolateForDialect("?", []interface{}{builder}, dialect.MySQL)
+	require.NoError(t, err)
+	require.Equal(t, "SELECT * FROM (SELECT `author_id`, COUNT(*) AS n FROM `post` WHERE (`created_at` > '2020-01-01') GROUP BY `author_id`) AS `t` WHERE (`n` > 10)", query)
+}
+
+func TestSelectInto(t *testing.T) {
+	builder := Select("*").From("orders").Where(Lt("created_at", "2020-01-01")).Into("orders_2019")
+
+	for _, test := range []struct {
+		dialect Dialect
+		query   string
+	}{
+		{
+			dialect: dialect.MySQL,
+			query:   "CREATE TABLE `orders_2019` AS SELECT * FROM `orders` WHERE (`created_at` < ?)",
+		},
+		{
+			dialect: dialect.PostgreSQL,
+			query:   `CREATE TABLE "orders_2019" AS SELECT * FROM "orders" WHERE ("created_at" < ?)`,
+		},
+		{
+			dialect: dialect.MSSQL,
+			query:   `SELECT * INTO [orders_2019] FROM [orders] WHERE ([created_at] < ?)`,
+		},
+	} {
+		buf := NewBuffer()
+		err := builder.Build(test.dialect, buf)
+		require.NoError(t, err)
+		require.Equal(t, test.query, buf.String())
+		require.Equal(t, []interface{}{"2020-01-01"}, buf.Value())
+	}
+}
+
+func TestSelectLimitWithTies(t *testing.T) {
+	builder := Select("*").From("scores").OrderDesc("score").LimitWithTies(3)
+
+	buf := NewBuffer()
+	err := builder.Build(dialect.PostgreSQL, buf)
+	require.NoError(t, err)
+	require.Equal(t, `SELECT * FROM "scores" ORDER BY "score" DESC FETCH FIRST 3 ROWS WITH TIES`, buf.String())
+
+	buf = NewBuffer()
+	err = builder.Build(dialect.MSSQL, buf)
+	require.NoError(t, err)
+	require.Equal(t, "SELECT TOP (3) WITH TIES * FROM [scores] ORDER BY [score] DESC", buf.String())
+
+	err = builder.Build(dialect.MySQL, NewBuffer())
+	require.True(t, errors.Is(err, ErrDialectNotSupported))
+
+	builder.Offset(6)
+
+	buf = NewBuffer()
+	err = builder.Build(dialect.PostgreSQL, buf)
+	require.NoError(t, err)
+	require.Equal(t, `SELECT * FROM "scores" ORDER BY "score" DESC OFFSET 6 ROWS FETCH FIRST 3 ROWS WITH TIES`, buf.String())
+
+	err = builder.Build(dialect.MSSQL, NewBuffer())
+	require.True(t, errors.Is(err, ErrDialectNotSupported))
+}
+
+func TestSelectMSSQLLimit(t *testing.T) {
+	buf := NewBuffer()
+	err := Select("a").From("t").Where(Eq("b", 1)).Limit(5).Build(dialect.MSSQL, buf)
+	require.NoError(t, err)
+	require.Equal(t, "SELECT TOP (5) [a] FROM [t] WHERE ([b] = ?)", buf.String())
+
+	buf = NewBuffer()
+	err = Select("a").From("t").OrderAsc("a").Limit(5).Offset(10).Build(dialect.MSSQL, buf)
+	require.NoError(t, err)
+	require.Equal(t, "SELECT [a] FROM [t] ORDER BY [a] ASC OFFSET 10 ROWS FETCH FIRST 5 ROWS ONLY", buf.String())
+}
+
+func TestSelectOracle(t *testing.T) {
+	sub := Select("a").From("t1")
+	builder := Select("*").FromSelect(sub, "s").Where(Eq("a", 1)).OrderAsc("a").Limit(5).Offset(10)
+
+	query, err := InterpolateForDialect("?", []interface{}{builder}, dialect.Oracle)
+	require.NoError(t, err)
+	require.Equal(t, `SELECT * FROM (SELECT "a" FROM "t1") "s" WHERE ("a" = 1) ORDER BY "a" ASC OFFSET 10 ROWS FETCH FIRST 5 ROWS ONLY`, query)
+
+	buf := NewBuffer()
+	err = Select("*").From("t").Where(Eq("a", 1)).ForUpdate().SkipLocked().Build(dialect.Oracle, buf)
+	require.NoError(t, err)
+	require.Equal(t, `SELECT * FROM "t" WHERE ("a" = ?) FOR UPDATE SKIP LOCKED`, buf.String())
+}
+
+func TestSelectAsOfSystemTime(t *testing.T) {
+	builder := Select("*").From("orders").AsOfSystemTime("-10s").Where(Eq("id", 1))
+
+	query, err := InterpolateForDialect("?", []interface{}{builder}, dialect.CockroachDB)
+	require.NoError(t, err)
+	require.Equal(t, `SELECT * FROM "orders" AS OF SYSTEM TIME '-10s' WHERE ("id" = 1)`, query)
+
+	err = builder.Build(dialect.PostgreSQL, NewBuffer())
+	require.True(t, errors.Is(err, ErrDialectNotSupported))
+}
+
+func TestSelectQualify(t *testing.T) {
+	builder := Select("*").From("events").
+		Qualify("ROW_NUMBER() OVER (PARTITION BY user_id ORDER BY ts DESC) = ?", 1)
+
+	buf := NewBuffer()
+	err := builder.Build(dialect.Snowflake, buf)
+	require.NoError(t, err)
+	require.Equal(t, `SELECT * FROM "events" QUALIFY (ROW_NUMBER() OVER (PARTITION BY user_id ORDER BY ts DESC) = ?)`, buf.String())
+
+	err = builder.Build(dialect.MySQL, NewBuffer())
+	require.True(t, errors.Is(err, ErrDialectNotSupported))
+}
+
+func TestSelectQuotedIdent(t *testing.T) {
+	// sort parameter from user input
+	sort := "name`; DROP TABLE users; --"
+	buf := NewBuffer()
+	err := Select(I("u.*")).From(I("app.users").As("u")).OrderBy(I(sort)).Build(dialect.MySQL, buf)
+	require.NoError(t, err)
+
+	query, err := InterpolateForDialect(buf.String(), buf.Value(), dialect.MySQL)
+	require.NoError(t, err)
+	require.Equal(t, "SELECT `u`.* FROM `app`.`users` AS `u` ORDER BY `name``; DROP TABLE users; --`", query)
+}
+
+func TestSelectRowNumLimit(t *testing.T) {
+	oracle, err := dialect.OracleVersion("11.2")
+	require.NoError(t, err)
+
+	for _, test := range []struct {
+		builder Builder
+		query   string
+	}{
+		{
+			builder: Select("a").From("t").OrderAsc("a").Limit(5),
+			query:   `SELECT * FROM (SELECT "a" FROM "t" ORDER BY "a" ASC) WHERE ROWNUM <= 5`,
+		},
+		{
+			builder: Select("a").From("t").OrderAsc("a").Limit(5).Offset(10),
+			query:   `SELECT * FROM (SELECT dbr_q.*, ROWNUM dbr_rownum FROM (SELECT "a" FROM "t" ORDER BY "a" ASC) dbr_q WHERE ROWNUM <= 15) WHERE dbr_rownum > 10`,
+		},
+		{
+			builder: Select("a").From("t").Offset(10),
+			query:   `SELECT * FROM (SELECT dbr_q.*, ROWNUM dbr_rownum FROM (SELECT "a" FROM "t") dbr_q) WHERE dbr_rownum > 10`,
+		},
+		{
+			builder: Union(Select("a").From("t1"), Select("a").From("t2")).Limit(2),
+			query:   `SELECT * FROM (SELECT "a" FROM "t1" UNION SELECT "a" FROM "t2") WHERE ROWNUM <= 2`,
+		},
+	} {
+		buf := NewBuffer()
+		err := test.builder.Build(oracle, buf)
+		require.NoError(t, err)
+		require.Equal(t, test.query, buf.String())
+	}
+
+	err = Select("a").From("t").OrderAsc("a").LimitWithTies(5).Build(oracle, NewBuffer())
+	require.True(t, errors.Is(err, ErrDialectNotSupported))
+}
+
+func TestSelectForEach(t *testing.T) {
+	sess, mock := newMockSession(t, dialect.MySQL)
+
+	type suggestion struct {
+		ID    int64
+		Title string
+	}
+	mock.ExpectQuery("SELECT `id`, `title` FROM `suggestions`").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "title"}).AddRow(1, "a").AddRow(2, "b").AddRow(3, "c"))
+	var row suggestion
+	var titles []string
+	stop := errors.New("stop")
+	err := sess.Select("id", "title").From("suggestions").ForEach(&row, func() error {
+		titles = append(titles, row.Title)
+		if row.ID == 2 {
+			return stop
+		}
+		return nil
+	})
+	require.Equal(t, stop, err)
+	require.Equal(t, []string{"a", "b"}, titles)
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSelectCountExists(t *testing.T) {
+	sess, mock := newMockSession(t, dialect.MySQL)
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM `suggestions` WHERE (`state` = 'open')")).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+	count, err := sess.Select("id", "title").From("suggestions").Where(Eq("state", "open")).OrderBy("id").Count()
+	require.NoError(t, err)
+	require.Equal(t, int64(3), count)
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM (SELECT DISTINCT `title` FROM `suggestions` LIMIT 10) AS `t`")).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+	count, err = sess.Select("title").Distinct().From("suggestions").Limit(10).Count()
+	require.NoError(t, err)
+	require.Equal(t, int64(2), count)
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT 1 FROM `suggestions` WHERE (`id` = 1) LIMIT 1")).
+		WillReturnRows(sqlmock.NewRows([]string{"1"}).AddRow(1))
+	ok, err := sess.Select("*").From("suggestions").Where(Eq("id", 1)).Exists()
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT 1 FROM (SELECT * FROM suggestions) AS `t` LIMIT 1")).
+		WillReturnRows(sqlmock.NewRows([]string{"1"}))
+	ok, err = sess.SelectBySql("SELECT * FROM suggestions").Exists()
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	// the aliases used by HAVING are kept
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT 1 FROM (SELECT `user_id`, COUNT(*) AS n FROM `suggestions` GROUP BY `user_id` HAVING (n > 1)) AS `t` LIMIT 1")).
+		WillReturnRows(sqlmock.NewRows([]string{"1"}).AddRow(1))
+	ok, err = sess.Select("user_id", "COUNT(*) AS n").From("suggestions").GroupBy("user_id").Having("n > 1").Exists()
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	mock.ExpectQuery("SELECT `id` FROM `suggestions`").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	var id int64
+	err = sess.Select("id").From("suggestions").LoadOne(&id)
+	require.True(t, errors.Is(err, ErrNotFound))
+	require.True(t, errors.Is(err, sql.ErrNoRows))
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSelectCountExistsSubquery(t *testing.T) {
+	sess, mock := newMockSession(t, dialect.MySQL)
+	replica, replicaMock, err := sqlmock.New()
+	require.NoError(t, err)
+	sess.Replicas = NewReplicaPool(replica)
+
+	// the comments and the timeout are on the outer statement
+	replicaMock.ExpectQuery(regexp.QuoteMeta("/* list */\nSELECT /*+ MAX_EXECUTION_TIME(1500) */ COUNT(*) FROM (SELECT DISTINCT `title` FROM `suggestions` LIMIT 10) AS `t` /*action='list'*/")).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+	count, err := sess.Select("title").Distinct().From("suggestions").Limit(10).
+		Comment("list").CommentTag("action", "list").Timeout(1500 * time.Millisecond).Count()
+	require.NoError(t, err)
+	require.Equal(t, int64(2), count)
+	require.NoError(t, replicaMock.ExpectationsWereMet())
+
+	// and they are routed like the statement
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM (SELECT DISTINCT `title` FROM `suggestions` LIMIT 10) AS `t`")).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+	_, err = sess.Select("title").Distinct().From("suggestions").Limit(10).OnPrimary().Count()
+	require.NoError(t, err)
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT 1 FROM (SELECT * FROM suggestions) AS `t` LIMIT 1")).
+		WillReturnRows(sqlmock.NewRows([]string{"1"}))
+	_, err = sess.SelectBySql("SELECT * FROM suggestions").Exists()
+	require.NoError(t, err)
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT 1 FROM (SELECT `id` FROM `suggestions` LIMIT 10 FOR UPDATE) AS `t` LIMIT 1")).
+		WillReturnRows(sqlmock.NewRows([]string{"1"}))
+	_, err = sess.Select("id").From("suggestions").Limit(10).ForUpdate().Exists()
+	require.NoError(t, err)
+	require.NoError(t, mock.ExpectationsWereMet())
+
+	replicaMock.ExpectQuery(regexp.QuoteMeta("SELECT 1 FROM (SELECT * FROM suggestions) AS `t` LIMIT 1")).
+		WillReturnRows(sqlmock.NewRows([]string{"1"}))
+	_, err = sess.SelectBySql("SELECT * FROM suggestions").OnReplica().Exists()
+	require.NoError(t, err)
+	require.NoError(t, replicaMock.ExpectationsWereMet())
+}
+
+func TestReturnContext(t *testing.T) {
+	sess, mock := newMockSession(t, dialect.MySQL)
+
+	mock.ExpectQuery("SELECT `title` FROM `suggestions`").
+		WillReturnRows(sqlmock.NewRows([]string{"title"}).AddRow("a"))
+	title, err := sess.Select("title").From("suggestions").ReturnStringContext(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, "a", title)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = sess.Select("id").From("suggestions").ReturnInt64sContext(ctx)
+	require.True(t, errors.Is(err, context.Canceled))
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,88 @@
+package dbr
+
+import (
+	"hash/fnv"
+	"reflect"
+	"sort"
+)
+
+// Sharder picks the Connection of the shard holding key,
+// or nil if no shard holds it.
+type Sharder interface {
+	ShardFor(key interface{}) *Connection
+}
+
+// WithShardKey returns a session on the shard holding key, which is picked by
+// Connection.Sharder, and has the settings of sess. The statements built by
+// it run on the shard. It returns ErrNoShard if no shard holds key.
+func (sess *Session) WithShardKey(key interface{}) (*Session, error) {
+	if sess.Sharder == nil {
+		return nil, ErrNoShard
+	}
+	conn := sess.Sharder.ShardFor(key)
+	if conn == nil {
+		return nil, ErrNoShard
+	}
+	return sess.clone(conn), nil
+}
+
+// ModuloSharder picks the shard by key modulo the number of shards.
+// The keys are integers, or strings and []byte hashed by FNV-1a.
+type ModuloSharder []*Connection
+
+// ShardFor implements Sharder.
+func (s ModuloSharder) ShardFor(key interface{}) *Connection {
+	if len(s) == 0 {
+		return nil
+	}
+	var h uint64
+	switch key := key.(type) {
+	case string:
+		f := fnv.New64a()
+		f.Write([]byte(key))
+		h = f.Sum64()
+	case []byte:
+		f := fnv.New64a()
+		f.Write(key)
+		h = f.Sum64()
+	default:
+		n, ok := shardInt(key)
+		if !ok {
+			return nil
+		}
+		h = uint64(n)
+	}
+	return s[h%uint64(len(s))]
+}
+
+// RangeSharder picks the shard by the range of integer key.
+// Bounds are sorted, and Shards has one more connection than Bounds:
+// Shards[i] holds the keys below Bounds[i], and the last one holds the rest.
+type RangeSharder struct {
+	Bounds []int64
+	Shards []*Connection
+}
+
+// ShardFor implements Sharder.
+func (s *RangeSharder) ShardFor(key interface{}) *Connection {
+	n, ok := shardInt(key)
+	if !ok || len(s.Shards) != len(s.Bounds)+1 {
+		return nil
+	}
+	i := sort.Search(len(s.Bounds), func(i int) bool {
+		return n < s.Bounds[i]
+	})
+	return s.Shards[i]
+}
+
+// shardInt converts integer key to int64.
+func shardInt(key interface{}) (int64, bool) {
+	v := reflect.ValueOf(key)
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int(), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return int64(v.Uint()), true
+	}
+	return 0, false
+}
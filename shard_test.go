@@ -0,0 +1,53 @@
+package dbr
+
+import (
+	"testing"
+	"time"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/jiyeyuran/dbr/v2/dialect"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSharder(t *testing.T) {
+	db0, mock0, err := sqlmock.New()
+	require.NoError(t, err)
+	db1, mock1, err := sqlmock.New()
+	require.NoError(t, err)
+	shards := []*Connection{
+		{DB: db0, EventReceiver: &NullEventReceiver{}, Dialect: dialect.MySQL},
+		{DB: db1, EventReceiver: &NullEventReceiver{}, Dialect: dialect.PostgreSQL},
+	}
+
+	conn := &Connection{
+		EventReceiver: &NullEventReceiver{},
+		Sharder:       ModuloSharder(shards),
+	}
+	sess := conn.NewSession(nil)
+	sess.Timeout = time.Minute
+
+	mock0.ExpectExec("DELETE FROM `users` WHERE \\(id = 4\\)").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock1.ExpectExec(`DELETE FROM "users" WHERE \(id = 7\)`).WillReturnResult(sqlmock.NewResult(0, 1))
+	for _, id := range []int64{4, 7} {
+		shard, err := sess.WithShardKey(id)
+		require.NoError(t, err)
+		require.Equal(t, time.Minute, shard.Timeout)
+		_, err = shard.DeleteFrom("users").Where("id = ?", id).Exec()
+		require.NoError(t, err)
+	}
+	require.NoError(t, mock0.ExpectationsWereMet())
+	require.NoError(t, mock1.ExpectationsWereMet())
+
+	_, err = sess.WithShardKey(1.5)
+	require.Equal(t, ErrNoShard, err)
+
+	ranges := &RangeSharder{Bounds: []int64{100}, Shards: shards}
+	require.Equal(t, shards[0], ranges.ShardFor(99))
+	require.Equal(t, shards[1], ranges.ShardFor(uint8(100)))
+	require.Equal(t, shards[1], ranges.ShardFor(1000))
+	require.Nil(t, ranges.ShardFor("a"))
+	require.Equal(t, ModuloSharder(shards).ShardFor("tenant"), ModuloSharder(shards).ShardFor([]byte("tenant")))
+
+	_, err = shards[0].NewSession(nil).WithShardKey(1)
+	require.Equal(t, ErrNoShard, err)
+}
@@ -0,0 +1,76 @@
+package dbr
+
+import (
+	"context"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// CommentTags is a set of key-value tags appended to a query as a comment
+// in the format of sqlcommenter, like /*action='list',route='%2Fusers'*/,
+// so that slow queries can be attributed to the endpoints in
+// pg_stat_statements or performance_schema.
+type CommentTags map[string]string
+
+// Add adds a tag of key and value.
+func (tags CommentTags) Add(key, value string) CommentTags {
+	if tags == nil {
+		tags = make(CommentTags)
+	}
+	tags[key] = value
+	return tags
+}
+
+// String returns the comment of tags, or an empty string if there is no tag.
+// The keys are sorted, and the keys and values are URL-encoded,
+// with a space as %20.
+func (tags CommentTags) String() string {
+	if len(tags) == 0 {
+		return ""
+	}
+	keys := make([]string, 0, len(tags))
+	for key := range tags {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, len(keys))
+	for i, key := range keys {
+		pairs[i] = escapeTag(key) + "='" + escapeTag(tags[key]) + "'"
+	}
+	return openingSQLComment + strings.Join(pairs, ",") + closingSQLComment
+}
+
+// escapeTag URL-encodes s like encodeURIComponent, which sqlcommenter uses,
+// and also ' and *.
+func escapeTag(s string) string {
+	// QueryEscape encodes + as %2B, so the rest are spaces
+	return strings.ReplaceAll(url.QueryEscape(s), "+", "%20")
+}
+
+// commentTagger is implemented by Session and Tx for Session.CommentTags,
+// and the statements with CommentTag.
+type commentTagger interface {
+	commentTags(ctx context.Context) map[string]string
+}
+
+// appendCommentTags appends the tags of runner and builder to query,
+// and the tags of builder are preferred. It also reports whether the tags of
+// runner are appended, which vary by request, so the query is not cached.
+func appendCommentTags(ctx context.Context, runner runner, builder Builder, query string) (string, bool) {
+	var tags CommentTags
+	varying := false
+	for _, v := range []interface{}{runner, builder} {
+		if tagger, ok := v.(commentTagger); ok {
+			for key, value := range tagger.commentTags(ctx) {
+				tags = tags.Add(key, value)
+				varying = varying || v == runner
+			}
+		}
+	}
+	if len(tags) == 0 {
+		return query, false
+	}
+	return query + space + tags.String(), varying
+}
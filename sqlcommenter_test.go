@@ -0,0 +1,54 @@
+package dbr
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/jiyeyuran/dbr/v2/dialect"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCommentTags(t *testing.T) {
+	sess, mock := newMockSession(t, dialect.MySQL)
+	type routeKey struct{}
+	sess.CommentTags = func(ctx context.Context) map[string]string {
+		route, _ := ctx.Value(routeKey{}).(string)
+		return map[string]string{
+			"route":       route,
+			"traceparent": "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01",
+		}
+	}
+	ctx := context.WithValue(context.Background(), routeKey{}, "/users/{id}")
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT `id` FROM `users` /*action='get',route='%2Fusers%2F%7Bid%7D',traceparent='00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01'*/")).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	var id int64
+	err := sess.Select("id").From("users").CommentTag("action", "get").LoadOneContext(ctx, &id)
+	require.NoError(t, err)
+
+	sess.CommentTags = nil
+	mock.ExpectExec(regexp.QuoteMeta("/* audit */\nDELETE FROM `users` /*app='it%27s%2A%2F'*/")).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	_, err = sess.DeleteFrom("users").Comment("audit").CommentTag("app", "it's*/").Exec()
+	require.NoError(t, err)
+
+	// the tags of the session vary by request, and skip the cache
+	sess.EnableStmtCache(10)
+	sess.CommentTags = func(ctx context.Context) map[string]string {
+		route, _ := ctx.Value(routeKey{}).(string)
+		return map[string]string{"route": route}
+	}
+	for _, route := range []string{"/users", "/users/me"} {
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT `id` FROM `users` /*route='" + escapeTag(route) + "'*/")).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+		err = sess.Select("id").From("users").
+			LoadOneContext(context.WithValue(context.Background(), routeKey{}, route), &id)
+		require.NoError(t, err)
+	}
+
+	require.Equal(t, "/*app='my%20app%2Bdbr'*/", CommentTags{"app": "my app+dbr"}.String())
+	require.Equal(t, "", CommentTags(nil).String())
+	require.NoError(t, mock.ExpectationsWereMet())
+}
@@ -9,17 +9,10 @@ import (
 )
 
 func TestSQLMock(t *testing.T) {
-	db, mock, err := sqlmock.New()
-	require.NoError(t, err)
-
-	conn := &Connection{
-		DB:            db,
-		EventReceiver: &NullEventReceiver{},
-		Dialect:       dialect.MySQL,
-	}
-	sess := conn.NewSession(nil)
+	sess, mock := newMockSession(t, dialect.MySQL)
+	conn := sess.Connection
 
-	mock.ExpectQuery("SELECT id FROM suggestions").
+	mock.ExpectQuery("SELECT `id` FROM `suggestions`").
 		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1).AddRow(2))
 	id, err := sess.Select("id").From("suggestions").ReturnInt64s()
 	require.NoError(t, err)
@@ -30,3 +23,38 @@ func TestSQLMock(t *testing.T) {
 
 	require.NoError(t, mock.ExpectationsWereMet())
 }
+
+// newMockSession returns a session of d on a sqlmock database.
+func newMockSession(t *testing.T, d Dialect) (*Session, sqlmock.Sqlmock) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+
+	conn := &Connection{
+		DB:            db,
+		EventReceiver: &NullEventReceiver{},
+		Dialect:       d,
+	}
+	return conn.NewSession(nil), mock
+}
+
+// newInitSession is like newMockSession,
+// but its Connection can open the pools of Session.Init.
+func newInitSession(t *testing.T, d Dialect) (*Session, sqlmock.Sqlmock) {
+	dsn := t.Name()
+	db, mock, err := sqlmock.NewWithDSN(dsn)
+	require.NoError(t, err)
+	connector, err := connectorOf(db.Driver(), dsn)
+	require.NoError(t, err)
+
+	conn := &Connection{
+		DB:            db,
+		EventReceiver: &NullEventReceiver{},
+		Dialect:       d,
+		connector:     connector,
+	}
+	// releases dsn, which is closed with the last connection
+	t.Cleanup(func() {
+		conn.Close()
+	})
+	return conn.NewSession(nil), mock
+}
@@ -0,0 +1,60 @@
+package dbr
+
+import (
+	"context"
+	"database/sql"
+	"strconv"
+	"time"
+)
+
+// ReportStats sends the stats of the connection pools of the primary and the
+// replicas to EventReceiver by EventKv, as the event "dbr.pool.stats" with the
+// kvs "max_open", "open", "in_use" and "idle", which are gauges, and
+// "wait_count_total" and "wait_duration_total" in nanoseconds, which are
+// counters since the pool is opened.
+// The stats of the replicas have the kv "replica" of their index.
+func (conn *Connection) ReportStats() {
+	if conn.DB != nil {
+		reportStats(conn.EventReceiver, conn.DB.Stats(), nil)
+	}
+	if conn.Replicas == nil {
+		return
+	}
+	for i, r := range conn.Replicas.replicas {
+		reportStats(conn.EventReceiver, r.Stats(), kvs{
+			"replica": strconv.Itoa(i),
+		})
+	}
+}
+
+// ReportStatsEvery calls ReportStats every interval in a goroutine
+// until ctx is done.
+func (conn *Connection) ReportStatsEvery(ctx context.Context, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				conn.ReportStats()
+			}
+		}
+	}()
+}
+
+func reportStats(log EventReceiver, stats sql.DBStats, labels kvs) {
+	m := kvs{
+		"max_open":            strconv.Itoa(stats.MaxOpenConnections),
+		"open":                strconv.Itoa(stats.OpenConnections),
+		"in_use":              strconv.Itoa(stats.InUse),
+		"idle":                strconv.Itoa(stats.Idle),
+		"wait_count_total":    strconv.FormatInt(stats.WaitCount, 10),
+		"wait_duration_total": strconv.FormatInt(stats.WaitDuration.Nanoseconds(), 10),
+	}
+	for k, v := range labels {
+		m[k] = v
+	}
+	log.EventKv("dbr.pool.stats", m)
+}
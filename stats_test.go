@@ -0,0 +1,65 @@
+package dbr
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/jiyeyuran/dbr/v2/dialect"
+	"github.com/stretchr/testify/require"
+)
+
+type testStatsReceiver struct {
+	NullEventReceiver
+	mu    sync.Mutex
+	stats map[string]map[string]string
+}
+
+func (r *testStatsReceiver) EventKv(eventName string, kvs map[string]string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if eventName == "dbr.pool.stats" {
+		r.stats[kvs["replica"]] = kvs
+	}
+}
+
+func (r *testStatsReceiver) get(replica string) map[string]string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.stats[replica]
+}
+
+func TestReportStats(t *testing.T) {
+	db, _, err := sqlmock.New()
+	require.NoError(t, err)
+	replica, _, err := sqlmock.New()
+	require.NoError(t, err)
+	db.SetMaxOpenConns(8)
+
+	log := &testStatsReceiver{stats: make(map[string]map[string]string)}
+	conn := &Connection{
+		DB:            db,
+		EventReceiver: log,
+		Dialect:       dialect.MySQL,
+		Replicas:      NewReplicaPool(replica),
+	}
+	conn.ReportStats()
+	for _, name := range []string{"max_open", "open", "in_use", "idle", "wait_count_total", "wait_duration_total"} {
+		require.Contains(t, log.get(""), name)
+		require.Contains(t, log.get("0"), name)
+	}
+	require.Equal(t, "8", log.get("")["max_open"])
+	require.Equal(t, "0", log.get("")["wait_count_total"])
+
+	log.mu.Lock()
+	log.stats = make(map[string]map[string]string)
+	log.mu.Unlock()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	conn.ReportStatsEvery(ctx, time.Millisecond)
+	require.Eventually(t, func() bool {
+		return log.get("") != nil
+	}, time.Second, time.Millisecond)
+}
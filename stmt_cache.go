@@ -0,0 +1,225 @@
+package dbr
+
+import (
+	"container/list"
+	"context"
+	"database/sql"
+	"sync"
+)
+
+// EnableStmtCache caches up to size prepared statements of the queries built
+// by the connection and its sessions, which are reused for the same query.
+// The least recently used statements are closed if the cache is full, and
+// all statements are closed by Close. size <= 0 disables the cache.
+//
+// With the cache, the values are not interpolated but sent to the driver,
+// so that the same builder makes the same query for different values.
+// It should be called before the connection is used.
+func (conn *Connection) EnableStmtCache(size int) {
+	if conn.stmts != nil {
+		conn.stmts.close()
+		conn.stmts = nil
+	}
+	if size > 0 {
+		conn.stmts = newStmtCache(size)
+	}
+}
+
+// Close closes the cached prepared statements, the replicas, the pools of
+// Session.Init and the database.
+func (conn *Connection) Close() error {
+	if conn.stmts != nil {
+		conn.stmts.close()
+	}
+	conn.closeInitDBs()
+	if conn.Replicas != nil {
+		conn.Replicas.close()
+	}
+	return conn.DB.Close()
+}
+
+func (conn *Connection) stmtCache() *stmtCache {
+	return conn.stmts
+}
+
+// stmtCacher is implemented by Connection, Session and Tx for EnableStmtCache.
+type stmtCacher interface {
+	stmtCache() *stmtCache
+}
+
+// stmtCacheOf returns the statement cache of runner, or nil if it is disabled.
+func stmtCacheOf(runner interface{}) *stmtCache {
+	if c, ok := runner.(stmtCacher); ok {
+		return c.stmtCache()
+	}
+	return nil
+}
+
+// stmtCache is a LRU cache of prepared statements by query.
+type stmtCache struct {
+	size int
+
+	mu     sync.Mutex
+	lru    *list.List // of *cachedStmt, most recently used first
+	stmts  map[string]*list.Element
+	closed bool
+}
+
+// cachedStmt is closed when it is evicted and no longer used.
+type cachedStmt struct {
+	*sql.Stmt
+	query   string
+	refs    int
+	evicted bool
+}
+
+func newStmtCache(size int) *stmtCache {
+	return &stmtCache{
+		size:  size,
+		lru:   list.New(),
+		stmts: make(map[string]*list.Element),
+	}
+}
+
+// get returns the prepared statement of query, which must be released
+// after it is used.
+func (c *stmtCache) get(ctx context.Context, db *sql.DB, query string) (*cachedStmt, error) {
+	c.mu.Lock()
+	if elem, ok := c.stmts[query]; ok && !c.closed {
+		c.lru.MoveToFront(elem)
+		stmt := elem.Value.(*cachedStmt)
+		stmt.refs++
+		c.mu.Unlock()
+		return stmt, nil
+	}
+	c.mu.Unlock()
+
+	prepared, err := db.PrepareContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	stmt := &cachedStmt{Stmt: prepared, query: query, refs: 1}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.closed {
+		// used once and closed by release
+		stmt.evicted = true
+		return stmt, nil
+	}
+	if elem, ok := c.stmts[query]; ok {
+		// prepared concurrently
+		prepared.Close()
+		c.lru.MoveToFront(elem)
+		stmt = elem.Value.(*cachedStmt)
+		stmt.refs++
+		return stmt, nil
+	}
+	c.stmts[query] = c.lru.PushFront(stmt)
+	for c.lru.Len() > c.size {
+		c.evict(c.lru.Back())
+	}
+	return stmt, nil
+}
+
+// release closes stmt if it is evicted and no longer used.
+func (c *stmtCache) release(stmt *cachedStmt) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	stmt.refs--
+	if stmt.evicted {
+		c.closeStmt(stmt)
+	}
+}
+
+// close closes all statements, or after they are released if in use.
+func (c *stmtCache) close() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.closed = true
+	for c.lru.Len() > 0 {
+		c.evict(c.lru.Back())
+	}
+}
+
+func (c *stmtCache) evict(elem *list.Element) {
+	stmt := c.lru.Remove(elem).(*cachedStmt)
+	delete(c.stmts, stmt.query)
+	stmt.evicted = true
+	c.closeStmt(stmt)
+}
+
+func (c *stmtCache) closeStmt(stmt *cachedStmt) {
+	if stmt.refs == 0 {
+		stmt.Close()
+	}
+}
+
+// execStmt executes query with the cached prepared statement
+// if the statement cache of runner is enabled.
+func execStmt(ctx context.Context, runner runner, query string, value []interface{}) (sql.Result, error) {
+	stmt, release, err := prepareStmt(ctx, runner, query)
+	if err != nil {
+		return nil, err
+	}
+	if stmt == nil {
+		return runner.ExecContext(ctx, query, value...)
+	}
+	defer release()
+	return stmt.ExecContext(ctx, value...)
+}
+
+// queryStmt is like execStmt, but returns rows.
+func queryStmt(ctx context.Context, runner runner, query string, value []interface{}) (*sql.Rows, error) {
+	stmt, release, err := prepareStmt(ctx, runner, query)
+	if err != nil {
+		return nil, err
+	}
+	if stmt == nil {
+		return runner.QueryContext(ctx, query, value...)
+	}
+	// the statement can be closed before rows,
+	// which are closed by database/sql later.
+	defer release()
+	return stmt.QueryContext(ctx, value...)
+}
+
+type skipStmtCacheKey struct{}
+
+// skipStmtCache returns ctx in which the query is not cached,
+// since it is unique to the request.
+func skipStmtCache(ctx context.Context) context.Context {
+	return context.WithValue(ctx, skipStmtCacheKey{}, true)
+}
+
+// prepareStmt returns the cached prepared statement of query in runner,
+// or nil if the cache is disabled.
+func prepareStmt(ctx context.Context, runner runner, query string) (*sql.Stmt, func(), error) {
+	cache := stmtCacheOf(runner)
+	if cache == nil || ctx.Value(skipStmtCacheKey{}) != nil {
+		return nil, nil, nil
+	}
+	var db *sql.DB
+	var tx *sql.Tx
+	switch runner := runner.(type) {
+	case *Session:
+		db = runner.DB
+	case *Tx:
+		db, tx = runner.db, runner.Tx
+	}
+	if db == nil {
+		return nil, nil, nil
+	}
+	cached, err := cache.get(ctx, db, query)
+	if err != nil {
+		return nil, nil, err
+	}
+	release := func() {
+		cache.release(cached)
+	}
+	if tx != nil {
+		// closed with the transaction
+		return tx.StmtContext(ctx, cached.Stmt), release, nil
+	}
+	return cached.Stmt, release, nil
+}
@@ -0,0 +1,51 @@
+package dbr
+
+import (
+	"regexp"
+	"testing"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/jiyeyuran/dbr/v2/dialect"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStmtCache(t *testing.T) {
+	sess, mock := newMockSession(t, dialect.PostgreSQL)
+	sess.EnableStmtCache(1)
+
+	update := mock.ExpectPrepare(regexp.QuoteMeta(`UPDATE "suggestions" SET "title" = $1 WHERE (id = $2)`))
+	update.ExpectExec().WithArgs("a", int64(1)).WillReturnResult(sqlmock.NewResult(0, 1))
+	update.ExpectExec().WithArgs("b", int64(2)).WillReturnResult(sqlmock.NewResult(0, 1))
+	update.WillBeClosed()
+	for i, title := range []string{"a", "b"} {
+		_, err := sess.Update("suggestions").Set("title", title).Where("id = ?", i+1).Exec()
+		require.NoError(t, err)
+	}
+
+	// IN is expanded to placeholders, and evicts the update
+	query := mock.ExpectPrepare(regexp.QuoteMeta(`SELECT "id" FROM "suggestions" WHERE ("id" IN ($1,$2))`))
+	query.ExpectQuery().WithArgs(int64(1), int64(2)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1).AddRow(2))
+	query.WillBeClosed()
+	var ids []int64
+	_, err := sess.Select("id").From("suggestions").Where(Eq("id", []int64{1, 2})).Load(&ids)
+	require.NoError(t, err)
+	require.Equal(t, []int64{1, 2}, ids)
+
+	// the cached statement is used in transactions
+	mock.ExpectBegin()
+	query.ExpectQuery().WithArgs(int64(3), int64(4)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(3))
+	mock.ExpectCommit()
+	tx, err := sess.Begin()
+	require.NoError(t, err)
+	ids = nil
+	_, err = tx.Select("id").From("suggestions").Where(Eq("id", []int64{3, 4})).Load(&ids)
+	require.NoError(t, err)
+	require.Equal(t, []int64{3}, ids)
+	require.NoError(t, tx.Commit())
+
+	mock.ExpectClose()
+	require.NoError(t, sess.Close())
+	require.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,25 @@
+package dbr
+
+import (
+	"strconv"
+
+	"github.com/jiyeyuran/dbr/v2/dialect"
+)
+
+// tableSample builds `TABLESAMPLE method (percent)`.
+func tableSample(method string, percent float64) Builder {
+	return BuildFunc(func(d Dialect, buf Buffer) error {
+		if !dialect.CapabilitiesOf(d).SupportsTableSample {
+			return errDialectNotSupported("TABLESAMPLE")
+		}
+		buf.WriteString(" TABLESAMPLE ")
+		buf.WriteString(method)
+		buf.WriteString(" (")
+		buf.WriteString(strconv.FormatFloat(percent, 'f', -1, 64))
+		if d == dialect.MSSQL {
+			buf.WriteString(" PERCENT")
+		}
+		buf.WriteString(")")
+		return nil
+	})
+}
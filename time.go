@@ -0,0 +1,172 @@
+package dbr
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// TimeOptions controls time.Time of a session, which applies to the written
+// times in interpolation and Record, and the loaded times in Load,
+// including *time.Time and NullTime.
+//
+// Layouts parses the time strings loaded into them in order, like
+// "2006-01-02T15:04:05Z07:00" for the TEXT columns in sqlite, so that the
+// sessions of different databases have their own formats. The strings are
+// parsed like NullTime.Scan if no layout matches.
+type TimeOptions struct {
+	// Location converts the times to it if it is not nil,
+	// like the location of DATETIME columns in MySQL.
+	Location *time.Location
+	// Truncate truncates the times to a multiple of it if it is positive,
+	// like time.Microsecond for the precision of most databases.
+	// The monotonic clock readings are always stripped.
+	Truncate time.Duration
+	// ZeroAsNull writes zero times as NULL in the values of INSERT and
+	// UPDATE SET, but not in the conditions like WHERE.
+	ZeroAsNull bool
+	// Layouts are the formats of the loaded time strings.
+	Layouts []string
+}
+
+func (o *TimeOptions) isZero() bool {
+	return o.Location == nil && o.Truncate == 0 && !o.ZeroAsNull && len(o.Layouts) == 0
+}
+
+func (o *TimeOptions) apply(t time.Time) time.Time {
+	if o.Location != nil {
+		t = t.In(o.Location)
+	}
+	if o.Truncate > 0 {
+		return t.Truncate(o.Truncate)
+	}
+	return t.Round(0)
+}
+
+// loaded applies the options to the times loaded into ptr.
+func (o *TimeOptions) loaded(ptr []interface{}) {
+	for _, p := range ptr {
+		switch p := p.(type) {
+		case *time.Time:
+			*p = o.apply(*p)
+		case **time.Time:
+			if *p != nil {
+				**p = o.apply(**p)
+			}
+		case *NullTime:
+			if p.Valid {
+				p.Time = o.apply(p.Time)
+			}
+		}
+	}
+}
+
+// scanRow scans the current row of rows into ptr with o, which can be nil.
+func scanRow(rows *sql.Rows, ptr []interface{}, o *TimeOptions) error {
+	if o == nil {
+		return rows.Scan(ptr...)
+	}
+	dest := ptr
+	if len(o.Layouts) > 0 {
+		dest = make([]interface{}, len(ptr))
+		for i, p := range ptr {
+			switch p.(type) {
+			case *time.Time, **time.Time, *NullTime:
+				dest[i] = &timeScanner{ptr: p, layouts: o.Layouts}
+			default:
+				dest[i] = p
+			}
+		}
+	}
+	err := rows.Scan(dest...)
+	if err != nil {
+		return err
+	}
+	o.loaded(ptr)
+	return nil
+}
+
+// timeScanner scans the time strings of Layouts into ptr,
+// which is *time.Time, **time.Time or *NullTime.
+type timeScanner struct {
+	ptr     interface{}
+	layouts []string
+}
+
+func (s *timeScanner) Scan(value interface{}) error {
+	var n NullTime
+	if nt, ok := s.ptr.(*NullTime); ok {
+		n.Location = nt.Location
+	}
+	if b, ok := value.([]byte); ok {
+		value = string(b)
+	}
+	if str, ok := value.(string); ok {
+		t, err := s.parse(str, n.location())
+		if err != nil {
+			return err
+		}
+		n.Time, n.Valid = t, true
+	} else if err := n.Scan(value); err != nil {
+		return err
+	}
+
+	switch p := s.ptr.(type) {
+	case *time.Time:
+		if !n.Valid {
+			return fmt.Errorf("dbr: cannot scan NULL into %T", p)
+		}
+		*p = n.Time
+	case **time.Time:
+		*p = n.Ptr()
+	case *NullTime:
+		p.Time, p.Valid = n.Time, n.Valid
+	}
+	return nil
+}
+
+// parse parses str with the layouts, or like NullTime.Scan if none matches.
+func (s *timeScanner) parse(str string, loc *time.Location) (time.Time, error) {
+	for _, layout := range s.layouts {
+		if t, err := time.ParseInLocation(layout, str, loc); err == nil {
+			return t, nil
+		}
+	}
+	return parseDateTime(str, loc)
+}
+
+// written returns the value written by INSERT or UPDATE SET,
+// which is nil for a zero time with ZeroAsNull.
+func (o *TimeOptions) written(value interface{}) interface{} {
+	if o == nil || !o.ZeroAsNull {
+		return value
+	}
+	var zero bool
+	switch v := value.(type) {
+	case time.Time:
+		zero = v.IsZero()
+	case *time.Time:
+		zero = v != nil && v.IsZero()
+	case NullTime:
+		zero = v.Valid && v.Time.IsZero()
+	case *NullTime:
+		zero = v != nil && v.Valid && v.Time.IsZero()
+	}
+	if zero {
+		return nil
+	}
+	return value
+}
+
+// timeOptioner is implemented by Session and Tx for Session.Time.
+type timeOptioner interface {
+	timeOptions() *TimeOptions
+}
+
+// timeOptionsOf returns the TimeOptions of runner, or nil if it is not set.
+func timeOptionsOf(runner interface{}) *TimeOptions {
+	if o, ok := runner.(timeOptioner); ok {
+		return o.timeOptions()
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+package dbr
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/jiyeyuran/dbr/v2/dialect"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSessionTimeOptions(t *testing.T) {
+	sess, mock := newMockSession(t, dialect.MySQL)
+	loc := time.FixedZone("UTC+8", 8*60*60)
+	sess.Time = TimeOptions{
+		Location:   loc,
+		Truncate:   time.Second,
+		ZeroAsNull: true,
+	}
+
+	type event struct {
+		ID        int64
+		StartedAt time.Time
+		EndedAt   *time.Time
+		DeletedAt NullTime
+	}
+	now := time.Date(2020, 1, 2, 3, 4, 5, 600000000, time.UTC)
+
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `events` (`id`,`started_at`,`ended_at`,`deleted_at`) VALUES (1,'2020-01-02 11:04:05.000000',NULL,NULL)")).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	_, err := sess.InsertInto("events").Columns("id", "started_at", "ended_at", "deleted_at").
+		Record(&event{ID: 1, StartedAt: now, EndedAt: &time.Time{}}).Exec()
+	require.NoError(t, err)
+
+	mock.ExpectQuery("SELECT `id`, `started_at`, `ended_at`, `deleted_at` FROM `events`").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "started_at", "ended_at", "deleted_at"}).
+			AddRow(1, now, now, now))
+	var e event
+	require.NoError(t, sess.Select("id", "started_at", "ended_at", "deleted_at").From("events").LoadOne(&e))
+	want := now.Truncate(time.Second).In(loc)
+	for _, got := range []time.Time{e.StartedAt, *e.EndedAt, e.DeletedAt.Time} {
+		require.Equal(t, want, got)
+		require.Equal(t, loc, got.Location())
+	}
+
+	// zero times in conditions are not NULL
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE `events` SET `ended_at` = NULL WHERE (`ended_at` = '0001-01-01 08:00:00.000000')")).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	_, err = sess.Update("events").Set("ended_at", time.Time{}).Where(Eq("ended_at", time.Time{})).Exec()
+	require.NoError(t, err)
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTimeLayouts(t *testing.T) {
+	sess, mock := newMockSession(t, dialect.SQLite3)
+	sess.Time = TimeOptions{Layouts: []string{time.RFC3339Nano}}
+
+	type event struct {
+		StartedAt time.Time
+		EndedAt   *time.Time
+		DeletedAt NullTime
+	}
+	want := time.Date(2020, 1, 2, 3, 4, 5, 0, time.FixedZone("", 8*60*60))
+	mock.ExpectQuery(`SELECT "started_at", "ended_at", "deleted_at" FROM "events"`).
+		WillReturnRows(sqlmock.NewRows([]string{"started_at", "ended_at", "deleted_at"}).
+			AddRow("2020-01-02T03:04:05+08:00", nil, []byte("2020-01-02 03:04:05")))
+	var e event
+	require.NoError(t, sess.Select("started_at", "ended_at", "deleted_at").From("events").LoadOne(&e))
+	require.True(t, want.Equal(e.StartedAt))
+	require.Nil(t, e.EndedAt)
+	// the strings not matching the layouts are parsed like NullTime.Scan
+	require.Equal(t, NullTimeFrom(time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)), e.DeletedAt)
+
+	mock.ExpectQuery(`SELECT "started_at" FROM "events"`).
+		WillReturnRows(sqlmock.NewRows([]string{"started_at"}).AddRow("2020-01-02T03:04:05+08:00"))
+	var times []time.Time
+	_, err := sess.Select("started_at").From("events").Load(&times)
+	require.NoError(t, err)
+	require.Len(t, times, 1)
+	require.True(t, want.Equal(times[0]))
+
+	mock.ExpectQuery(`SELECT "started_at" FROM "events"`).
+		WillReturnRows(sqlmock.NewRows([]string{"started_at"}).AddRow("yesterday"))
+	_, err = sess.Select("started_at").From("events").Load(&times)
+	require.Error(t, err)
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
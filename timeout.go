@@ -0,0 +1,87 @@
+package dbr
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/jiyeyuran/dbr/v2/dialect"
+)
+
+// statementTimeouter is implemented by the statements with Timeout.
+type statementTimeouter interface {
+	statementTimeout() time.Duration
+}
+
+// statementTimeoutOf returns the Timeout of builder, or 0 if it is not set.
+func statementTimeoutOf(builder Builder) time.Duration {
+	if t, ok := builder.(statementTimeouter); ok {
+		return t.statementTimeout()
+	}
+	return 0
+}
+
+// withTimeout derives ctx with the shorter one of the runner timeout and
+// the statement timeout. In a PostgreSQL transaction, it also sets
+// statement_timeout for the statement, which is restored to the previous
+// value by the returned func.
+func withTimeout(ctx context.Context, runner runner, log EventReceiver, builder Builder, d Dialect) (context.Context, func(), error) {
+	timeout := runner.GetTimeout()
+	stmtTimeout := statementTimeoutOf(builder)
+	if stmtTimeout > 0 && (timeout <= 0 || stmtTimeout < timeout) {
+		timeout = stmtTimeout
+	}
+	if timeout <= 0 {
+		return ctx, func() {}, nil
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	if _, ok := runner.(*Tx); !ok || stmtTimeout <= 0 || dialect.CapabilitiesOf(d).StatementTimeoutStyle != dialect.SetStatementTimeout {
+		return ctx, cancel, nil
+	}
+
+	prev, err := currentSetting(ctx, runner, "statement_timeout")
+	if err == nil {
+		// set_config with is_local is SET LOCAL with placeholders
+		_, err = runner.ExecContext(ctx, setStatementTimeout, strconv.FormatInt(durationMillis(stmtTimeout), 10))
+	}
+	if err != nil {
+		cancel()
+		return nil, nil, log.EventErrKv("dbr.exec.timeout", err, kvs{
+			"sql": setStatementTimeout,
+		})
+	}
+	return ctx, func() {
+		cancel()
+		// the statement may be canceled with ctx; the error is ignored
+		// since the transaction is aborted anyway.
+		runner.ExecContext(context.Background(), setStatementTimeout, prev)
+	}, nil
+}
+
+const setStatementTimeout = "SELECT set_config('statement_timeout', $1, true)"
+
+// currentSetting returns the value of the postgres setting of name.
+func currentSetting(ctx context.Context, runner runner, name string) (string, error) {
+	rows, err := runner.QueryContext(ctx, "SELECT current_setting($1)", name)
+	if err != nil {
+		return "", err
+	}
+	defer rows.Close()
+	var value string
+	if rows.Next() {
+		err = rows.Scan(&value)
+		if err != nil {
+			return "", err
+		}
+	}
+	return value, rows.Err()
+}
+
+// durationMillis returns d in milliseconds, which is at least 1.
+func durationMillis(d time.Duration) int64 {
+	ms := d.Milliseconds()
+	if ms < 1 {
+		return 1
+	}
+	return ms
+}
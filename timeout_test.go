@@ -0,0 +1,59 @@
+package dbr
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/jiyeyuran/dbr/v2/dialect"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStatementTimeout(t *testing.T) {
+	sess, mock := newMockSession(t, dialect.MySQL)
+	conn := sess.Connection
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT /*+ MAX_EXECUTION_TIME(1500) */ `id` FROM `suggestions`")).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	var ids []int64
+	_, err := sess.Select("id").From("suggestions").Timeout(1500 * time.Millisecond).Load(&ids)
+	require.NoError(t, err)
+	require.Equal(t, []int64{1}, ids)
+
+	conn.Dialect = dialect.PostgreSQL
+	sess = conn.NewSession(nil)
+	mock.ExpectBegin()
+	// the previous value set by the user is restored
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT current_setting($1)")).
+		WithArgs("statement_timeout").
+		WillReturnRows(sqlmock.NewRows([]string{"current_setting"}).AddRow("30s"))
+	mock.ExpectExec(regexp.QuoteMeta("SELECT set_config('statement_timeout', $1, true)")).
+		WithArgs("2000").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "suggestions" SET "title" = 'a'`)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta("SELECT set_config('statement_timeout', $1, true)")).
+		WithArgs("30s").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	tx, err := sess.Begin()
+	require.NoError(t, err)
+	_, err = tx.Update("suggestions").Set("title", "a").Timeout(2 * time.Second).Exec()
+	require.NoError(t, err)
+	require.NoError(t, tx.Commit())
+
+	require.NoError(t, mock.ExpectationsWereMet())
+
+	// the shorter timeout cancels the statement
+	sess, mock = newMockSession(t, dialect.MySQL)
+	sess.Timeout = time.Minute
+	mock.ExpectExec("DELETE FROM suggestions").
+		WillDelayFor(time.Second).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	start := time.Now()
+	_, err = sess.DeleteFrom("suggestions").Timeout(10 * time.Millisecond).Exec()
+	require.Error(t, err)
+	require.True(t, time.Since(start) < time.Second)
+}
@@ -3,7 +3,13 @@ package dbr
 import (
 	"context"
 	"database/sql"
+	"errors"
+	"fmt"
+	"reflect"
+	"runtime/debug"
 	"time"
+
+	"github.com/jiyeyuran/dbr/v2/dialect"
 )
 
 // Tx is a transaction created by Session.
@@ -12,6 +18,69 @@ type Tx struct {
 	Dialect
 	*sql.Tx
 	Timeout time.Duration
+	// NameMapper is Connection.NameMapper of the session.
+	NameMapper func(fieldName string) string
+	// TagName is Connection.TagName of the session.
+	TagName string
+	// Strict is Session.Strict of the session.
+	Strict bool
+	// Time is Session.Time of the session.
+	Time TimeOptions
+	// CommentTags is Session.CommentTags of the session.
+	CommentTags func(ctx context.Context) map[string]string
+	// Watchdog is Session.Watchdog of the session.
+	Watchdog Watchdog
+	// Schema is Session.Schema of the session.
+	Schema string
+
+	afterCommit   []func()
+	afterRollback []func()
+
+	db    *sql.DB
+	stmts *stmtCache
+	// attempt is the attempt of InTx or RunInTx, starting at 1.
+	attempt int
+}
+
+func (tx *Tx) stmtCache() *stmtCache {
+	return tx.stmts
+}
+
+func (tx *Tx) nameMapper() func(string) string {
+	return tx.NameMapper
+}
+
+func (tx *Tx) tagName() string {
+	return tx.TagName
+}
+
+func (tx *Tx) strict() bool {
+	return tx.Strict
+}
+
+func (tx *Tx) watchdog() *Watchdog {
+	if tx.Watchdog.Threshold <= 0 {
+		return nil
+	}
+	return &tx.Watchdog
+}
+
+func (tx *Tx) commentTags(ctx context.Context) map[string]string {
+	if tx.CommentTags == nil {
+		return nil
+	}
+	return tx.CommentTags(ctx)
+}
+
+func (tx *Tx) schema() string {
+	return tx.Schema
+}
+
+func (tx *Tx) timeOptions() *TimeOptions {
+	if tx.Time.isZero() {
+		return nil
+	}
+	return &tx.Time
 }
 
 // GetTimeout returns timeout enforced in Tx.
@@ -19,9 +88,18 @@ func (tx *Tx) GetTimeout() time.Duration {
 	return tx.Timeout
 }
 
+// TxOptions holds the isolation level and the read-only flag of a transaction,
+// like &TxOptions{Isolation: sql.LevelSerializable, ReadOnly: true}.
+// The zero value or nil is the default of the driver.
+type TxOptions = sql.TxOptions
+
 // BeginTx creates a transaction with TxOptions.
-func (sess *Session) BeginTx(ctx context.Context, opts *sql.TxOptions) (*Tx, error) {
-	tx, err := sess.Connection.BeginTx(ctx, opts)
+func (sess *Session) BeginTx(ctx context.Context, opts *TxOptions) (*Tx, error) {
+	db, err := sess.pool()
+	if err != nil {
+		return nil, sess.EventErr("dbr.begin.error", err)
+	}
+	tx, err := db.BeginTx(ctx, opts)
 	if err != nil {
 		return nil, sess.EventErr("dbr.begin.error", err)
 	}
@@ -32,6 +110,15 @@ func (sess *Session) BeginTx(ctx context.Context, opts *sql.TxOptions) (*Tx, err
 		Dialect:       sess.Dialect,
 		Tx:            tx,
 		Timeout:       sess.GetTimeout(),
+		NameMapper:    sess.NameMapper,
+		TagName:       sess.TagName,
+		Strict:        sess.Strict,
+		Time:          sess.Time,
+		CommentTags:   sess.CommentTags,
+		Watchdog:      sess.Watchdog,
+		Schema:        sess.Schema,
+		db:            sess.DB,
+		stmts:         sess.stmtCache(),
 	}, nil
 }
 
@@ -44,9 +131,14 @@ func (sess *Session) Begin() (*Tx, error) {
 func (tx *Tx) Commit() error {
 	err := tx.Tx.Commit()
 	if err != nil {
+		if err != sql.ErrTxDone {
+			// the transaction is rolled back if commit fails
+			tx.runHooks(tx.afterRollback)
+		}
 		return tx.EventErr("dbr.commit.error", err)
 	}
 	tx.Event("dbr.commit")
+	tx.runHooks(tx.afterCommit)
 	return nil
 }
 
@@ -57,9 +149,33 @@ func (tx *Tx) Rollback() error {
 		return tx.EventErr("dbr.rollback", err)
 	}
 	tx.Event("dbr.rollback")
+	tx.runHooks(tx.afterRollback)
 	return nil
 }
 
+// AfterCommit registers fn to run after the transaction is committed,
+// like invalidating caches or publishing events.
+// fn does not run if the transaction is rolled back.
+func (tx *Tx) AfterCommit(fn func()) {
+	tx.afterCommit = append(tx.afterCommit, fn)
+}
+
+// AfterRollback registers fn to run after the transaction is rolled back,
+// including by RollbackUnlessCommitted and failed Commit.
+func (tx *Tx) AfterRollback(fn func()) {
+	tx.afterRollback = append(tx.afterRollback, fn)
+}
+
+// runHooks runs hooks in the order of registration,
+// and drops all hooks since the transaction is done.
+func (tx *Tx) runHooks(hooks []func()) {
+	tx.afterCommit = nil
+	tx.afterRollback = nil
+	for _, fn := range hooks {
+		fn()
+	}
+}
+
 // RollbackUnlessCommitted rollsback the transaction unless
 // it has already been committed or rolled back.
 //
@@ -75,5 +191,123 @@ func (tx *Tx) RollbackUnlessCommitted() {
 		tx.EventErr("dbr.rollback_unless_committed", err)
 	} else {
 		tx.Event("dbr.rollback")
+		tx.runHooks(tx.afterRollback)
+	}
+}
+
+// RunInTx runs fn in a transaction, which is committed if fn returns nil,
+// or rolled back otherwise. If fn panics, the transaction is rolled back
+// and the panic is returned as *PanicError.
+//
+// In cockroachdb, fn is retried up to 3 times after rolling back to a
+// savepoint with backoff if the transaction fails with a retryable error,
+// so fn should be idempotent. See InTx to retry on the other dialects.
+func (sess *Session) RunInTx(ctx context.Context, opts *TxOptions, fn func(tx *Tx) error) error {
+	return sess.runTx(ctx, opts, 1, fn)
+}
+
+// RunInTx runs fn in a transaction of sess like Session.RunInTx,
+// with fn taking SessionRunner.
+func RunInTx(ctx context.Context, sess *Session, fn func(tx SessionRunner) error) error {
+	return sess.runTx(ctx, nil, 1, func(tx *Tx) error {
+		return fn(tx)
+	})
+}
+
+// runTx runs fn in a transaction for Session.RunInTx, RunInTx and InTx,
+// where attempt is the attempt of the transaction, starting at 1.
+func (sess *Session) runTx(ctx context.Context, opts *TxOptions, attempt int, fn func(tx *Tx) error) (err error) {
+	tx, err := sess.BeginTx(ctx, opts)
+	if err != nil {
+		return err
+	}
+	defer tx.RollbackUnlessCommitted()
+	defer func() {
+		if v := recover(); v != nil {
+			err = tx.EventErr("dbr.tx.panic", &PanicError{
+				Value: v,
+				Stack: debug.Stack(),
+			})
+		}
+	}()
+	tx.attempt = attempt
+
+	if sess.Dialect != dialect.CockroachDB {
+		err := fn(tx)
+		if err != nil {
+			return err
+		}
+		return tx.Commit()
+	}
+
+	// https://www.cockroachlabs.com/docs/stable/advanced-client-side-transaction-retries
+	_, err = tx.ExecContext(ctx, "SAVEPOINT cockroach_restart")
+	if err != nil {
+		return err
 	}
+	// the hooks registered by the attempts rolled back are dropped
+	afterCommit, afterRollback := len(tx.afterCommit), len(tx.afterRollback)
+	for retry := 0; ; retry++ {
+		err := fn(tx)
+		if err == nil {
+			_, err = tx.ExecContext(ctx, "RELEASE SAVEPOINT cockroach_restart")
+			if err == nil {
+				return tx.Commit()
+			}
+		}
+		if sqlState(err) != "40001" || retry >= defaultMaxRetries || ctx.Err() != nil {
+			return err
+		}
+		tx.Event("dbr.retry")
+		_, rollbackErr := tx.ExecContext(ctx, "ROLLBACK TO SAVEPOINT cockroach_restart")
+		if rollbackErr != nil {
+			return rollbackErr
+		}
+		tx.afterCommit = tx.afterCommit[:afterCommit]
+		tx.afterRollback = tx.afterRollback[:afterRollback]
+		if !sleep(ctx, jitter(defaultBackoff<<retry)) {
+			return err
+		}
+		tx.attempt++
+	}
+}
+
+// PanicError is a panic recovered by RunInTx, Session.RunInTx and InTx.
+type PanicError struct {
+	Value interface{}
+	Stack []byte
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("dbr: panic in transaction: %v", e.Value)
+}
+
+// Unwrap returns Value if it is an error.
+func (e *PanicError) Unwrap() error {
+	err, _ := e.Value.(error)
+	return err
+}
+
+// sqlState returns SQLSTATE code of postgres errors from different drivers,
+// or "" if it is not found. The errors of lib/pq have only the Code field.
+func sqlState(err error) string {
+	var e sqlStater
+	if errors.As(err, &e) {
+		return e.SQLState()
+	}
+	for ; err != nil; err = errors.Unwrap(err) {
+		v := reflect.Indirect(reflect.ValueOf(err))
+		if v.Kind() != reflect.Struct {
+			continue
+		}
+		if code := v.FieldByName("Code"); code.Kind() == reflect.String {
+			return code.String()
+		}
+	}
+	return ""
+}
+
+// sqlStater is implemented by the errors of postgres drivers, like pgx.
+type sqlStater interface {
+	SQLState() string
 }
@@ -1,9 +1,17 @@
 package dbr
 
 import (
+	"context"
+	"database/sql"
+	"errors"
+	"regexp"
+	"strconv"
 	"testing"
+	"time"
 
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
 	"github.com/jiyeyuran/dbr/v2/dialect"
+	"github.com/lib/pq"
 	"github.com/stretchr/testify/require"
 )
 
@@ -75,3 +83,273 @@ func TestTransactionRollback(t *testing.T) {
 		require.Error(t, err)
 	}
 }
+
+func TestBeginTxOptions(t *testing.T) {
+	sess, mock := newMockSession(t, dialect.PostgreSQL)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT "id" FROM "account"`).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	mock.ExpectCommit()
+
+	tx, err := sess.BeginTx(context.Background(), &TxOptions{Isolation: sql.LevelSerializable, ReadOnly: true})
+	require.NoError(t, err)
+	defer tx.RollbackUnlessCommitted()
+
+	id, err := tx.Select("id").From("account").ReturnInt64()
+	require.NoError(t, err)
+	require.Equal(t, int64(1), id)
+	require.NoError(t, tx.Commit())
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRunInTxRetry(t *testing.T) {
+	sess, mock := newMockSession(t, dialect.CockroachDB)
+
+	mock.ExpectBegin()
+	mock.ExpectExec("SAVEPOINT cockroach_restart").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("UPDATE").WillReturnError(&pq.Error{Code: "40001"})
+	mock.ExpectExec("ROLLBACK TO SAVEPOINT cockroach_restart").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("UPDATE").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("RELEASE SAVEPOINT cockroach_restart").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	attempt := 0
+	var called []string
+	err := sess.RunInTx(context.Background(), nil, func(tx *Tx) error {
+		attempt++
+		n := strconv.Itoa(attempt)
+		tx.AfterCommit(func() { called = append(called, "commit "+n) })
+		tx.AfterRollback(func() { called = append(called, "rollback "+n) })
+		_, err := tx.Update("account").Set("balance", 1).Where(Eq("id", 1)).Exec()
+		return err
+	})
+	require.NoError(t, err)
+	require.Equal(t, 2, attempt)
+	// the hooks of the attempt rolled back to the savepoint are dropped
+	require.Equal(t, []string{"commit 2"}, called)
+	require.NoError(t, mock.ExpectationsWereMet())
+
+	// the retries are limited
+	mock.ExpectBegin()
+	mock.ExpectExec("SAVEPOINT cockroach_restart").WillReturnResult(sqlmock.NewResult(0, 0))
+	for i := 0; i < 3; i++ {
+		mock.ExpectExec("UPDATE").WillReturnError(&pq.Error{Code: "40001"})
+		mock.ExpectExec("ROLLBACK TO SAVEPOINT cockroach_restart").WillReturnResult(sqlmock.NewResult(0, 0))
+	}
+	mock.ExpectExec("UPDATE").WillReturnError(&pq.Error{Code: "40001"})
+	mock.ExpectRollback()
+	attempt = 0
+	err = sess.RunInTx(context.Background(), nil, func(tx *Tx) error {
+		attempt++
+		_, err := tx.Update("account").Set("balance", 1).Where(Eq("id", 1)).Exec()
+		return err
+	})
+	require.Equal(t, &pq.Error{Code: "40001"}, err)
+	require.Equal(t, 4, attempt)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+type testMySQLError struct {
+	Number uint16
+}
+
+func (e *testMySQLError) Error() string {
+	return "Error " + strconv.Itoa(int(e.Number))
+}
+
+func TestInTxRetry(t *testing.T) {
+	sess, mock := newMockSession(t, dialect.PostgreSQL)
+	conn := sess.Connection
+	update := func(tx *Tx) error {
+		_, err := tx.Update("account").Set("balance", 1).Where(Eq("id", 1)).Exec()
+		return err
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE").WillReturnError(&pq.Error{Code: "40P01"})
+	mock.ExpectRollback()
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit().WillReturnError(&pq.Error{Code: "40001"})
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	err := sess.InTx(context.Background(), update, RetryOn(Deadlock, SerializationFailure), Backoff(time.Millisecond))
+	require.NoError(t, err)
+	require.NoError(t, mock.ExpectationsWereMet())
+
+	// the failures not in RetryOn are returned
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE").WillReturnError(&pq.Error{Code: "40001"})
+	mock.ExpectRollback()
+	err = sess.InTx(context.Background(), update, RetryOn(Deadlock))
+	require.Equal(t, &pq.Error{Code: "40001"}, err)
+	require.NoError(t, mock.ExpectationsWereMet())
+
+	// MaxRetries
+	conn.Dialect = dialect.MySQL
+	sess = conn.NewSession(nil)
+	for i := 0; i < 2; i++ {
+		mock.ExpectBegin()
+		mock.ExpectExec("UPDATE").WillReturnError(&testMySQLError{Number: 1213})
+		mock.ExpectRollback()
+	}
+	err = sess.InTx(context.Background(), update, RetryOn(Deadlock), MaxRetries(1), Backoff(time.Millisecond))
+	require.Equal(t, &testMySQLError{Number: 1213}, err)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTxHooks(t *testing.T) {
+	sess, mock := newMockSession(t, dialect.MySQL)
+
+	var called []string
+	hooks := func(tx *Tx) {
+		tx.AfterCommit(func() { called = append(called, "commit 1") })
+		tx.AfterCommit(func() { called = append(called, "commit 2") })
+		tx.AfterRollback(func() { called = append(called, "rollback") })
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectCommit()
+	tx, err := sess.Begin()
+	require.NoError(t, err)
+	hooks(tx)
+	require.NoError(t, tx.Commit())
+	tx.RollbackUnlessCommitted()
+	require.Equal(t, []string{"commit 1", "commit 2"}, called)
+
+	called = nil
+	mock.ExpectBegin()
+	mock.ExpectRollback()
+	tx, err = sess.Begin()
+	require.NoError(t, err)
+	hooks(tx)
+	tx.RollbackUnlessCommitted()
+	require.Error(t, tx.Commit())
+	require.Equal(t, []string{"rollback"}, called)
+
+	called = nil
+	mock.ExpectBegin()
+	mock.ExpectCommit().WillReturnError(errors.New("commit failed"))
+	tx, err = sess.Begin()
+	require.NoError(t, err)
+	hooks(tx)
+	require.Error(t, tx.Commit())
+	require.Equal(t, []string{"rollback"}, called)
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRunInTxPanic(t *testing.T) {
+	sess, mock := newMockSession(t, dialect.MySQL)
+
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	err := RunInTx(context.Background(), sess, func(tx SessionRunner) error {
+		_, err := tx.Update("account").Set("balance", 1).Exec()
+		return err
+	})
+	require.NoError(t, err)
+
+	mock.ExpectBegin()
+	mock.ExpectRollback()
+	failed := errors.New("failed")
+	err = RunInTx(context.Background(), sess, func(tx SessionRunner) error {
+		return failed
+	})
+	require.Equal(t, failed, err)
+
+	mock.ExpectBegin()
+	mock.ExpectRollback()
+	err = RunInTx(context.Background(), sess, func(tx SessionRunner) error {
+		panic(failed)
+	})
+	var panicErr *PanicError
+	require.True(t, errors.As(err, &panicErr))
+	require.Equal(t, failed, panicErr.Value)
+	require.NotEmpty(t, panicErr.Stack)
+	require.True(t, errors.Is(err, failed))
+
+	// Session.RunInTx and InTx share the recovery
+	mock.ExpectBegin()
+	mock.ExpectRollback()
+	err = sess.RunInTx(context.Background(), nil, func(tx *Tx) error {
+		panic(failed)
+	})
+	require.True(t, errors.As(err, &panicErr))
+	mock.ExpectBegin()
+	mock.ExpectRollback()
+	err = sess.InTx(context.Background(), func(tx *Tx) error {
+		panic(failed)
+	}, RetryOn(Deadlock))
+	require.True(t, errors.As(err, &panicErr))
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTwoPhaseTx(t *testing.T) {
+	sess, mock := newMockSession(t, dialect.MySQL)
+	conn := sess.Connection
+	ctx := context.Background()
+
+	mock.ExpectExec(regexp.QuoteMeta("XA START 'order-1'")).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("INSERT INTO `orders`").WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(regexp.QuoteMeta("XA END 'order-1'")).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta("XA PREPARE 'order-1'")).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta("XA COMMIT 'order-1'")).WillReturnResult(sqlmock.NewResult(0, 0))
+
+	tx, err := sess.BeginTwoPhase(ctx, "order-1")
+	require.NoError(t, err)
+	defer tx.RollbackUnlessPrepared()
+	_, err = tx.InsertInto("orders").Pair("id", 1).Exec()
+	require.NoError(t, err)
+	require.Equal(t, ErrTxNotPrepared, tx.Commit())
+	require.NoError(t, tx.Prepare())
+	tx.RollbackUnlessPrepared()
+	require.NoError(t, tx.Commit())
+	require.Equal(t, sql.ErrTxDone, tx.Rollback())
+	require.NoError(t, mock.ExpectationsWereMet())
+
+	// XA END does not run again after XA PREPARE fails,
+	// and the connection is discarded if the rollback fails
+	held, err := sess.DB.Conn(ctx)
+	require.NoError(t, err)
+	defer held.Close()
+	mock.ExpectExec(regexp.QuoteMeta("XA START 'order-6'")).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta("XA END 'order-6'")).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta("XA PREPARE 'order-6'")).WillReturnError(errors.New("prepare failed"))
+	mock.ExpectExec(regexp.QuoteMeta("XA ROLLBACK 'order-6'")).WillReturnError(errors.New("rollback failed"))
+	tx, err = sess.BeginTwoPhase(ctx, "order-6")
+	require.NoError(t, err)
+	require.EqualError(t, tx.Prepare(), "prepare failed")
+	require.EqualError(t, tx.Rollback(), "rollback failed")
+	require.Equal(t, 1, sess.DB.Stats().OpenConnections)
+	require.NoError(t, mock.ExpectationsWereMet())
+
+	conn.Dialect = dialect.PostgreSQL
+	sess = conn.NewSession(nil)
+	mock.ExpectExec("BEGIN").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("UPDATE \"orders\"").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("ROLLBACK").WillReturnResult(sqlmock.NewResult(0, 0))
+	tx, err = sess.BeginTwoPhase(ctx, "order-2")
+	require.NoError(t, err)
+	_, err = tx.Update("orders").Set("paid", true).Exec()
+	require.NoError(t, err)
+	tx.RollbackUnlessPrepared()
+	require.NoError(t, mock.ExpectationsWereMet())
+
+	mock.ExpectExec(regexp.QuoteMeta("ROLLBACK PREPARED 'order-3'")).WillReturnResult(sqlmock.NewResult(0, 0))
+	require.NoError(t, sess.RollbackPrepared(ctx, "order-3"))
+	mock.ExpectQuery("SELECT gid FROM pg_prepared_xacts").
+		WillReturnRows(sqlmock.NewRows([]string{"gid"}).AddRow("order-4"))
+	ids, err := sess.RecoverPrepared(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []string{"order-4"}, ids)
+	require.NoError(t, mock.ExpectationsWereMet())
+
+	conn.Dialect = dialect.SQLite3
+	_, err = conn.NewSession(nil).BeginTwoPhase(ctx, "order-5")
+	require.True(t, errors.Is(err, ErrDialectNotSupported))
+}
@@ -0,0 +1,108 @@
+package dbr
+
+import (
+	"context"
+	"time"
+
+	"github.com/jiyeyuran/dbr/v2/dialect"
+)
+
+// TruncateStmt builds `TRUNCATE TABLE ...`.
+type TruncateStmt struct {
+	runner
+	EventReceiver
+	Dialect
+
+	Table             string
+	IsRestartIdentity bool
+	IsCascade         bool
+
+	timeout time.Duration
+}
+
+type TruncateBuilder = TruncateStmt
+
+func (b *TruncateStmt) Build(d Dialect, buf Buffer) error {
+	if b.Table == "" {
+		return ErrTableNotSpecified
+	}
+
+	if b.IsCascade && d != dialect.PostgreSQL && d != dialect.CockroachDB {
+		return errDialectNotSupported("TRUNCATE CASCADE")
+	}
+
+	if !dialect.CapabilitiesOf(d).SupportsTruncate {
+		// sqlite has no TRUNCATE, but optimizes DELETE without WHERE
+		buf.WriteString("DELETE FROM ")
+		buf.WriteString(quoteTable(d, b.runner, b.Table))
+		return nil
+	}
+
+	buf.WriteString("TRUNCATE TABLE ")
+	buf.WriteString(quoteTable(d, b.runner, b.Table))
+
+	// mysql and mssql always restart identity
+	if d == dialect.PostgreSQL && b.IsRestartIdentity {
+		buf.WriteString(" RESTART IDENTITY")
+	}
+	if b.IsCascade {
+		buf.WriteString(" CASCADE")
+	}
+	return nil
+}
+
+// TruncateTable creates a TruncateStmt.
+func TruncateTable(table string) *TruncateStmt {
+	return &TruncateStmt{
+		Table: table,
+	}
+}
+
+// TruncateTable creates a TruncateStmt.
+func (sess *Session) TruncateTable(table string) *TruncateStmt {
+	b := TruncateTable(table)
+	b.runner = sess
+	b.EventReceiver = sess.EventReceiver
+	b.Dialect = sess.Dialect
+	return b
+}
+
+// TruncateTable creates a TruncateStmt.
+func (tx *Tx) TruncateTable(table string) *TruncateStmt {
+	b := TruncateTable(table)
+	b.runner = tx
+	b.EventReceiver = tx.EventReceiver
+	b.Dialect = tx.Dialect
+	return b
+}
+
+// RestartIdentity resets the sequences owned by the table in postgres.
+// mysql and mssql always reset them.
+func (b *TruncateStmt) RestartIdentity() *TruncateStmt {
+	b.IsRestartIdentity = true
+	return b
+}
+
+// Cascade also truncates the tables that reference the table in postgres.
+func (b *TruncateStmt) Cascade() *TruncateStmt {
+	b.IsCascade = true
+	return b
+}
+
+// Timeout cancels the statement after d like SelectStmt.Timeout.
+func (b *TruncateStmt) Timeout(d time.Duration) *TruncateStmt {
+	b.timeout = d
+	return b
+}
+
+func (b *TruncateStmt) statementTimeout() time.Duration {
+	return b.timeout
+}
+
+func (b *TruncateStmt) Exec() (*Result, error) {
+	return b.ExecContext(context.Background())
+}
+
+func (b *TruncateStmt) ExecContext(ctx context.Context) (*Result, error) {
+	return exec(ctx, b.runner, b.EventReceiver, b, b.Dialect)
+}
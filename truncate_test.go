@@ -0,0 +1,41 @@
+package dbr
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/jiyeyuran/dbr/v2/dialect"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTruncateStmt(t *testing.T) {
+	for _, test := range []struct {
+		builder *TruncateStmt
+		dialect Dialect
+		query   string
+	}{
+		{
+			builder: TruncateTable("user"),
+			dialect: dialect.MySQL,
+			query:   "TRUNCATE TABLE `user`",
+		},
+		{
+			builder: TruncateTable("user").RestartIdentity().Cascade(),
+			dialect: dialect.PostgreSQL,
+			query:   `TRUNCATE TABLE "user" RESTART IDENTITY CASCADE`,
+		},
+		{
+			builder: TruncateTable("user").RestartIdentity(),
+			dialect: dialect.SQLite3,
+			query:   `DELETE FROM "user"`,
+		},
+	} {
+		buf := NewBuffer()
+		err := test.builder.Build(test.dialect, buf)
+		require.NoError(t, err)
+		require.Equal(t, test.query, buf.String())
+	}
+
+	err := TruncateTable("user").Cascade().Build(dialect.MySQL, NewBuffer())
+	require.True(t, errors.Is(err, ErrDialectNotSupported))
+}
@@ -0,0 +1,183 @@
+package dbr
+
+import "github.com/jiyeyuran/dbr/v2/dialect"
+
+// RowValue is a list of columns compared as a whole like `(a, b) > (1, 2)`.
+type RowValue []string
+
+// Tuple creates a RowValue from columns.
+func Tuple(column ...string) RowValue {
+	return RowValue(column)
+}
+
+func (r RowValue) buildColumns(d Dialect, buf Buffer) {
+	buf.WriteString("(")
+	for i, col := range r {
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+		buf.WriteString(d.QuoteIdent(col))
+	}
+	buf.WriteString(")")
+}
+
+func (r RowValue) buildValues(buf Buffer, value []interface{}) {
+	buf.WriteString("(")
+	for i := range value {
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+		buf.WriteString(placeholder)
+	}
+	buf.WriteString(")")
+	buf.WriteValue(value...)
+}
+
+// expand rewrites a row value comparison with scalar comparisons
+// for dialects without row value support.
+//
+// (a, b) > (1, 2) is the same as (a > 1) OR (a = 1 AND b > 2).
+func (r RowValue) expand(pred string, value []interface{}) Builder {
+	switch pred {
+	case "=":
+		return r.expandEq(value)
+	case "!=":
+		return BuildFunc(func(d Dialect, buf Buffer) error {
+			buf.WriteString("NOT (")
+			err := r.expandEq(value).Build(d, buf)
+			if err != nil {
+				return err
+			}
+			buf.WriteString(")")
+			return nil
+		})
+	}
+
+	strict := pred[:1]
+	var cond []Builder
+	for i := range r {
+		var term []Builder
+		for j := 0; j < i; j++ {
+			term = append(term, Eq(r[j], value[j]))
+		}
+		op := strict
+		if i == len(r)-1 {
+			op = pred
+		}
+		col, v := r[i], value[i]
+		term = append(term, BuildFunc(func(d Dialect, buf Buffer) error {
+			return buildCmp(d, buf, op, col, v)
+		}))
+		cond = append(cond, And(term...))
+	}
+	return Or(cond...)
+}
+
+func (r RowValue) expandEq(value []interface{}) Builder {
+	var cond []Builder
+	for i, col := range r {
+		cond = append(cond, Eq(col, value[i]))
+	}
+	return And(cond...)
+}
+
+func (r RowValue) cmp(pred string, value []interface{}) Builder {
+	return BuildFunc(func(d Dialect, buf Buffer) error {
+		if len(value) != len(r) {
+			return ErrPlaceholderCount
+		}
+		if !dialect.CapabilitiesOf(d).SupportsRowValues {
+			return r.expand(pred, value).Build(d, buf)
+		}
+		r.buildColumns(d, buf)
+		buf.WriteString(" ")
+		buf.WriteString(pred)
+		buf.WriteString(" ")
+		r.buildValues(buf, value)
+		return nil
+	})
+}
+
+// Eq is `=`.
+func (r RowValue) Eq(value ...interface{}) Builder {
+	return r.cmp("=", value)
+}
+
+// Neq is `!=`.
+func (r RowValue) Neq(value ...interface{}) Builder {
+	return r.cmp("!=", value)
+}
+
+// Gt is `>`.
+func (r RowValue) Gt(value ...interface{}) Builder {
+	return r.cmp(">", value)
+}
+
+// Gte is `>=`.
+func (r RowValue) Gte(value ...interface{}) Builder {
+	return r.cmp(">=", value)
+}
+
+// Lt is `<`.
+func (r RowValue) Lt(value ...interface{}) Builder {
+	return r.cmp("<", value)
+}
+
+// Lte is `<=`.
+func (r RowValue) Lte(value ...interface{}) Builder {
+	return r.cmp("<=", value)
+}
+
+func (r RowValue) in(isNot bool, value [][]interface{}) Builder {
+	return BuildFunc(func(d Dialect, buf Buffer) error {
+		if len(value) == 0 {
+			buf.WriteString(d.EncodeBool(isNot))
+			return nil
+		}
+		for _, v := range value {
+			if len(v) != len(r) {
+				return ErrPlaceholderCount
+			}
+		}
+		if !dialect.CapabilitiesOf(d).SupportsRowValues {
+			var cond []Builder
+			for _, v := range value {
+				cond = append(cond, r.expandEq(v))
+			}
+			if isNot {
+				buf.WriteString("NOT (")
+				err := Or(cond...).Build(d, buf)
+				if err != nil {
+					return err
+				}
+				buf.WriteString(")")
+				return nil
+			}
+			return Or(cond...).Build(d, buf)
+		}
+		r.buildColumns(d, buf)
+		if isNot {
+			buf.WriteString(" NOT IN (")
+		} else {
+			buf.WriteString(" IN (")
+		}
+		for i, v := range value {
+			if i > 0 {
+				buf.WriteString(", ")
+			}
+			r.buildValues(buf, v)
+		}
+		buf.WriteString(")")
+		return nil
+	})
+}
+
+// In is `IN` with a list of row values.
+func (r RowValue) In(value ...[]interface{}) Builder {
+	return r.in(false, value)
+}
+
+// NotIn is `NOT IN` with a list of row values.
+func (r RowValue) NotIn(value ...[]interface{}) Builder {
+	return r.in(true, value)
+}
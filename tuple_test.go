@@ -0,0 +1,68 @@
+package dbr
+
+import (
+	"testing"
+
+	"github.com/jiyeyuran/dbr/v2/dialect"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTuple(t *testing.T) {
+	for _, test := range []struct {
+		cond    Builder
+		dialect Dialect
+		query   string
+		value   []interface{}
+	}{
+		{
+			cond:    Tuple("a", "b").Gt(1, 2),
+			dialect: dialect.MySQL,
+			query:   "(`a`, `b`) > (?, ?)",
+			value:   []interface{}{1, 2},
+		},
+		{
+			cond:    Tuple("a", "b").Eq(1, 2),
+			dialect: dialect.PostgreSQL,
+			query:   `("a", "b") = (?, ?)`,
+			value:   []interface{}{1, 2},
+		},
+		{
+			cond:    Tuple("a", "b").In([]interface{}{1, 2}, []interface{}{3, 4}),
+			dialect: dialect.MySQL,
+			query:   "(`a`, `b`) IN ((?, ?), (?, ?))",
+			value:   []interface{}{1, 2, 3, 4},
+		},
+		{
+			cond:    Tuple("a", "b").NotIn(),
+			dialect: dialect.MySQL,
+			query:   "1",
+		},
+		{
+			cond:    Tuple("a", "b", "c").Lte(1, 2, 3),
+			dialect: dialect.MSSQL,
+			query:   `(([a] < ?)) OR (([a] = ?) AND ([b] < ?)) OR (([a] = ?) AND ([b] = ?) AND ([c] <= ?))`,
+			value:   []interface{}{1, 1, 2, 1, 2, 3},
+		},
+		{
+			cond:    Tuple("a", "b").Neq(1, 2),
+			dialect: dialect.MSSQL,
+			query:   `NOT (([a] = ?) AND ([b] = ?))`,
+			value:   []interface{}{1, 2},
+		},
+		{
+			cond:    Tuple("a", "b").In([]interface{}{1, 2}, []interface{}{3, 4}),
+			dialect: dialect.MSSQL,
+			query:   `(([a] = ?) AND ([b] = ?)) OR (([a] = ?) AND ([b] = ?))`,
+			value:   []interface{}{1, 2, 3, 4},
+		},
+	} {
+		buf := NewBuffer()
+		err := test.cond.Build(test.dialect, buf)
+		require.NoError(t, err)
+		require.Equal(t, test.query, buf.String())
+		require.Equal(t, test.value, buf.Value())
+	}
+
+	err := Tuple("a", "b").Gt(1).Build(dialect.MySQL, NewBuffer())
+	require.Equal(t, ErrPlaceholderCount, err)
+}
@@ -0,0 +1,382 @@
+package dbr
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"time"
+
+	"github.com/jiyeyuran/dbr/v2/dialect"
+)
+
+// TwoPhaseTx is a transaction committed in two phases, which coordinates
+// the writes across databases: Prepare all transactions first, then Commit
+// them if all of them are prepared, or Rollback otherwise.
+//
+// It is XA in mysql, and PREPARE TRANSACTION in postgres, which requires
+// max_prepared_transactions > 0. The prepared transactions survive the
+// connections, so that they can be finished by Session.CommitPrepared and
+// Session.RollbackPrepared after crashes.
+type TwoPhaseTx struct {
+	EventReceiver
+	Dialect
+	// ID is the global transaction id.
+	ID string
+
+	conn     *sql.Conn
+	sess     *Session
+	prepared bool
+	done     bool
+	// ended is set after XA END, which fails if it runs again.
+	ended bool
+}
+
+var _ SessionRunner = (*TwoPhaseTx)(nil)
+
+// BeginTwoPhase begins a two-phase transaction with the global id,
+// which must be unique among the prepared transactions.
+func (sess *Session) BeginTwoPhase(ctx context.Context, id string) (*TwoPhaseTx, error) {
+	var begin string
+	switch dialect.CapabilitiesOf(sess.Dialect).TwoPhaseStyle {
+	case dialect.XA:
+		begin = "XA START " + sess.EncodeString(id)
+	case dialect.PrepareTransaction:
+		begin = "BEGIN"
+	default:
+		return nil, errDialectNotSupported("two-phase commit")
+	}
+
+	// the transaction outlives the session, so it has its own connection
+	db, err := sess.pool()
+	if err != nil {
+		return nil, sess.EventErr("dbr.begin.error", err)
+	}
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return nil, sess.EventErr("dbr.begin.error", err)
+	}
+	_, err = conn.ExecContext(ctx, begin)
+	if err != nil {
+		conn.Close()
+		return nil, sess.EventErr("dbr.begin.error", err)
+	}
+	sess.Event("dbr.begin")
+
+	return &TwoPhaseTx{
+		EventReceiver: sess.EventReceiver,
+		Dialect:       sess.Dialect,
+		ID:            id,
+		conn:          conn,
+		sess:          sess,
+	}, nil
+}
+
+func (tx *TwoPhaseTx) nameMapper() func(string) string {
+	return tx.sess.NameMapper
+}
+
+func (tx *TwoPhaseTx) tagName() string {
+	return tx.sess.TagName
+}
+
+func (tx *TwoPhaseTx) strict() bool {
+	return tx.sess.Strict
+}
+
+func (tx *TwoPhaseTx) schema() string {
+	return tx.sess.Schema
+}
+
+func (tx *TwoPhaseTx) timeOptions() *TimeOptions {
+	return tx.sess.timeOptions()
+}
+
+func (tx *TwoPhaseTx) commentTags(ctx context.Context) map[string]string {
+	return tx.sess.commentTags(ctx)
+}
+
+func (tx *TwoPhaseTx) watchdog() *Watchdog {
+	return tx.sess.watchdog()
+}
+
+// ExecContext executes a query in the transaction.
+func (tx *TwoPhaseTx) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	return tx.conn.ExecContext(ctx, query, args...)
+}
+
+// QueryContext executes a query that returns rows in the transaction.
+func (tx *TwoPhaseTx) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	return tx.conn.QueryContext(ctx, query, args...)
+}
+
+// GetTimeout returns the timeout of the session.
+func (tx *TwoPhaseTx) GetTimeout() time.Duration {
+	return tx.sess.GetTimeout()
+}
+
+// Prepare finishes the first phase, after which the transaction can only be
+// committed or rolled back. The connection is released.
+func (tx *TwoPhaseTx) Prepare() error {
+	return tx.PrepareContext(context.Background())
+}
+
+// PrepareContext is like Prepare with context.
+func (tx *TwoPhaseTx) PrepareContext(ctx context.Context) error {
+	if tx.done || tx.prepared {
+		return sql.ErrTxDone
+	}
+	id := tx.EncodeString(tx.ID)
+	var err error
+	switch dialect.CapabilitiesOf(tx.Dialect).TwoPhaseStyle {
+	case dialect.XA:
+		err = tx.xaEnd(ctx, id)
+		if err == nil {
+			_, err = tx.conn.ExecContext(ctx, "XA PREPARE "+id)
+		}
+	default:
+		_, err = tx.conn.ExecContext(ctx, "PREPARE TRANSACTION "+id)
+	}
+	if err != nil {
+		return tx.EventErr("dbr.prepare.error", err)
+	}
+	tx.prepared = true
+	tx.conn.Close()
+	tx.Event("dbr.prepare")
+	return nil
+}
+
+// Commit commits the prepared transaction.
+func (tx *TwoPhaseTx) Commit() error {
+	return tx.CommitContext(context.Background())
+}
+
+// CommitContext is like Commit with context.
+func (tx *TwoPhaseTx) CommitContext(ctx context.Context) error {
+	if tx.done {
+		return sql.ErrTxDone
+	}
+	if !tx.prepared {
+		return ErrTxNotPrepared
+	}
+	err := tx.sess.CommitPrepared(ctx, tx.ID)
+	if err != nil {
+		return err
+	}
+	tx.done = true
+	return nil
+}
+
+// Rollback rolls back the transaction, which may be prepared.
+func (tx *TwoPhaseTx) Rollback() error {
+	return tx.RollbackContext(context.Background())
+}
+
+// RollbackContext is like Rollback with context.
+func (tx *TwoPhaseTx) RollbackContext(ctx context.Context) error {
+	if tx.done {
+		return sql.ErrTxDone
+	}
+	if tx.prepared {
+		err := tx.sess.RollbackPrepared(ctx, tx.ID)
+		if err != nil {
+			return err
+		}
+		tx.done = true
+		return nil
+	}
+
+	tx.done = true
+	var err error
+	switch dialect.CapabilitiesOf(tx.Dialect).TwoPhaseStyle {
+	case dialect.XA:
+		id := tx.EncodeString(tx.ID)
+		err = tx.xaEnd(ctx, id)
+		if err == nil {
+			_, err = tx.conn.ExecContext(ctx, "XA ROLLBACK "+id)
+		}
+	default:
+		_, err = tx.conn.ExecContext(ctx, "ROLLBACK")
+	}
+	if err != nil {
+		// the connection is still in the transaction
+		discardConn(tx.conn)
+		return tx.EventErr("dbr.rollback", err)
+	}
+	tx.conn.Close()
+	tx.Event("dbr.rollback")
+	return nil
+}
+
+// xaEnd runs XA END unless it has run.
+func (tx *TwoPhaseTx) xaEnd(ctx context.Context, id string) error {
+	if tx.ended {
+		return nil
+	}
+	_, err := tx.conn.ExecContext(ctx, "XA END "+id)
+	if err != nil {
+		return err
+	}
+	tx.ended = true
+	return nil
+}
+
+// RollbackUnlessPrepared rolls back the transaction unless it has been
+// prepared, committed or rolled back, which is useful with defer.
+// The prepared transaction is left to the coordinator.
+func (tx *TwoPhaseTx) RollbackUnlessPrepared() {
+	if tx.done || tx.prepared {
+		return
+	}
+	tx.RollbackContext(context.Background())
+}
+
+// CommitPrepared commits the prepared transaction of id,
+// which may be prepared by another connection.
+func (sess *Session) CommitPrepared(ctx context.Context, id string) error {
+	return sess.finishPrepared(ctx, id, "XA COMMIT ", "COMMIT PREPARED ", "dbr.commit")
+}
+
+// RollbackPrepared rolls back the prepared transaction of id,
+// which may be prepared by another connection.
+func (sess *Session) RollbackPrepared(ctx context.Context, id string) error {
+	return sess.finishPrepared(ctx, id, "XA ROLLBACK ", "ROLLBACK PREPARED ", "dbr.rollback")
+}
+
+func (sess *Session) finishPrepared(ctx context.Context, id, xa, prepared, event string) error {
+	var query string
+	switch dialect.CapabilitiesOf(sess.Dialect).TwoPhaseStyle {
+	case dialect.XA:
+		query = xa + sess.EncodeString(id)
+	case dialect.PrepareTransaction:
+		query = prepared + sess.EncodeString(id)
+	default:
+		return errDialectNotSupported("two-phase commit")
+	}
+	_, err := sess.Connection.DB.ExecContext(ctx, query)
+	if err != nil {
+		return sess.EventErrKv(event+".error", err, kvs{
+			"sql": query,
+		})
+	}
+	sess.Event(event)
+	return nil
+}
+
+// RecoverPrepared returns the ids of the prepared transactions,
+// which are left by crashed coordinators.
+func (sess *Session) RecoverPrepared(ctx context.Context) ([]string, error) {
+	var ids []string
+	switch dialect.CapabilitiesOf(sess.Dialect).TwoPhaseStyle {
+	case dialect.XA:
+		// the id is data of gtrid_length without bqual
+		rows, err := sess.Connection.DB.QueryContext(ctx, "XA RECOVER")
+		if err != nil {
+			return nil, err
+		}
+		defer rows.Close()
+		for rows.Next() {
+			var formatID, gtridLength, bqualLength int
+			var data string
+			err := rows.Scan(&formatID, &gtridLength, &bqualLength, &data)
+			if err != nil {
+				return nil, err
+			}
+			if gtridLength <= len(data) {
+				data = data[:gtridLength]
+			}
+			ids = append(ids, data)
+		}
+		return ids, rows.Err()
+	case dialect.PrepareTransaction:
+		_, err := sess.SelectBySql("SELECT gid FROM pg_prepared_xacts WHERE database = current_database()").
+			LoadContext(ctx, &ids)
+		return ids, err
+	}
+	return nil, errDialectNotSupported("two-phase commit")
+}
+
+// Select creates a SelectStmt.
+func (tx *TwoPhaseTx) Select(column ...string) *SelectStmt {
+	b := Select(prepareSelect(column)...)
+	b.runner = tx
+	b.EventReceiver = tx.EventReceiver
+	b.Dialect = tx.Dialect
+	return b
+}
+
+// SelectBySql creates a SelectStmt from raw query.
+func (tx *TwoPhaseTx) SelectBySql(query string, value ...interface{}) *SelectStmt {
+	b := SelectBySql(query, value...)
+	b.runner = tx
+	b.EventReceiver = tx.EventReceiver
+	b.Dialect = tx.Dialect
+	return b
+}
+
+// InsertInto creates an InsertStmt.
+func (tx *TwoPhaseTx) InsertInto(table string) *InsertStmt {
+	b := InsertInto(table)
+	b.runner = tx
+	b.EventReceiver = tx.EventReceiver
+	b.Dialect = tx.Dialect
+	return b
+}
+
+// InsertBySql creates an InsertStmt from raw query.
+func (tx *TwoPhaseTx) InsertBySql(query string, value ...interface{}) *InsertStmt {
+	b := InsertBySql(query, value...)
+	b.runner = tx
+	b.EventReceiver = tx.EventReceiver
+	b.Dialect = tx.Dialect
+	return b
+}
+
+// Update creates an UpdateStmt.
+func (tx *TwoPhaseTx) Update(table string) *UpdateStmt {
+	b := Update(table)
+	b.runner = tx
+	b.EventReceiver = tx.EventReceiver
+	b.Dialect = tx.Dialect
+	return b
+}
+
+// UpdateBySql creates an UpdateStmt with raw query.
+func (tx *TwoPhaseTx) UpdateBySql(query string, value ...interface{}) *UpdateStmt {
+	b := UpdateBySql(query, value...)
+	b.runner = tx
+	b.EventReceiver = tx.EventReceiver
+	b.Dialect = tx.Dialect
+	return b
+}
+
+// DeleteFrom creates a DeleteStmt.
+func (tx *TwoPhaseTx) DeleteFrom(table string) *DeleteStmt {
+	b := DeleteFrom(table)
+	b.runner = tx
+	b.EventReceiver = tx.EventReceiver
+	b.Dialect = tx.Dialect
+	return b
+}
+
+// DeleteBySql creates a DeleteStmt from raw query.
+func (tx *TwoPhaseTx) DeleteBySql(query string, value ...interface{}) *DeleteStmt {
+	b := DeleteBySql(query, value...)
+	b.runner = tx
+	b.EventReceiver = tx.EventReceiver
+	b.Dialect = tx.Dialect
+	return b
+}
+
+// discardConn closes conn without returning it to the pool.
+// It waits for the rows and the transaction of conn to be closed.
+func discardConn(conn *sql.Conn) error {
+	// database/sql discards the connection if Raw returns driver.ErrBadConn
+	err := conn.Raw(func(interface{}) error {
+		return driver.ErrBadConn
+	})
+	if errors.Is(err, driver.ErrBadConn) {
+		return nil
+	}
+	return err
+}
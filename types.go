@@ -4,8 +4,14 @@ import (
 	"bytes"
 	"database/sql"
 	"database/sql/driver"
+	"encoding/base64"
 	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
 	"time"
+
+	"github.com/jiyeyuran/dbr/v2/dialect"
 )
 
 //
@@ -27,10 +33,140 @@ type NullInt64 struct {
 	sql.NullInt64
 }
 
+// NullInt32 is a type that can be null or an int32.
+type NullInt32 struct {
+	sql.NullInt32
+}
+
+// NullInt16 is a type that can be null or an int16.
+type NullInt16 struct {
+	sql.NullInt16
+}
+
+// NullByte is a type that can be null or a byte.
+type NullByte struct {
+	sql.NullByte
+}
+
+// NullUint64 is a type that can be null or an uint64,
+// like BIGINT UNSIGNED in mysql.
+type NullUint64 struct {
+	Uint64 uint64
+	Valid  bool // Valid is true if Uint64 is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (n *NullUint64) Scan(value interface{}) error {
+	if value == nil {
+		n.Uint64, n.Valid = 0, false
+		return nil
+	}
+	err := convertAssign(&n.Uint64, value)
+	n.Valid = err == nil
+	return err
+}
+
+// Value implements the driver Valuer interface.
+// The value is uint64 if it overflows int64, which is supported by mysql driver.
+func (n NullUint64) Value() (driver.Value, error) {
+	if !n.Valid {
+		return nil, nil
+	}
+	if n.Uint64 > math.MaxInt64 {
+		return n.Uint64, nil
+	}
+	return int64(n.Uint64), nil
+}
+
+// NullUint32 is a type that can be null or an uint32.
+type NullUint32 struct {
+	Uint32 uint32
+	Valid  bool // Valid is true if Uint32 is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (n *NullUint32) Scan(value interface{}) error {
+	if value == nil {
+		n.Uint32, n.Valid = 0, false
+		return nil
+	}
+	err := convertAssign(&n.Uint32, value)
+	n.Valid = err == nil
+	return err
+}
+
+// Value implements the driver Valuer interface.
+func (n NullUint32) Value() (driver.Value, error) {
+	if !n.Valid {
+		return nil, nil
+	}
+	return int64(n.Uint32), nil
+}
+
+// NullBytes is a type that can be null or bytes, like BLOB or BYTEA.
+// Unlike []byte, the empty bytes and NULL are distinguished.
+type NullBytes struct {
+	Bytes []byte
+	Valid bool // Valid is true if Bytes is not NULL
+}
+
+// Scan implements the Scanner interface.
+// The bytes are copied, because the driver may reuse them.
+func (n *NullBytes) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		n.Bytes, n.Valid = nil, false
+		return nil
+	case []byte:
+		n.Bytes = append([]byte{}, v...)
+	case string:
+		n.Bytes = []byte(v)
+	default:
+		n.Bytes, n.Valid = nil, false
+		return fmt.Errorf("dbr: cannot scan %T into NullBytes", value)
+	}
+	n.Valid = true
+	return nil
+}
+
+// Value implements the driver Valuer interface.
+func (n NullBytes) Value() (driver.Value, error) {
+	if !n.Valid {
+		return nil, nil
+	}
+	if n.Bytes == nil {
+		return []byte{}, nil
+	}
+	return n.Bytes, nil
+}
+
+// TimeLocation is the location of time strings without zone, like DATETIME
+// in mysql, which are scanned by NullTime without Location.
+var TimeLocation = time.UTC
+
 // NullTime is a type that can be null or a time.
 type NullTime struct {
 	Time  time.Time
 	Valid bool // Valid is true if Time is not NULL
+	// Location is the location of time strings without zone,
+	// which is TimeLocation if it is nil.
+	Location *time.Location
+}
+
+// NullTimeIn creates a NullTime that scans time strings in loc,
+// for the databases storing local time.
+func NullTimeIn(loc *time.Location) NullTime {
+	return NullTime{Location: loc}
+}
+
+func (n *NullTime) location() *time.Location {
+	if n.Location != nil {
+		return n.Location
+	}
+	if TimeLocation != nil {
+		return TimeLocation
+	}
+	return time.UTC
 }
 
 // Value implements the driver Valuer interface.
@@ -41,6 +177,23 @@ func (n NullTime) Value() (driver.Value, error) {
 	return n.Time, nil
 }
 
+// DialectValue implements DialectValuer. Time is truncated to the precision
+// of the dialect, like microseconds in mysql, the same as the interpolated
+// times, instead of being rounded by the driver.
+func (n NullTime) DialectValue(d Dialect) (driver.Value, error) {
+	if !n.Valid {
+		return nil, nil
+	}
+	if precision := dialect.CapabilitiesOf(d).TimePrecision; precision > 0 {
+		return n.Time.Truncate(precision), nil
+	}
+	return n.Time, nil
+}
+
+func (n *NullTime) parse(str string) (time.Time, error) {
+	return parseDateTime(str, n.location())
+}
+
 // NullBool is a type that can be null or a bool.
 type NullBool struct {
 	sql.NullBool
@@ -64,6 +217,54 @@ func (n NullInt64) MarshalJSON() ([]byte, error) {
 	return nullString, nil
 }
 
+// MarshalJSON correctly serializes a NullInt32 to JSON.
+func (n NullInt32) MarshalJSON() ([]byte, error) {
+	if n.Valid {
+		return json.Marshal(n.Int32)
+	}
+	return nullString, nil
+}
+
+// MarshalJSON correctly serializes a NullInt16 to JSON.
+func (n NullInt16) MarshalJSON() ([]byte, error) {
+	if n.Valid {
+		return json.Marshal(n.Int16)
+	}
+	return nullString, nil
+}
+
+// MarshalJSON correctly serializes a NullByte to JSON.
+func (n NullByte) MarshalJSON() ([]byte, error) {
+	if n.Valid {
+		return json.Marshal(n.Byte)
+	}
+	return nullString, nil
+}
+
+// MarshalJSON correctly serializes a NullUint64 to JSON.
+func (n NullUint64) MarshalJSON() ([]byte, error) {
+	if n.Valid {
+		return json.Marshal(n.Uint64)
+	}
+	return nullString, nil
+}
+
+// MarshalJSON correctly serializes a NullUint32 to JSON.
+func (n NullUint32) MarshalJSON() ([]byte, error) {
+	if n.Valid {
+		return json.Marshal(n.Uint32)
+	}
+	return nullString, nil
+}
+
+// MarshalJSON correctly serializes a NullBytes to JSON string in base64.
+func (n NullBytes) MarshalJSON() ([]byte, error) {
+	if n.Valid {
+		return json.Marshal(base64.StdEncoding.EncodeToString(n.Bytes))
+	}
+	return nullString, nil
+}
+
 // MarshalJSON correctly serializes a NullFloat64 to JSON.
 func (n NullFloat64) MarshalJSON() ([]byte, error) {
 	if n.Valid {
@@ -109,6 +310,82 @@ func (n *NullInt64) UnmarshalJSON(b []byte) error {
 	return n.Scan(s)
 }
 
+// UnmarshalJSON correctly deserializes a NullInt32 from JSON.
+func (n *NullInt32) UnmarshalJSON(b []byte) error {
+	var s json.Number
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+	if s == "" {
+		return n.Scan(nil)
+	}
+	return n.Scan(string(s))
+}
+
+// UnmarshalJSON correctly deserializes a NullInt16 from JSON.
+func (n *NullInt16) UnmarshalJSON(b []byte) error {
+	var s json.Number
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+	if s == "" {
+		return n.Scan(nil)
+	}
+	return n.Scan(string(s))
+}
+
+// UnmarshalJSON correctly deserializes a NullByte from JSON.
+func (n *NullByte) UnmarshalJSON(b []byte) error {
+	var s json.Number
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+	if s == "" {
+		return n.Scan(nil)
+	}
+	return n.Scan(string(s))
+}
+
+// UnmarshalJSON correctly deserializes a NullUint64 from JSON.
+func (n *NullUint64) UnmarshalJSON(b []byte) error {
+	var s json.Number
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+	if s == "" {
+		return n.Scan(nil)
+	}
+	return n.Scan(string(s))
+}
+
+// UnmarshalJSON correctly deserializes a NullUint32 from JSON.
+func (n *NullUint32) UnmarshalJSON(b []byte) error {
+	var s json.Number
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+	if s == "" {
+		return n.Scan(nil)
+	}
+	return n.Scan(string(s))
+}
+
+// UnmarshalJSON correctly deserializes a NullBytes from JSON string in base64.
+func (n *NullBytes) UnmarshalJSON(b []byte) error {
+	if bytes.Equal(b, nullString) {
+		return n.Scan(nil)
+	}
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+	decoded, err := base64.StdEncoding.DecodeString(s)
+	if err != nil {
+		return err
+	}
+	return n.Scan(decoded)
+}
+
 // UnmarshalJSON correctly deserializes a NullFloat64 from JSON.
 func (n *NullFloat64) UnmarshalJSON(b []byte) error {
 	var s interface{}
@@ -141,8 +418,802 @@ func (n *NullBool) UnmarshalJSON(b []byte) error {
 	return n.Scan(s)
 }
 
-// NewNullInt64 creates a NullInt64 with Scan().
-func NewNullInt64(v interface{}) (n NullInt64) {
+// MarshalText implements encoding.TextMarshaler.
+// NULL is the empty text.
+func (n NullString) MarshalText() ([]byte, error) {
+	if !n.Valid {
+		return []byte{}, nil
+	}
+	return []byte(n.String), nil
+}
+
+// MarshalText implements encoding.TextMarshaler.
+// NULL is the empty text.
+func (n NullInt64) MarshalText() ([]byte, error) {
+	if !n.Valid {
+		return []byte{}, nil
+	}
+	return strconv.AppendInt(nil, n.Int64, 10), nil
+}
+
+// MarshalText implements encoding.TextMarshaler.
+// NULL is the empty text.
+func (n NullInt32) MarshalText() ([]byte, error) {
+	if !n.Valid {
+		return []byte{}, nil
+	}
+	return strconv.AppendInt(nil, int64(n.Int32), 10), nil
+}
+
+// MarshalText implements encoding.TextMarshaler.
+// NULL is the empty text.
+func (n NullInt16) MarshalText() ([]byte, error) {
+	if !n.Valid {
+		return []byte{}, nil
+	}
+	return strconv.AppendInt(nil, int64(n.Int16), 10), nil
+}
+
+// MarshalText implements encoding.TextMarshaler.
+// NULL is the empty text.
+func (n NullByte) MarshalText() ([]byte, error) {
+	if !n.Valid {
+		return []byte{}, nil
+	}
+	return strconv.AppendUint(nil, uint64(n.Byte), 10), nil
+}
+
+// MarshalText implements encoding.TextMarshaler.
+// NULL is the empty text.
+func (n NullUint64) MarshalText() ([]byte, error) {
+	if !n.Valid {
+		return []byte{}, nil
+	}
+	return strconv.AppendUint(nil, n.Uint64, 10), nil
+}
+
+// MarshalText implements encoding.TextMarshaler.
+// NULL is the empty text.
+func (n NullUint32) MarshalText() ([]byte, error) {
+	if !n.Valid {
+		return []byte{}, nil
+	}
+	return strconv.AppendUint(nil, uint64(n.Uint32), 10), nil
+}
+
+// MarshalText implements encoding.TextMarshaler in base64.
+// NULL is the empty text.
+func (n NullBytes) MarshalText() ([]byte, error) {
+	if !n.Valid {
+		return []byte{}, nil
+	}
+	return []byte(base64.StdEncoding.EncodeToString(n.Bytes)), nil
+}
+
+// MarshalText implements encoding.TextMarshaler.
+// NULL is the empty text.
+func (n NullFloat64) MarshalText() ([]byte, error) {
+	if !n.Valid {
+		return []byte{}, nil
+	}
+	return strconv.AppendFloat(nil, n.Float64, 'g', -1, 64), nil
+}
+
+// MarshalText implements encoding.TextMarshaler in RFC 3339.
+// NULL is the empty text.
+func (n NullTime) MarshalText() ([]byte, error) {
+	if !n.Valid {
+		return []byte{}, nil
+	}
+	return n.Time.MarshalText()
+}
+
+// MarshalText implements encoding.TextMarshaler.
+// NULL is the empty text.
+func (n NullBool) MarshalText() ([]byte, error) {
+	if !n.Valid {
+		return []byte{}, nil
+	}
+	return strconv.AppendBool(nil, n.Bool), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+// The empty text is NULL.
+func (n *NullString) UnmarshalText(text []byte) error {
+	if len(text) == 0 {
+		return n.Scan(nil)
+	}
+	return n.Scan(string(text))
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+// The empty text is NULL.
+func (n *NullInt64) UnmarshalText(text []byte) error {
+	if len(text) == 0 {
+		return n.Scan(nil)
+	}
+	return n.Scan(string(text))
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+// The empty text is NULL.
+func (n *NullInt32) UnmarshalText(text []byte) error {
+	if len(text) == 0 {
+		return n.Scan(nil)
+	}
+	return n.Scan(string(text))
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+// The empty text is NULL.
+func (n *NullInt16) UnmarshalText(text []byte) error {
+	if len(text) == 0 {
+		return n.Scan(nil)
+	}
+	return n.Scan(string(text))
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+// The empty text is NULL.
+func (n *NullByte) UnmarshalText(text []byte) error {
+	if len(text) == 0 {
+		return n.Scan(nil)
+	}
+	return n.Scan(string(text))
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+// The empty text is NULL.
+func (n *NullUint64) UnmarshalText(text []byte) error {
+	if len(text) == 0 {
+		return n.Scan(nil)
+	}
+	return n.Scan(string(text))
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+// The empty text is NULL.
+func (n *NullUint32) UnmarshalText(text []byte) error {
+	if len(text) == 0 {
+		return n.Scan(nil)
+	}
+	return n.Scan(string(text))
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler in base64.
+// The empty text is NULL.
+func (n *NullBytes) UnmarshalText(text []byte) error {
+	if len(text) == 0 {
+		return n.Scan(nil)
+	}
+	decoded, err := base64.StdEncoding.DecodeString(string(text))
+	if err != nil {
+		return err
+	}
+	return n.Scan(decoded)
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+// The empty text is NULL.
+func (n *NullFloat64) UnmarshalText(text []byte) error {
+	if len(text) == 0 {
+		return n.Scan(nil)
+	}
+	return n.Scan(string(text))
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler in RFC 3339,
+// or the formats accepted by Scan(). The empty text is NULL.
+func (n *NullTime) UnmarshalText(text []byte) error {
+	if len(text) == 0 {
+		return n.Scan(nil)
+	}
+	var t time.Time
+	if err := t.UnmarshalText(text); err == nil {
+		return n.Scan(t)
+	}
+	return n.Scan(string(text))
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+// The empty text is NULL.
+func (n *NullBool) UnmarshalText(text []byte) error {
+	if len(text) == 0 {
+		return n.Scan(nil)
+	}
+	return n.Scan(string(text))
+}
+
+// MarshalYAML implements yaml.Marshaler, which is the same in
+// gopkg.in/yaml.v2 and gopkg.in/yaml.v3.
+func (n NullString) MarshalYAML() (interface{}, error) {
+	if !n.Valid {
+		return nil, nil
+	}
+	return n.String, nil
+}
+
+// MarshalYAML implements yaml.Marshaler.
+func (n NullInt64) MarshalYAML() (interface{}, error) {
+	if !n.Valid {
+		return nil, nil
+	}
+	return n.Int64, nil
+}
+
+// MarshalYAML implements yaml.Marshaler.
+func (n NullInt32) MarshalYAML() (interface{}, error) {
+	if !n.Valid {
+		return nil, nil
+	}
+	return n.Int32, nil
+}
+
+// MarshalYAML implements yaml.Marshaler.
+func (n NullInt16) MarshalYAML() (interface{}, error) {
+	if !n.Valid {
+		return nil, nil
+	}
+	return n.Int16, nil
+}
+
+// MarshalYAML implements yaml.Marshaler.
+func (n NullByte) MarshalYAML() (interface{}, error) {
+	if !n.Valid {
+		return nil, nil
+	}
+	return n.Byte, nil
+}
+
+// MarshalYAML implements yaml.Marshaler.
+func (n NullUint64) MarshalYAML() (interface{}, error) {
+	if !n.Valid {
+		return nil, nil
+	}
+	return n.Uint64, nil
+}
+
+// MarshalYAML implements yaml.Marshaler.
+func (n NullUint32) MarshalYAML() (interface{}, error) {
+	if !n.Valid {
+		return nil, nil
+	}
+	return n.Uint32, nil
+}
+
+// MarshalYAML implements yaml.Marshaler.
+// The bytes are base64 string like JSON.
+func (n NullBytes) MarshalYAML() (interface{}, error) {
+	if !n.Valid {
+		return nil, nil
+	}
+	return base64.StdEncoding.EncodeToString(n.Bytes), nil
+}
+
+// MarshalYAML implements yaml.Marshaler.
+func (n NullFloat64) MarshalYAML() (interface{}, error) {
+	if !n.Valid {
+		return nil, nil
+	}
+	return n.Float64, nil
+}
+
+// MarshalYAML implements yaml.Marshaler.
+func (n NullTime) MarshalYAML() (interface{}, error) {
+	if !n.Valid {
+		return nil, nil
+	}
+	return n.Time, nil
+}
+
+// MarshalYAML implements yaml.Marshaler.
+func (n NullBool) MarshalYAML() (interface{}, error) {
+	if !n.Valid {
+		return nil, nil
+	}
+	return n.Bool, nil
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler.
+func (n *NullString) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var v *string
+	if err := unmarshal(&v); err != nil {
+		return err
+	}
+	if v == nil {
+		return n.Scan(nil)
+	}
+	return n.Scan(*v)
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler.
+func (n *NullInt64) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var v *int64
+	if err := unmarshal(&v); err != nil {
+		return err
+	}
+	if v == nil {
+		return n.Scan(nil)
+	}
+	return n.Scan(*v)
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler.
+func (n *NullInt32) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var v *int64
+	if err := unmarshal(&v); err != nil {
+		return err
+	}
+	if v == nil {
+		return n.Scan(nil)
+	}
+	return n.Scan(*v)
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler.
+func (n *NullInt16) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var v *int64
+	if err := unmarshal(&v); err != nil {
+		return err
+	}
+	if v == nil {
+		return n.Scan(nil)
+	}
+	return n.Scan(*v)
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler.
+func (n *NullByte) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var v *int64
+	if err := unmarshal(&v); err != nil {
+		return err
+	}
+	if v == nil {
+		return n.Scan(nil)
+	}
+	return n.Scan(*v)
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler.
+func (n *NullUint64) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var v *uint64
+	if err := unmarshal(&v); err != nil {
+		return err
+	}
+	if v == nil {
+		return n.Scan(nil)
+	}
+	return n.Scan(*v)
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler.
+func (n *NullUint32) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var v *int64
+	if err := unmarshal(&v); err != nil {
+		return err
+	}
+	if v == nil {
+		return n.Scan(nil)
+	}
+	return n.Scan(*v)
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler like UnmarshalText.
+func (n *NullBytes) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var v *string
+	if err := unmarshal(&v); err != nil {
+		return err
+	}
+	if v == nil {
+		return n.Scan(nil)
+	}
+	return n.UnmarshalText([]byte(*v))
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler.
+func (n *NullFloat64) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var v *float64
+	if err := unmarshal(&v); err != nil {
+		return err
+	}
+	if v == nil {
+		return n.Scan(nil)
+	}
+	return n.Scan(*v)
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler like UnmarshalText.
+func (n *NullTime) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var v *string
+	if err := unmarshal(&v); err != nil {
+		return err
+	}
+	if v == nil {
+		return n.Scan(nil)
+	}
+	return n.UnmarshalText([]byte(*v))
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler.
+func (n *NullBool) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var v *bool
+	if err := unmarshal(&v); err != nil {
+		return err
+	}
+	if v == nil {
+		return n.Scan(nil)
+	}
+	return n.Scan(*v)
+}
+
+// NullStringFrom creates a valid NullString.
+func NullStringFrom(v string) NullString {
+	return NullString{sql.NullString{String: v, Valid: true}}
+}
+
+// NullStringFromPtr creates a NullString that is NULL if p is nil.
+func NullStringFromPtr(p *string) (n NullString) {
+	if p != nil {
+		n = NullStringFrom(*p)
+	}
+	return
+}
+
+// Ptr returns a pointer to the value, or nil if n is NULL.
+func (n NullString) Ptr() *string {
+	if !n.Valid {
+		return nil
+	}
+	v := n.String
+	return &v
+}
+
+// ValueOrZero returns the value, or the zero value if n is NULL.
+func (n NullString) ValueOrZero() string {
+	if !n.Valid {
+		return ""
+	}
+	return n.String
+}
+
+// NullInt64From creates a valid NullInt64.
+func NullInt64From(v int64) NullInt64 {
+	return NullInt64{sql.NullInt64{Int64: v, Valid: true}}
+}
+
+// NullInt64FromPtr creates a NullInt64 that is NULL if p is nil.
+func NullInt64FromPtr(p *int64) (n NullInt64) {
+	if p != nil {
+		n = NullInt64From(*p)
+	}
+	return
+}
+
+// Ptr returns a pointer to the value, or nil if n is NULL.
+func (n NullInt64) Ptr() *int64 {
+	if !n.Valid {
+		return nil
+	}
+	v := n.Int64
+	return &v
+}
+
+// ValueOrZero returns the value, or the zero value if n is NULL.
+func (n NullInt64) ValueOrZero() int64 {
+	if !n.Valid {
+		return 0
+	}
+	return n.Int64
+}
+
+// NullInt32From creates a valid NullInt32.
+func NullInt32From(v int32) NullInt32 {
+	return NullInt32{sql.NullInt32{Int32: v, Valid: true}}
+}
+
+// NullInt32FromPtr creates a NullInt32 that is NULL if p is nil.
+func NullInt32FromPtr(p *int32) (n NullInt32) {
+	if p != nil {
+		n = NullInt32From(*p)
+	}
+	return
+}
+
+// Ptr returns a pointer to the value, or nil if n is NULL.
+func (n NullInt32) Ptr() *int32 {
+	if !n.Valid {
+		return nil
+	}
+	v := n.Int32
+	return &v
+}
+
+// ValueOrZero returns the value, or the zero value if n is NULL.
+func (n NullInt32) ValueOrZero() int32 {
+	if !n.Valid {
+		return 0
+	}
+	return n.Int32
+}
+
+// NullInt16From creates a valid NullInt16.
+func NullInt16From(v int16) NullInt16 {
+	return NullInt16{sql.NullInt16{Int16: v, Valid: true}}
+}
+
+// NullInt16FromPtr creates a NullInt16 that is NULL if p is nil.
+func NullInt16FromPtr(p *int16) (n NullInt16) {
+	if p != nil {
+		n = NullInt16From(*p)
+	}
+	return
+}
+
+// Ptr returns a pointer to the value, or nil if n is NULL.
+func (n NullInt16) Ptr() *int16 {
+	if !n.Valid {
+		return nil
+	}
+	v := n.Int16
+	return &v
+}
+
+// ValueOrZero returns the value, or the zero value if n is NULL.
+func (n NullInt16) ValueOrZero() int16 {
+	if !n.Valid {
+		return 0
+	}
+	return n.Int16
+}
+
+// NullByteFrom creates a valid NullByte.
+func NullByteFrom(v byte) NullByte {
+	return NullByte{sql.NullByte{Byte: v, Valid: true}}
+}
+
+// NullByteFromPtr creates a NullByte that is NULL if p is nil.
+func NullByteFromPtr(p *byte) (n NullByte) {
+	if p != nil {
+		n = NullByteFrom(*p)
+	}
+	return
+}
+
+// Ptr returns a pointer to the value, or nil if n is NULL.
+func (n NullByte) Ptr() *byte {
+	if !n.Valid {
+		return nil
+	}
+	v := n.Byte
+	return &v
+}
+
+// ValueOrZero returns the value, or the zero value if n is NULL.
+func (n NullByte) ValueOrZero() byte {
+	if !n.Valid {
+		return 0
+	}
+	return n.Byte
+}
+
+// NullUint64From creates a valid NullUint64.
+func NullUint64From(v uint64) NullUint64 {
+	return NullUint64{Uint64: v, Valid: true}
+}
+
+// NullUint64FromPtr creates a NullUint64 that is NULL if p is nil.
+func NullUint64FromPtr(p *uint64) (n NullUint64) {
+	if p != nil {
+		n = NullUint64From(*p)
+	}
+	return
+}
+
+// Ptr returns a pointer to the value, or nil if n is NULL.
+func (n NullUint64) Ptr() *uint64 {
+	if !n.Valid {
+		return nil
+	}
+	v := n.Uint64
+	return &v
+}
+
+// ValueOrZero returns the value, or the zero value if n is NULL.
+func (n NullUint64) ValueOrZero() uint64 {
+	if !n.Valid {
+		return 0
+	}
+	return n.Uint64
+}
+
+// NullUint32From creates a valid NullUint32.
+func NullUint32From(v uint32) NullUint32 {
+	return NullUint32{Uint32: v, Valid: true}
+}
+
+// NullUint32FromPtr creates a NullUint32 that is NULL if p is nil.
+func NullUint32FromPtr(p *uint32) (n NullUint32) {
+	if p != nil {
+		n = NullUint32From(*p)
+	}
+	return
+}
+
+// Ptr returns a pointer to the value, or nil if n is NULL.
+func (n NullUint32) Ptr() *uint32 {
+	if !n.Valid {
+		return nil
+	}
+	v := n.Uint32
+	return &v
+}
+
+// ValueOrZero returns the value, or the zero value if n is NULL.
+func (n NullUint32) ValueOrZero() uint32 {
+	if !n.Valid {
+		return 0
+	}
+	return n.Uint32
+}
+
+// NullBytesFrom creates a valid NullBytes.
+func NullBytesFrom(v []byte) NullBytes {
+	return NullBytes{Bytes: v, Valid: true}
+}
+
+// NullBytesFromPtr creates a NullBytes that is NULL if p is nil.
+func NullBytesFromPtr(p *[]byte) (n NullBytes) {
+	if p != nil {
+		n = NullBytesFrom(*p)
+	}
+	return
+}
+
+// Ptr returns a pointer to the value, or nil if n is NULL.
+func (n NullBytes) Ptr() *[]byte {
+	if !n.Valid {
+		return nil
+	}
+	v := n.Bytes
+	return &v
+}
+
+// ValueOrZero returns the value, or the zero value if n is NULL.
+func (n NullBytes) ValueOrZero() []byte {
+	if !n.Valid {
+		return nil
+	}
+	return n.Bytes
+}
+
+// NullFloat64From creates a valid NullFloat64.
+func NullFloat64From(v float64) NullFloat64 {
+	return NullFloat64{sql.NullFloat64{Float64: v, Valid: true}}
+}
+
+// NullFloat64FromPtr creates a NullFloat64 that is NULL if p is nil.
+func NullFloat64FromPtr(p *float64) (n NullFloat64) {
+	if p != nil {
+		n = NullFloat64From(*p)
+	}
+	return
+}
+
+// Ptr returns a pointer to the value, or nil if n is NULL.
+func (n NullFloat64) Ptr() *float64 {
+	if !n.Valid {
+		return nil
+	}
+	v := n.Float64
+	return &v
+}
+
+// ValueOrZero returns the value, or the zero value if n is NULL.
+func (n NullFloat64) ValueOrZero() float64 {
+	if !n.Valid {
+		return 0
+	}
+	return n.Float64
+}
+
+// NullTimeFrom creates a valid NullTime.
+func NullTimeFrom(v time.Time) NullTime {
+	return NullTime{Time: v, Valid: true}
+}
+
+// NullTimeFromPtr creates a NullTime that is NULL if p is nil.
+func NullTimeFromPtr(p *time.Time) (n NullTime) {
+	if p != nil {
+		n = NullTimeFrom(*p)
+	}
+	return
+}
+
+// Ptr returns a pointer to the value, or nil if n is NULL.
+func (n NullTime) Ptr() *time.Time {
+	if !n.Valid {
+		return nil
+	}
+	v := n.Time
+	return &v
+}
+
+// ValueOrZero returns the value, or the zero value if n is NULL.
+func (n NullTime) ValueOrZero() time.Time {
+	if !n.Valid {
+		return time.Time{}
+	}
+	return n.Time
+}
+
+// NullBoolFrom creates a valid NullBool.
+func NullBoolFrom(v bool) NullBool {
+	return NullBool{sql.NullBool{Bool: v, Valid: true}}
+}
+
+// NullBoolFromPtr creates a NullBool that is NULL if p is nil.
+func NullBoolFromPtr(p *bool) (n NullBool) {
+	if p != nil {
+		n = NullBoolFrom(*p)
+	}
+	return
+}
+
+// Ptr returns a pointer to the value, or nil if n is NULL.
+func (n NullBool) Ptr() *bool {
+	if !n.Valid {
+		return nil
+	}
+	v := n.Bool
+	return &v
+}
+
+// ValueOrZero returns the value, or the zero value if n is NULL.
+func (n NullBool) ValueOrZero() bool {
+	if !n.Valid {
+		return false
+	}
+	return n.Bool
+}
+
+// NewNullInt64 creates a NullInt64 with Scan().
+func NewNullInt64(v interface{}) (n NullInt64) {
+	n.Scan(v)
+	return
+}
+
+// NewNullInt32 creates a NullInt32 with Scan().
+func NewNullInt32(v interface{}) (n NullInt32) {
+	n.Scan(v)
+	return
+}
+
+// NewNullInt16 creates a NullInt16 with Scan().
+func NewNullInt16(v interface{}) (n NullInt16) {
+	n.Scan(v)
+	return
+}
+
+// NewNullByte creates a NullByte with Scan().
+func NewNullByte(v interface{}) (n NullByte) {
+	n.Scan(v)
+	return
+}
+
+// NewNullUint64 creates a NullUint64 with Scan().
+func NewNullUint64(v interface{}) (n NullUint64) {
+	n.Scan(v)
+	return
+}
+
+// NewNullUint32 creates a NullUint32 with Scan().
+func NewNullUint32(v interface{}) (n NullUint32) {
+	n.Scan(v)
+	return
+}
+
+// NewNullBytes creates a NullBytes with Scan().
+func NewNullBytes(v interface{}) (n NullBytes) {
 	n.Scan(v)
 	return
 }
@@ -207,11 +1278,11 @@ func (n *NullTime) Scan(value interface{}) error {
 		n.Time, n.Valid = v, true
 		return nil
 	case []byte:
-		n.Time, err = parseDateTime(string(v), time.UTC)
+		n.Time, err = n.parse(string(v))
 		n.Valid = (err == nil)
 		return err
 	case string:
-		n.Time, err = parseDateTime(v, time.UTC)
+		n.Time, err = n.parse(v)
 		n.Valid = (err == nil)
 		return err
 	}
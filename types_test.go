@@ -1,7 +1,10 @@
 package dbr
 
 import (
+	"database/sql"
+	"encoding"
 	"encoding/json"
+	"reflect"
 	"testing"
 	"time"
 
@@ -141,3 +144,298 @@ func TestNullTypesJSON(t *testing.T) {
 		require.Equal(t, test.in, test.out)
 	}
 }
+
+func TestNullGeneric(t *testing.T) {
+	type level int8
+
+	var n Null[level]
+	require.NoError(t, n.Scan(int64(3)))
+	require.Equal(t, NewNull(level(3)), n)
+
+	v, err := n.Value()
+	require.NoError(t, err)
+	require.Equal(t, int64(3), v)
+
+	require.NoError(t, n.Scan([]byte("7")))
+	require.Equal(t, level(7), n.V)
+	require.Error(t, n.Scan(int64(1000)))
+	require.False(t, n.Valid)
+
+	require.NoError(t, n.Scan(nil))
+	require.Equal(t, Null[level]{}, n)
+	v, err = n.Value()
+	require.NoError(t, err)
+	require.Nil(t, v)
+
+	var s Null[string]
+	require.NoError(t, s.Scan([]byte("wow")))
+	require.Equal(t, NewNull("wow"), s)
+
+	var out struct {
+		I Null[int32]  `json:"i"`
+		S Null[string] `json:"s"`
+	}
+	err = json.Unmarshal([]byte(`{"i":42,"s":null}`), &out)
+	require.NoError(t, err)
+	require.Equal(t, NewNull(int32(42)), out.I)
+	require.False(t, out.S.Valid)
+
+	b, err := json.Marshal(out)
+	require.NoError(t, err)
+	require.Equal(t, `{"i":42,"s":null}`, string(b))
+}
+
+func TestNullIntegerTypes(t *testing.T) {
+	n := NewNullUint64([]byte("18446744073709551615"))
+	require.True(t, n.Valid)
+	require.Equal(t, uint64(18446744073709551615), n.Uint64)
+	v, err := n.Value()
+	require.NoError(t, err)
+	require.Equal(t, uint64(18446744073709551615), v)
+
+	query, err := InterpolateForDialect("?", []interface{}{n}, dialect.MySQL)
+	require.NoError(t, err)
+	require.Equal(t, "18446744073709551615", query)
+
+	v, err = NewNullUint32(int64(7)).Value()
+	require.NoError(t, err)
+	require.Equal(t, int64(7), v)
+
+	require.False(t, NewNullUint32(int64(-1)).Valid)
+	require.False(t, NewNullInt16(int64(40000)).Valid)
+	require.Equal(t, NullInt32{sql.NullInt32{Int32: 5, Valid: true}}, NewNullInt32(int64(5)))
+
+	var out struct {
+		U64 NullUint64 `json:"u64"`
+		U32 NullUint32 `json:"u32"`
+		I32 NullInt32  `json:"i32"`
+		I16 NullInt16  `json:"i16"`
+		B   NullByte   `json:"b"`
+	}
+	in := `{"u64":18446744073709551615,"u32":4294967295,"i32":-2147483648,"i16":null,"b":255}`
+	err = json.Unmarshal([]byte(in), &out)
+	require.NoError(t, err)
+	require.Equal(t, uint64(18446744073709551615), out.U64.Uint64)
+	require.Equal(t, uint32(4294967295), out.U32.Uint32)
+	require.Equal(t, int32(-2147483648), out.I32.Int32)
+	require.False(t, out.I16.Valid)
+	require.Equal(t, byte(255), out.B.Byte)
+
+	b, err := json.Marshal(out)
+	require.NoError(t, err)
+	require.Equal(t, in, string(b))
+}
+
+func TestNullBytes(t *testing.T) {
+	buf := []byte("abc")
+	var n NullBytes
+	require.NoError(t, n.Scan(buf))
+	buf[0] = 'x'
+	require.Equal(t, []byte("abc"), n.Bytes)
+
+	require.NoError(t, n.Scan([]byte{}))
+	require.True(t, n.Valid)
+	require.NotNil(t, n.Bytes)
+	v, err := n.Value()
+	require.NoError(t, err)
+	require.Equal(t, []byte{}, v)
+
+	require.NoError(t, n.Scan(nil))
+	require.False(t, n.Valid)
+	v, err = n.Value()
+	require.NoError(t, err)
+	require.Nil(t, v)
+	require.Error(t, n.Scan(int64(1)))
+
+	query, err := InterpolateForDialect("?", []interface{}{NewNullBytes([]byte{0xff})}, dialect.MySQL)
+	require.NoError(t, err)
+	require.Equal(t, "0xff", query)
+
+	var out struct {
+		Data  NullBytes `json:"data"`
+		Empty NullBytes `json:"empty"`
+		Null  NullBytes `json:"null"`
+	}
+	in := `{"data":"AQI=","empty":"","null":null}`
+	err = json.Unmarshal([]byte(in), &out)
+	require.NoError(t, err)
+	require.Equal(t, []byte{1, 2}, out.Data.Bytes)
+	require.True(t, out.Empty.Valid)
+	require.False(t, out.Null.Valid)
+
+	b, err := json.Marshal(out)
+	require.NoError(t, err)
+	require.Equal(t, in, string(b))
+}
+
+func TestNullTypesText(t *testing.T) {
+	for _, test := range []struct {
+		value encoding.TextMarshaler
+		dest  encoding.TextUnmarshaler
+		text  string
+	}{
+		{value: NewNullString("wow"), dest: new(NullString), text: "wow"},
+		{value: NewNullInt64(-7), dest: new(NullInt64), text: "-7"},
+		{value: NewNullInt32(int64(32)), dest: new(NullInt32), text: "32"},
+		{value: NewNullInt16(int64(16)), dest: new(NullInt16), text: "16"},
+		{value: NewNullByte(int64(255)), dest: new(NullByte), text: "255"},
+		{value: NewNullUint64(uint64(18446744073709551615)), dest: new(NullUint64), text: "18446744073709551615"},
+		{value: NewNullUint32(int64(4294967295)), dest: new(NullUint32), text: "4294967295"},
+		{value: NewNullBytes([]byte{1, 2}), dest: new(NullBytes), text: "AQI="},
+		{value: NewNullFloat64(1.618), dest: new(NullFloat64), text: "1.618"},
+		{value: NewNullTime(time.Date(2009, 1, 3, 18, 15, 5, 0, time.UTC)), dest: new(NullTime), text: "2009-01-03T18:15:05Z"},
+		{value: NewNullBool(true), dest: new(NullBool), text: "true"},
+	} {
+		text, err := test.value.MarshalText()
+		require.NoError(t, err)
+		require.Equal(t, test.text, string(text))
+
+		require.NoError(t, test.dest.UnmarshalText(text))
+		require.Equal(t, test.value, reflect.ValueOf(test.dest).Elem().Interface())
+
+		require.NoError(t, test.dest.UnmarshalText(nil))
+		text, err = test.dest.(encoding.TextMarshaler).MarshalText()
+		require.NoError(t, err)
+		require.Empty(t, text)
+	}
+
+	var n NullTime
+	require.NoError(t, n.UnmarshalText([]byte("2009-01-03 18:15:05")))
+	require.Equal(t, time.Date(2009, 1, 3, 18, 15, 5, 0, time.UTC), n.Time)
+	require.Error(t, new(NullInt64).UnmarshalText([]byte("x")))
+}
+
+func TestNullTimeLocation(t *testing.T) {
+	loc := time.FixedZone("UTC+8", 8*60*60)
+
+	n := NullTimeIn(loc)
+	require.NoError(t, n.Scan([]byte("2009-01-03 18:15:05")))
+	require.True(t, n.Valid)
+	require.Equal(t, loc, n.Location)
+	require.True(t, time.Date(2009, 1, 3, 10, 15, 5, 0, time.UTC).Equal(n.Time))
+
+	// time.Time from the driver is kept
+	utc := time.Date(2009, 1, 3, 18, 15, 5, 0, time.UTC)
+	require.NoError(t, n.Scan(utc))
+	require.Equal(t, utc, n.Time)
+
+	defer func(orig *time.Location) { TimeLocation = orig }(TimeLocation)
+	TimeLocation = loc
+	n = NewNullTime("2009-01-03 18:15:05")
+	require.Nil(t, n.Location)
+	require.Equal(t, loc, n.Time.Location())
+	require.Equal(t, 18, n.Time.Hour())
+}
+
+func TestNullTimeDialectValue(t *testing.T) {
+	tm := time.Date(2009, 1, 3, 18, 15, 5, 123456789, time.UTC)
+	n := NullTimeFrom(tm)
+
+	v, err := n.Value()
+	require.NoError(t, err)
+	require.Equal(t, tm, v)
+
+	// truncated to the precision of the dialect like the interpolated times
+	for _, test := range []struct {
+		dialect Dialect
+		value   time.Time
+	}{
+		{dialect.MySQL, time.Date(2009, 1, 3, 18, 15, 5, 123456000, time.UTC)},
+		{dialect.MSSQL, time.Date(2009, 1, 3, 18, 15, 5, 123456700, time.UTC)},
+	} {
+		v, err = n.DialectValue(test.dialect)
+		require.NoError(t, err)
+		require.Equal(t, test.value, v)
+	}
+	v, err = NullTime{}.DialectValue(dialect.MySQL)
+	require.NoError(t, err)
+	require.Nil(t, v)
+
+	query, err := InterpolateForDialect("?", []interface{}{n}, dialect.MySQL)
+	require.NoError(t, err)
+	require.Equal(t, "'2009-01-03 18:15:05.123456'", query)
+}
+
+type yamlMarshaler interface {
+	MarshalYAML() (interface{}, error)
+}
+
+type yamlUnmarshaler interface {
+	UnmarshalYAML(func(interface{}) error) error
+}
+
+func TestNullTypesYAML(t *testing.T) {
+	for _, test := range []struct {
+		value yamlMarshaler
+		dest  yamlUnmarshaler
+		yaml  interface{}
+	}{
+		{value: NewNullString("wow"), dest: new(NullString), yaml: "wow"},
+		{value: NewNullInt64(-7), dest: new(NullInt64), yaml: int64(-7)},
+		{value: NewNullInt32(int64(32)), dest: new(NullInt32), yaml: int32(32)},
+		{value: NewNullUint64(uint64(18446744073709551615)), dest: new(NullUint64), yaml: uint64(18446744073709551615)},
+		{value: NewNullUint32(int64(4294967295)), dest: new(NullUint32), yaml: uint32(4294967295)},
+		{value: NewNullBytes([]byte{1, 2}), dest: new(NullBytes), yaml: "AQI="},
+		{value: NewNullFloat64(1.618), dest: new(NullFloat64), yaml: 1.618},
+		{value: NewNullBool(true), dest: new(NullBool), yaml: true},
+	} {
+		v, err := test.value.MarshalYAML()
+		require.NoError(t, err)
+		require.Equal(t, test.yaml, v)
+
+		// decode the marshaled value like yaml does
+		b, err := json.Marshal(v)
+		require.NoError(t, err)
+		require.NoError(t, test.dest.UnmarshalYAML(func(dest interface{}) error {
+			return json.Unmarshal(b, dest)
+		}))
+		require.Equal(t, test.value, reflect.ValueOf(test.dest).Elem().Interface())
+
+		require.NoError(t, test.dest.UnmarshalYAML(func(dest interface{}) error {
+			return json.Unmarshal(nullString, dest)
+		}))
+		v, err = test.dest.(yamlMarshaler).MarshalYAML()
+		require.NoError(t, err)
+		require.Nil(t, v)
+	}
+
+	var n NullTime
+	require.NoError(t, n.UnmarshalYAML(func(dest interface{}) error {
+		return json.Unmarshal([]byte(`"2009-01-03T18:15:05Z"`), dest)
+	}))
+	require.Equal(t, NewNullTime(time.Date(2009, 1, 3, 18, 15, 5, 0, time.UTC)), n)
+
+	var i16 NullInt16
+	require.Error(t, i16.UnmarshalYAML(func(dest interface{}) error {
+		return json.Unmarshal([]byte(`40000`), dest)
+	}))
+}
+
+func TestNullTypesAccessors(t *testing.T) {
+	s := "wow"
+	require.Equal(t, NewNullString("wow"), NullStringFrom("wow"))
+	require.Equal(t, NewNullString("wow"), NullStringFromPtr(&s))
+	require.False(t, NullStringFromPtr(nil).Valid)
+	require.Equal(t, &s, NullStringFrom("wow").Ptr())
+	require.Nil(t, NullString{}.Ptr())
+	require.Equal(t, "", NullString{}.ValueOrZero())
+
+	require.Equal(t, int64(7), NullInt64From(7).ValueOrZero())
+	require.Equal(t, NewNullInt32(int64(7)), NullInt32From(7))
+	require.Equal(t, NewNullUint64(int64(7)), NullUint64From(7))
+	require.Nil(t, NullBytes{}.ValueOrZero())
+	require.True(t, NullTime{Time: time.Now()}.ValueOrZero().IsZero())
+	require.Equal(t, true, *NullBoolFrom(true).Ptr())
+
+	d := NewDecimal(12345, 2)
+	require.Equal(t, NewNullDecimal("123.45"), NullDecimalFromPtr(&d))
+	require.Equal(t, time.Second, NullDurationFrom(time.Second).ValueOrZero())
+	require.Nil(t, NullUUID{}.Ptr())
+	require.Equal(t, JSONText(`1`), *NullJSONFrom(JSONText(`1`)).Ptr())
+
+	n := 3
+	require.Equal(t, NewNull(3), NullFromPtr(&n))
+	require.False(t, NullFromPtr[int](nil).Valid)
+	require.Equal(t, 3, *NewNull(3).Ptr())
+	require.Equal(t, 0, Null[int]{V: 3}.ValueOrZero())
+}
@@ -1,47 +1,218 @@
 package dbr
 
-type union struct {
-	builder []Builder
-	all     bool
+import (
+	"context"
+	"time"
+)
+
+// UnionStmt builds `... UNION ...`, `... INTERSECT ...` and `... EXCEPT ...`.
+type UnionStmt struct {
+	runner
+	EventReceiver
+	Dialect
+
+	Builder  []Builder
+	Operator string
+
+	Order       []Builder
+	LimitCount  int64
+	OffsetCount int64
+
+	timeout time.Duration
+	primary bool
 }
 
-// Union builds `... UNION ...`.
-func Union(builder ...Builder) interface {
-	Builder
-	As(string) Builder
-} {
-	return &union{
-		builder: builder,
+func newUnion(op string, builder []Builder) *UnionStmt {
+	u := &UnionStmt{
+		Builder:     builder,
+		Operator:    op,
+		LimitCount:  -1,
+		OffsetCount: -1,
 	}
+	// inherit the session from the first select, so the result can be loaded.
+	for _, b := range builder {
+		if sel, ok := b.(*SelectStmt); ok && sel.runner != nil {
+			u.runner = sel.runner
+			u.EventReceiver = sel.EventReceiver
+			u.Dialect = sel.Dialect
+			break
+		}
+	}
+	return u
+}
+
+// Union builds `... UNION ...`.
+func Union(builder ...Builder) *UnionStmt {
+	return newUnion("UNION", builder)
 }
 
 // UnionAll builds `... UNION ALL ...`.
-func UnionAll(builder ...Builder) interface {
-	Builder
-	As(string) Builder
-} {
-	return &union{
-		builder: builder,
-		all:     true,
-	}
+func UnionAll(builder ...Builder) *UnionStmt {
+	return newUnion("UNION ALL", builder)
+}
+
+// Intersect builds `... INTERSECT ...`.
+func Intersect(builder ...Builder) *UnionStmt {
+	return newUnion("INTERSECT", builder)
 }
 
-func (u *union) Build(d Dialect, buf Buffer) error {
-	for i, b := range u.builder {
+// Except builds `... EXCEPT ...`.
+func Except(builder ...Builder) *UnionStmt {
+	return newUnion("EXCEPT", builder)
+}
+
+func (u *UnionStmt) Build(d Dialect, buf Buffer) error {
+	limit := limitClause{
+		limit:  u.LimitCount,
+		offset: u.OffsetCount,
+	}
+	limit.wrap(d, buf)
+
+	for i, b := range u.Builder {
 		if i > 0 {
-			buf.WriteString(" UNION ")
-			if u.all {
-				buf.WriteString("ALL ")
-			}
+			buf.WriteString(" ")
+			buf.WriteString(u.Operator)
+			buf.WriteString(" ")
 		}
 		err := b.Build(d, buf)
 		if err != nil {
 			return err
 		}
 	}
-	return nil
+
+	if len(u.Order) > 0 {
+		buf.WriteString(" ORDER BY ")
+		for i, order := range u.Order {
+			if i > 0 {
+				buf.WriteString(", ")
+			}
+			err := order.Build(d, buf)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	var order Builder
+	if len(u.Order) == 0 {
+		order = Expr("(SELECT NULL)")
+	}
+	return limit.build(d, buf, order)
 }
 
-func (u *union) As(alias string) Builder {
+// As creates alias for the combined statement.
+func (u *UnionStmt) As(alias string) Builder {
 	return as(u, alias)
 }
+
+// OrderAsc sorts the combined result by col in ascending order.
+func (u *UnionStmt) OrderAsc(col string) *UnionStmt {
+	u.Order = append(u.Order, order(col, asc))
+	return u
+}
+
+// OrderDesc sorts the combined result by col in descending order.
+func (u *UnionStmt) OrderDesc(col string) *UnionStmt {
+	u.Order = append(u.Order, order(col, desc))
+	return u
+}
+
+// OrderBy specifies columns for ordering the combined result.
+// col can be Builder or string.
+func (u *UnionStmt) OrderBy(col interface{}) *UnionStmt {
+	switch col := col.(type) {
+	case string:
+		u.Order = append(u.Order, Expr(col))
+	case Builder:
+		u.Order = append(u.Order, col)
+	}
+	return u
+}
+
+func (u *UnionStmt) Limit(n uint64) *UnionStmt {
+	u.LimitCount = int64(n)
+	return u
+}
+
+func (u *UnionStmt) Offset(n uint64) *UnionStmt {
+	u.OffsetCount = int64(n)
+	return u
+}
+
+// Timeout cancels the statement after d like SelectStmt.Timeout.
+func (u *UnionStmt) Timeout(d time.Duration) *UnionStmt {
+	u.timeout = d
+	return u
+}
+
+func (u *UnionStmt) statementTimeout() time.Duration {
+	return u.timeout
+}
+
+// OnPrimary reads from the primary instead of the replicas.
+func (u *UnionStmt) OnPrimary() *UnionStmt {
+	u.primary = true
+	return u
+}
+
+func (u *UnionStmt) LoadOneContext(ctx context.Context, value interface{}) error {
+	count, err := query(ctx, u.runner, u.EventReceiver, u, u.Dialect, value)
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// LoadOne loads SQL result into go variable that is not a slice.
+// Unlike Load, it returns ErrNotFound if the SQL result row count is 0.
+func (u *UnionStmt) LoadOne(value interface{}) error {
+	return u.LoadOneContext(context.Background(), value)
+}
+
+func (u *UnionStmt) LoadContext(ctx context.Context, value interface{}) (int, error) {
+	return query(ctx, u.runner, u.EventReceiver, u, u.Dialect, value)
+}
+
+// Load loads multi-row SQL result into a slice of go variables.
+//
+// The session is taken from the first SelectStmt created by Session or Tx.
+func (u *UnionStmt) Load(value interface{}) (int, error) {
+	return u.LoadContext(context.Background(), value)
+}
+
+// LoadMaps loads each row into a map of column name to value like SelectStmt.LoadMaps.
+func (u *UnionStmt) LoadMaps() ([]map[string]interface{}, error) {
+	return u.LoadMapsContext(context.Background())
+}
+
+// LoadMapsContext is like LoadMaps with context.
+func (u *UnionStmt) LoadMapsContext(ctx context.Context) ([]map[string]interface{}, error) {
+	return queryMaps(ctx, u.runner, u.EventReceiver, u, u.Dialect)
+}
+
+// Iterate executes the query and returns the Iterator, or any error encountered.
+func (u *UnionStmt) Iterate() (Iterator, error) {
+	return u.IterateContext(context.Background())
+}
+
+// IterateContext executes the query and returns the Iterator, or any error encountered.
+func (u *UnionStmt) IterateContext(ctx context.Context) (Iterator, error) {
+	return iterate(ctx, u.runner, u.EventReceiver, u, u.Dialect)
+}
+
+// ForEach scans each row into dest and calls fn like SelectStmt.ForEach.
+func (u *UnionStmt) ForEach(dest interface{}, fn func() error) error {
+	return u.ForEachContext(context.Background(), dest, fn)
+}
+
+// ForEachContext is like ForEach with context.
+func (u *UnionStmt) ForEachContext(ctx context.Context, dest interface{}, fn func() error) error {
+	iter, err := u.IterateContext(ctx)
+	if err != nil {
+		return err
+	}
+	return forEach(iter, dest, fn)
+}
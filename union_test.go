@@ -0,0 +1,60 @@
+package dbr
+
+import (
+	"testing"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/jiyeyuran/dbr/v2/dialect"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnionStmt(t *testing.T) {
+	for _, test := range []struct {
+		builder Builder
+		dialect Dialect
+		query   string
+	}{
+		{
+			builder: Union(Select("a").From("t1"), Select("a").From("t2")),
+			dialect: dialect.MySQL,
+			query:   "SELECT `a` FROM `t1` UNION SELECT `a` FROM `t2`",
+		},
+		{
+			builder: UnionAll(Select("a").From("t1"), Select("a").From("t2")).OrderDesc("a").Limit(2).Offset(1),
+			dialect: dialect.MySQL,
+			query:   "SELECT `a` FROM `t1` UNION ALL SELECT `a` FROM `t2` ORDER BY `a` DESC LIMIT 2 OFFSET 1",
+		},
+		{
+			builder: Intersect(Select("a").From("t1"), Select("a").From("t2")).OrderAsc("a"),
+			dialect: dialect.PostgreSQL,
+			query:   `SELECT "a" FROM "t1" INTERSECT SELECT "a" FROM "t2" ORDER BY "a" ASC`,
+		},
+		{
+			builder: Except(Select("a").From("t1"), Select("a").From("t2")).Limit(5),
+			dialect: dialect.MSSQL,
+			query:   "SELECT [a] FROM [t1] EXCEPT SELECT [a] FROM [t2] ORDER BY (SELECT NULL) OFFSET 0 ROWS FETCH FIRST 5 ROWS ONLY",
+		},
+	} {
+		buf := NewBuffer()
+		err := test.builder.Build(test.dialect, buf)
+		require.NoError(t, err)
+		require.Equal(t, test.query, buf.String())
+	}
+}
+
+func TestUnionStmtLoad(t *testing.T) {
+	sess, mock := newMockSession(t, dialect.MySQL)
+
+	mock.ExpectQuery("SELECT `name` FROM `a` UNION SELECT `name` FROM `b` ORDER BY `name` ASC LIMIT 2").
+		WillReturnRows(sqlmock.NewRows([]string{"name"}).AddRow("x").AddRow("y"))
+
+	var names []string
+	count, err := Union(
+		sess.Select("name").From("a"),
+		sess.Select("name").From("b"),
+	).OrderAsc("name").Limit(2).Load(&names)
+	require.NoError(t, err)
+	require.Equal(t, 2, count)
+	require.Equal(t, []string{"x", "y"}, names)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
@@ -2,8 +2,10 @@ package dbr
 
 import (
 	"context"
-	"database/sql"
-	"strconv"
+	"reflect"
+	"time"
+
+	"github.com/jiyeyuran/dbr/v2/dialect"
 )
 
 // UpdateStmt builds `UPDATE ...`.
@@ -15,11 +17,17 @@ type UpdateStmt struct {
 	raw
 
 	Table        string
+	IndexHint    []Builder
 	Value        map[string]interface{}
 	WhereCond    []Builder
 	ReturnColumn []string
+	Order        []Builder
 	LimitCount   int64
 	comments     Comments
+	tags         CommentTags
+	joins        []*joinClause
+
+	timeout time.Duration
 }
 
 type UpdateBuilder = UpdateStmt
@@ -43,9 +51,23 @@ func (b *UpdateStmt) Build(d Dialect, buf Buffer) error {
 	}
 
 	buf.WriteString("UPDATE ")
-	buf.WriteString(d.QuoteIdent(b.Table))
+	buf.WriteString(quoteTable(d, b.runner, b.Table))
+	err = buildIndexHints(d, buf, b.IndexHint)
+	if err != nil {
+		return err
+	}
+	joinStyle := dialect.CapabilitiesOf(d).UpdateJoinStyle
+	if joinStyle == dialect.JoinTable {
+		for _, j := range b.joins {
+			err := j.Build(d, buf)
+			if err != nil {
+				return err
+			}
+		}
+	}
 	buf.WriteString(" SET ")
 
+	timeOpts := timeOptionsOf(b.runner)
 	i := 0
 	for col, v := range b.Value {
 		if i > 0 {
@@ -55,32 +77,59 @@ func (b *UpdateStmt) Build(d Dialect, buf Buffer) error {
 		buf.WriteString(" = ")
 		buf.WriteString(placeholder)
 
-		buf.WriteValue(v)
+		buf.WriteValue(timeOpts.written(v))
 
 		i++
 	}
 
-	if len(b.WhereCond) > 0 {
+	buildOutput(d, buf, "INSERTED", b.ReturnColumn)
+
+	whereCond := b.WhereCond
+	if len(b.joins) > 0 && joinStyle != dialect.JoinTable {
+		if joinStyle == dialect.NoJoin {
+			return errDialectNotSupported("UPDATE with JOIN")
+		}
+		buf.WriteString(" FROM ")
+		if joinStyle == dialect.JoinFrom {
+			buf.WriteString(quoteTable(d, b.runner, b.Table))
+			for _, j := range b.joins {
+				err := j.Build(d, buf)
+				if err != nil {
+					return err
+				}
+			}
+		} else {
+			// the join conditions are moved to WHERE
+			var joinCond []Builder
+			for i, j := range b.joins {
+				if i > 0 {
+					buf.WriteString(", ")
+				}
+				j.buildTable(d, buf)
+				if cond := j.cond(); cond != nil {
+					joinCond = append(joinCond, cond)
+				}
+			}
+			whereCond = append(joinCond, whereCond...)
+		}
+	}
+
+	if len(whereCond) > 0 {
 		buf.WriteString(" WHERE ")
-		err := And(b.WhereCond...).Build(d, buf)
+		err := And(whereCond...).Build(d, buf)
 		if err != nil {
 			return err
 		}
 	}
 
-	if len(b.ReturnColumn) > 0 {
-		buf.WriteString(" RETURNING ")
-		for i, col := range b.ReturnColumn {
-			if i > 0 {
-				buf.WriteString(",")
-			}
-			buf.WriteString(d.QuoteIdent(col))
-		}
+	err = buildReturning(d, buf, b.ReturnColumn)
+	if err != nil {
+		return err
 	}
 
-	if b.LimitCount >= 0 {
-		buf.WriteString(" LIMIT ")
-		buf.WriteString(strconv.FormatInt(b.LimitCount, 10))
+	err = buildOrderLimit(d, buf, b.Order, b.LimitCount)
+	if err != nil {
+		return err
 	}
 
 	return nil
@@ -113,6 +162,123 @@ func (tx *Tx) Update(table string) *UpdateStmt {
 	return b
 }
 
+// UpdateRecord creates an UpdateStmt that only sets the columns
+// changed from oldRecord to newRecord, which are usually structs of the same
+// type. The fields of embedded structs are compared too.
+//
+// Fields with `readonly` tag option are never set.
+// Fields with `pk` tag option are not set either, but compared with
+// the values of oldRecord in WHERE.
+//
+// If nothing is changed, Exec returns ErrColumnNotSpecified.
+func UpdateRecord(table string, oldRecord, newRecord interface{}) *UpdateStmt {
+	return updateRecord(Update(table), newTagStore(), oldRecord, newRecord)
+}
+
+func updateRecord(b *UpdateStmt, s *tagStore, oldRecord, newRecord interface{}) *UpdateStmt {
+	oldValue := reflect.Indirect(reflect.ValueOf(oldRecord))
+	newValue := reflect.Indirect(reflect.ValueOf(newRecord))
+	if newValue.Kind() != reflect.Struct {
+		return b
+	}
+
+	column, opts := s.recordColumns(newValue, nil, nil)
+	found := make([]interface{}, len(column))
+	s.findValueByName(newValue, column, found, false)
+	oldFound := make([]interface{}, len(column))
+	s.findValueByName(oldValue, column, oldFound, false)
+	for i, col := range column {
+		nv, ok := found[i].(reflect.Value)
+		if !ok || opts[i].Contains("readonly") {
+			continue
+		}
+		v := nv.Interface()
+		ov, hasOld := oldFound[i].(reflect.Value)
+		if opts[i].Contains("pk") {
+			if hasOld {
+				v = ov.Interface()
+			}
+			b.Where(Eq(col, v))
+			continue
+		}
+		if hasOld && valueEqual(ov.Interface(), v) {
+			continue
+		}
+		b.Set(col, v)
+	}
+	return b
+}
+
+// recordColumns appends the columns of struct value v with their tag options,
+// including the fields of embedded structs.
+func (s *tagStore) recordColumns(v reflect.Value, column []string, opts []tagOptions) ([]string, []tagOptions) {
+	l := s.get(v.Type())
+	o := s.options(v.Type())
+	for i := 0; i < v.NumField(); i++ {
+		if l[i] == "" {
+			continue
+		}
+		field := v.Field(i)
+		if v.Type().Field(i).Anonymous && !isValueType(field.Type()) {
+			if field.Kind() == reflect.Ptr {
+				if field.IsNil() {
+					continue
+				}
+				field = field.Elem()
+			}
+			if field.Kind() == reflect.Struct {
+				column, opts = s.recordColumns(field, column, opts)
+				continue
+			}
+		}
+		if composite, _ := fieldColumns(field, l[i], o[i], false); composite != nil {
+			for _, col := range composite {
+				column = append(column, col)
+				opts = append(opts, o[i])
+			}
+			continue
+		}
+		column = append(column, l[i])
+		opts = append(opts, o[i])
+	}
+	return column, opts
+}
+
+// UpdateRecord creates an UpdateStmt that only sets the changed columns.
+func (sess *Session) UpdateRecord(table string, oldRecord, newRecord interface{}) *UpdateStmt {
+	b := updateRecord(Update(table), newTagStoreFor(sess), oldRecord, newRecord)
+	b.runner = sess
+	b.EventReceiver = sess.EventReceiver
+	b.Dialect = sess.Dialect
+	return b
+}
+
+// UpdateRecord creates an UpdateStmt that only sets the changed columns.
+func (tx *Tx) UpdateRecord(table string, oldRecord, newRecord interface{}) *UpdateStmt {
+	b := updateRecord(Update(table), newTagStoreFor(tx), oldRecord, newRecord)
+	b.runner = tx
+	b.EventReceiver = tx.EventReceiver
+	b.Dialect = tx.Dialect
+	return b
+}
+
+func valueEqual(a, b interface{}) bool {
+	// compare pointer fields by the values
+	av, bv := reflect.ValueOf(a), reflect.ValueOf(b)
+	if av.Kind() == reflect.Ptr && bv.Kind() == reflect.Ptr && av.Type() == bv.Type() {
+		if av.IsNil() || bv.IsNil() {
+			return av.IsNil() == bv.IsNil()
+		}
+		return valueEqual(av.Elem().Interface(), bv.Elem().Interface())
+	}
+	if t, ok := a.(time.Time); ok {
+		if u, ok := b.(time.Time); ok {
+			return t.Equal(u)
+		}
+	}
+	return reflect.DeepEqual(a, b)
+}
+
 // UpdateBySql creates an UpdateStmt with raw query.
 func UpdateBySql(query string, value ...interface{}) *UpdateStmt {
 	return &UpdateStmt{
@@ -155,13 +321,24 @@ func (b *UpdateStmt) Where(query interface{}, value ...interface{}) *UpdateStmt
 	return b
 }
 
-// Returning specifies the returning columns for postgres.
+// Join updates the table joined with another table.
+// It builds `UPDATE a JOIN b ON ... SET ...` in mysql,
+// and `UPDATE a SET ... FROM b WHERE ...` in postgres and sqlite.
+// table can be Builder or string. on can be Builder or string.
+func (b *UpdateStmt) Join(table, on interface{}) *UpdateStmt {
+	b.joins = append(b.joins, &joinClause{table: joinTable(b.runner, table), on: on})
+	return b
+}
+
+// Returning specifies the returning columns for postgres/sqlite/mssql.
+// The updated rows can be loaded with Load.
 func (b *UpdateStmt) Returning(column ...string) *UpdateStmt {
 	b.ReturnColumn = column
 	return b
 }
 
 // Set updates column with value.
+// value can be Builder like SelectStmt, which is built as a subquery.
 func (b *UpdateStmt) Set(column string, value interface{}) *UpdateStmt {
 	b.Value[column] = value
 	return b
@@ -175,6 +352,12 @@ func (b *UpdateStmt) SetMap(m map[string]interface{}) *UpdateStmt {
 	return b
 }
 
+// SetExpr updates column with a raw expression, like SetExpr("count", "count * ?", 2).
+func (b *UpdateStmt) SetExpr(column, query string, value ...interface{}) *UpdateStmt {
+	b.Value[column] = Expr(query, value...)
+	return b
+}
+
 // IncrBy increases column by value
 func (b *UpdateStmt) IncrBy(column string, value interface{}) *UpdateStmt {
 	b.Value[column] = Expr("? + ?", I(column), value)
@@ -187,6 +370,52 @@ func (b *UpdateStmt) DecrBy(column string, value interface{}) *UpdateStmt {
 	return b
 }
 
+// UseIndex suggests indexes to use for the table. It is only rendered for MySQL.
+func (b *UpdateStmt) UseIndex(index ...string) *UpdateStmt {
+	b.IndexHint = append(b.IndexHint, indexHint("USE", index))
+	return b
+}
+
+// ForceIndex forces indexes to use for the table. It is only rendered for MySQL.
+func (b *UpdateStmt) ForceIndex(index ...string) *UpdateStmt {
+	b.IndexHint = append(b.IndexHint, indexHint("FORCE", index))
+	return b
+}
+
+// IgnoreIndex prevents indexes from being used for the table. It is only rendered for MySQL.
+func (b *UpdateStmt) IgnoreIndex(index ...string) *UpdateStmt {
+	b.IndexHint = append(b.IndexHint, indexHint("IGNORE", index))
+	return b
+}
+
+// OrderAsc sorts the rows to update by col in ascending order.
+// It is only supported by mysql and sqlite.
+func (b *UpdateStmt) OrderAsc(col string) *UpdateStmt {
+	b.Order = append(b.Order, order(col, asc))
+	return b
+}
+
+// OrderDesc sorts the rows to update by col in descending order.
+// It is only supported by mysql and sqlite.
+func (b *UpdateStmt) OrderDesc(col string) *UpdateStmt {
+	b.Order = append(b.Order, order(col, desc))
+	return b
+}
+
+// OrderBy specifies columns for ordering the rows to update.
+// col can be Builder or string. It is only supported by mysql and sqlite.
+func (b *UpdateStmt) OrderBy(col interface{}) *UpdateStmt {
+	switch col := col.(type) {
+	case string:
+		b.Order = append(b.Order, Expr(col))
+	case Builder:
+		b.Order = append(b.Order, col)
+	}
+	return b
+}
+
+// Limit limits the number of rows to update.
+// It is only supported by mysql and sqlite.
 func (b *UpdateStmt) Limit(n uint64) *UpdateStmt {
 	b.LimitCount = int64(n)
 	return b
@@ -197,11 +426,31 @@ func (b *UpdateStmt) Comment(comment string) *UpdateStmt {
 	return b
 }
 
-func (b *UpdateStmt) Exec() (sql.Result, error) {
+// CommentTag adds a tag to the comment appended to the query like SelectStmt.CommentTag.
+func (b *UpdateStmt) CommentTag(key, value string) *UpdateStmt {
+	b.tags = b.tags.Add(key, value)
+	return b
+}
+
+func (b *UpdateStmt) commentTags(context.Context) map[string]string {
+	return b.tags
+}
+
+// Timeout cancels the statement after d like SelectStmt.Timeout.
+func (b *UpdateStmt) Timeout(d time.Duration) *UpdateStmt {
+	b.timeout = d
+	return b
+}
+
+func (b *UpdateStmt) statementTimeout() time.Duration {
+	return b.timeout
+}
+
+func (b *UpdateStmt) Exec() (*Result, error) {
 	return b.ExecContext(context.Background())
 }
 
-func (b *UpdateStmt) ExecContext(ctx context.Context) (sql.Result, error) {
+func (b *UpdateStmt) ExecContext(ctx context.Context) (*Result, error) {
 	return exec(ctx, b.runner, b.EventReceiver, b, b.Dialect)
 }
 
@@ -1,7 +1,9 @@
 package dbr
 
 import (
+	"errors"
 	"testing"
+	"time"
 
 	"github.com/jiyeyuran/dbr/v2/dialect"
 	"github.com/stretchr/testify/require"
@@ -60,3 +62,169 @@ func TestUpdateIncrBy(t *testing.T) {
 
 	require.Equal(t, "UPDATE `table` SET `a` = `a` + 1 WHERE (`b` = 2)", sqlstr)
 }
+
+func TestUpdateSetExpr(t *testing.T) {
+	builder := Update("table").SetExpr("a", "a * ? + ?", 2, 1).DecrBy("b", 3).Where(Eq("c", 4))
+
+	query, err := InterpolateForDialect("?", []interface{}{builder}, dialect.MySQL)
+	require.NoError(t, err)
+	require.Contains(t, query, "`a` = a * 2 + 1")
+	require.Contains(t, query, "`b` = `b` - 3")
+	require.Contains(t, query, "WHERE (`c` = 4)")
+}
+
+func TestUpdateIndexHint(t *testing.T) {
+	buf := NewBuffer()
+	builder := Update("table").ForceIndex("idx").Set("a", 1).Where(Eq("b", 2))
+	err := builder.Build(dialect.MySQL, buf)
+	require.NoError(t, err)
+	require.Equal(t, "UPDATE `table` FORCE INDEX (`idx`) SET `a` = ? WHERE (`b` = ?)", buf.String())
+}
+
+func TestUpdateJoin(t *testing.T) {
+	builder := Update("orders").
+		Join("users", "users.id = orders.user_id").
+		Set("status", "vip").
+		Where(Eq("users.level", 3))
+
+	for _, test := range []struct {
+		dialect Dialect
+		query   string
+	}{
+		{
+			dialect: dialect.MySQL,
+			query:   "UPDATE `orders` JOIN `users` ON users.id = orders.user_id SET `status` = ? WHERE (`users`.`level` = ?)",
+		},
+		{
+			dialect: dialect.PostgreSQL,
+			query:   `UPDATE "orders" SET "status" = ? FROM "users" WHERE (users.id = orders.user_id) AND ("users"."level" = ?)`,
+		},
+		{
+			dialect: dialect.SQLite3,
+			query:   `UPDATE "orders" SET "status" = ? FROM "users" WHERE (users.id = orders.user_id) AND ("users"."level" = ?)`,
+		},
+		{
+			dialect: dialect.MSSQL,
+			query:   `UPDATE [orders] SET [status] = ? FROM [orders] JOIN [users] ON users.id = orders.user_id WHERE ([users].[level] = ?)`,
+		},
+	} {
+		buf := NewBuffer()
+		err := builder.Build(test.dialect, buf)
+		require.NoError(t, err)
+		require.Equal(t, test.query, buf.String())
+		require.Equal(t, []interface{}{"vip", 3}, buf.Value())
+	}
+}
+
+func TestUpdateSetSubquery(t *testing.T) {
+	builder := Update("orders").
+		Set("total", Select("SUM(price)").From("items").Where("items.order_id = orders.id AND items.state = ?", "paid")).
+		Where(Eq("id", 1))
+
+	query, err := InterpolateForDialect("?", []interface{}{builder}, dialect.MySQL)
+	require.NoError(t, err)
+	require.Equal(t, "UPDATE `orders` SET `total` = (SELECT SUM(price) FROM `items` WHERE (items.order_id = orders.id AND items.state = 'paid')) WHERE (`id` = 1)", query)
+}
+
+func TestUpdateReturning(t *testing.T) {
+	builder := Update("account").Set("balance", 0).Where(Eq("id", 1)).Returning("id", "balance")
+
+	buf := NewBuffer()
+	err := builder.Build(dialect.SQLite3, buf)
+	require.NoError(t, err)
+	require.Equal(t, `UPDATE "account" SET "balance" = ? WHERE ("id" = ?) RETURNING "id","balance"`, buf.String())
+
+	buf = NewBuffer()
+	err = builder.Build(dialect.MSSQL, buf)
+	require.NoError(t, err)
+	require.Equal(t, `UPDATE [account] SET [balance] = ? OUTPUT INSERTED.[id],INSERTED.[balance] WHERE ([id] = ?)`, buf.String())
+}
+
+func TestUpdateOrderLimit(t *testing.T) {
+	builder := Update("job").Set("state", "queued").Where(Eq("state", "new")).OrderDesc("priority").Limit(10)
+
+	buf := NewBuffer()
+	err := builder.Build(dialect.MySQL, buf)
+	require.NoError(t, err)
+	require.Equal(t, "UPDATE `job` SET `state` = ? WHERE (`state` = ?) ORDER BY `priority` DESC LIMIT 10", buf.String())
+
+	err = builder.Build(dialect.MSSQL, NewBuffer())
+	require.True(t, errors.Is(err, ErrDialectNotSupported))
+}
+
+func TestUpdateRecord(t *testing.T) {
+	type user struct {
+		ID        int64
+		Name      string
+		Email     string
+		Tags      []string
+		UpdatedAt time.Time
+		secret    string
+	}
+	now := time.Now()
+	old := user{ID: 1, Name: "alice", Email: "a@b.c", Tags: []string{"x"}, UpdatedAt: now}
+	new := old
+	new.Email = "alice@b.c"
+	new.Tags = []string{"x"}
+	new.UpdatedAt = now.UTC()
+	new.secret = "s"
+
+	builder := UpdateRecord("user", &old, &new).Where(Eq("id", old.ID))
+	buf := NewBuffer()
+	err := builder.Build(dialect.MySQL, buf)
+	require.NoError(t, err)
+	require.Equal(t, "UPDATE `user` SET `email` = ? WHERE (`id` = ?)", buf.String())
+	require.Equal(t, []interface{}{"alice@b.c", int64(1)}, buf.Value())
+
+	err = UpdateRecord("user", &old, &old).Build(dialect.MySQL, NewBuffer())
+	require.Equal(t, ErrColumnNotSpecified, err)
+}
+
+func TestUpdateVariant(t *testing.T) {
+	builder := Update("raw").Set("payload", Variant(map[string]interface{}{"it's": `a\b`})).Where(Eq("id", 1))
+
+	query, err := InterpolateForDialect("?", []interface{}{builder}, dialect.Snowflake)
+	require.NoError(t, err)
+	require.Equal(t, `UPDATE "raw" SET "payload" = PARSE_JSON('{"it''s":"a\\\\b"}') WHERE ("id" = 1)`, query)
+
+	_, err = InterpolateForDialect("?", []interface{}{builder}, dialect.MySQL)
+	require.True(t, errors.Is(err, ErrDialectNotSupported))
+}
+
+func TestUpdateRecordTagOptions(t *testing.T) {
+	type user struct {
+		Code      string `db:"code,pk"`
+		Name      string
+		CreatedAt string `db:"created_at,readonly"`
+	}
+	old := user{Code: "a1", Name: "alice", CreatedAt: "yesterday"}
+	new := user{Code: "a1", Name: "bob", CreatedAt: "today"}
+
+	query, err := InterpolateForDialect("?", []interface{}{UpdateRecord("user", &old, &new)}, dialect.MySQL)
+	require.NoError(t, err)
+	require.Equal(t, "UPDATE `user` SET `name` = 'bob' WHERE (`code` = 'a1')", query)
+}
+
+func TestUpdateRecordEmbedded(t *testing.T) {
+	type base struct {
+		ID        int64 `db:"id,pk"`
+		UpdatedAt string
+	}
+	type user struct {
+		Name string
+		base
+		Email string
+	}
+	old := user{Name: "alice", base: base{ID: 1, UpdatedAt: "yesterday"}, Email: "a@b.c"}
+	changed := old
+	changed.UpdatedAt = "today"
+	changed.Email = "alice@b.c"
+
+	builder := UpdateRecord("user", &old, &changed)
+	require.Equal(t, map[string]interface{}{"updated_at": "today", "email": "alice@b.c"}, builder.Value)
+
+	changed.Email = old.Email
+	query, err := InterpolateForDialect("?", []interface{}{UpdateRecord("user", &old, &changed)}, dialect.MySQL)
+	require.NoError(t, err)
+	require.Equal(t, "UPDATE `user` SET `updated_at` = 'today' WHERE (`id` = 1)", query)
+}
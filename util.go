@@ -6,8 +6,37 @@ import (
 	"strings"
 )
 
+// NameMapping maps the names of struct fields without tag to columns,
+// unless Connection.NameMapper is set. It must be set before loading,
+// since the mappings of columns to fields are cached.
 var NameMapping = camelCaseToSnakeCase
 
+// SnakeCase maps field names like "UserID" to "user_id", which is the default.
+func SnakeCase(name string) string {
+	return camelCaseToSnakeCase(name)
+}
+
+// CamelCase maps field names like "UserID" to "userID" and "HTTPServer" to "httpServer".
+func CamelCase(name string) string {
+	n := 0
+	for n < len(name) && isUpper(name[n]) {
+		n++
+	}
+	if n > 1 && n < len(name) && isLower(name[n]) {
+		// keep the first letter of the next word
+		n--
+	}
+	return strings.ToLower(name[:n]) + name[n:]
+}
+
+// LowerCase maps field names like "UserID" to "userid".
+func LowerCase(name string) string {
+	return strings.ToLower(name)
+}
+
+// defaultTagName is the struct tag unless Connection.TagName is set.
+const defaultTagName = "db"
+
 func isUpper(b byte) bool {
 	return 'A' <= b && b <= 'Z'
 }
@@ -44,57 +73,228 @@ func camelCaseToSnakeCase(name string) string {
 }
 
 var (
-	typeValuer = reflect.TypeOf((*driver.Valuer)(nil)).Elem()
+	typeValuer         = reflect.TypeOf((*driver.Valuer)(nil)).Elem()
+	typeColumnsScanner = reflect.TypeOf((*ColumnsScanner)(nil)).Elem()
 )
 
+// tagOptions are the options after the column name in the tag, like `db:"name,omitempty"`.
+type tagOptions string
+
+func parseTag(tag string) (string, tagOptions) {
+	if i := strings.Index(tag, ","); i != -1 {
+		return tag[:i], tagOptions(tag[i+1:])
+	}
+	return tag, ""
+}
+
+// Contains reports whether the option is set.
+func (o tagOptions) Contains(option string) bool {
+	s := string(o)
+	for s != "" {
+		var next string
+		if i := strings.Index(s, ","); i >= 0 {
+			s, next = s[:i], s[i+1:]
+		}
+		if s == option {
+			return true
+		}
+		s = next
+	}
+	return false
+}
+
+// prefix returns the column prefix of a nested struct field,
+// like "author_" for `db:"author,prefix"` or `db:"writer,prefix=author_"`.
+func (o tagOptions) prefix(tag string) string {
+	s := string(o)
+	for s != "" {
+		var next string
+		if i := strings.Index(s, ","); i >= 0 {
+			s, next = s[:i], s[i+1:]
+		}
+		if s == "prefix" {
+			return tag + "_"
+		}
+		if strings.HasPrefix(s, "prefix=") {
+			return s[len("prefix="):]
+		}
+		s = next
+	}
+	return ""
+}
+
+// isValueType reports whether t is a single column, because it or its pointer
+// implements Scanner or Valuer, so that its fields are not columns.
+func isValueType(t reflect.Type) bool {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	pt := reflect.PtrTo(t)
+	return t == typeTime || pt.Implements(typeScanner) || pt.Implements(typeValuer)
+}
+
+// embedsScanner reports whether struct t implements Scanner only because of
+// an embedded field, while it has other fields to load, like a model
+// embedding NullTime. It is loaded by the fields instead.
+func embedsScanner(t reflect.Type) bool {
+	if t.Kind() != reflect.Struct || t.NumField() < 2 {
+		return false
+	}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.Anonymous && reflect.PtrTo(field.Type).Implements(typeScanner) {
+			return true
+		}
+	}
+	return false
+}
+
+// isScalarPtr reports whether t is a pointer to a non-struct type or time.Time,
+// which is loaded as nil for NULL like the pointer fields of structs.
+func isScalarPtr(t reflect.Type) bool {
+	return t.Kind() == reflect.Ptr && (t.Elem().Kind() != reflect.Struct || t.Elem() == typeTime)
+}
+
 type tagStore struct {
-	m map[reflect.Type][]string
+	m    map[reflect.Type][]string
+	opts map[reflect.Type][]tagOptions
+	// nameMapping is NameMapping if nil
+	nameMapping func(string) string
+	// tagName is defaultTagName if empty
+	tagName string
+	plans   map[localPlanKey]*scanPlan
+	// strict returns UnmappedColumnsError from fillDummy
+	strict bool
+	// time is applied to the loaded times if not nil
+	time *TimeOptions
 }
 
 func newTagStore() *tagStore {
 	return &tagStore{
-		m: make(map[reflect.Type][]string),
+		m:    make(map[reflect.Type][]string),
+		opts: make(map[reflect.Type][]tagOptions),
 	}
 }
 
+// nameMapper is implemented by Session and Tx for Connection.NameMapper.
+type nameMapper interface {
+	nameMapper() func(string) string
+}
+
+// tagNamer is implemented by Session and Tx for Connection.TagName.
+type tagNamer interface {
+	tagName() string
+}
+
+// strictLoader is implemented by Session and Tx for Session.Strict.
+type strictLoader interface {
+	strict() bool
+}
+
+// newTagStoreFor creates a tagStore with the NameMapper, TagName, Strict and
+// Time of runner, which can be nil.
+func newTagStoreFor(runner interface{}) *tagStore {
+	s := newTagStore()
+	if m, ok := runner.(nameMapper); ok {
+		s.nameMapping = m.nameMapper()
+	}
+	if n, ok := runner.(tagNamer); ok {
+		s.tagName = n.tagName()
+	}
+	if l, ok := runner.(strictLoader); ok {
+		s.strict = l.strict()
+	}
+	s.time = timeOptionsOf(runner)
+	return s
+}
+
+// mapName maps a Go name to the column name.
+func (s *tagStore) mapName(name string) string {
+	if s.nameMapping != nil {
+		return s.nameMapping(name)
+	}
+	return NameMapping(name)
+}
+
+// tag returns the struct tag of column names.
+func (s *tagStore) tag() string {
+	if s.tagName != "" {
+		return s.tagName
+	}
+	return defaultTagName
+}
+
 func (s *tagStore) get(t reflect.Type) []string {
 	if t.Kind() != reflect.Struct {
 		return nil
 	}
 	if _, ok := s.m[t]; !ok {
 		l := make([]string, t.NumField())
+		opts := make([]tagOptions, t.NumField())
 		for i := 0; i < t.NumField(); i++ {
 			field := t.Field(i)
 			if field.PkgPath != "" && !field.Anonymous {
 				// unexported
 				continue
 			}
-			tag := field.Tag.Get("db")
+			tag, opt := parseTag(field.Tag.Get(s.tag()))
 			if tag == "-" {
 				// ignore
 				continue
 			}
 			if tag == "" {
 				// no tag, but we can record the field name
-				tag = NameMapping(field.Name)
+				if s.nameMapping != nil {
+					tag = s.nameMapping(field.Name)
+				} else {
+					tag = NameMapping(field.Name)
+				}
 			}
 			l[i] = tag
+			opts[i] = opt
 		}
 		s.m[t] = l
+		s.opts[t] = opts
 	}
 	return s.m[t]
 }
 
+// options returns the tag options of struct fields.
+func (s *tagStore) options(t reflect.Type) []tagOptions {
+	s.get(t)
+	return s.opts[t]
+}
+
 func (s *tagStore) findPtr(value reflect.Value, name []string, ptr []interface{}) error {
-	if value.CanAddr() && value.Addr().Type().Implements(typeScanner) {
+	if value.CanAddr() && value.Addr().Type().Implements(typeScanner) && !embedsScanner(value.Type()) {
 		ptr[0] = value.Addr().Interface()
 		return nil
 	}
 	switch value.Kind() {
 	case reflect.Struct:
+		if value.Type() == typeTime {
+			ptr[0] = value.Addr().Interface()
+			return nil
+		}
+		if value.CanAddr() {
+			// like the code generated by cmd/dbrgen
+			if scanner, ok := value.Addr().Interface().(ColumnsScanner); ok {
+				matchColumns(scanner.Columns(), scanner.ColumnPtrs(), name, ptr)
+				return nil
+			}
+		}
+		if p := s.scanPlan(value.Type(), name); p != nil {
+			p.find(value, ptr)
+			return nil
+		}
 		s.findValueByName(value, name, ptr, true)
 		return nil
 	case reflect.Ptr:
+		if value.CanAddr() && isScalarPtr(value.Type()) {
+			// database/sql sets the pointer to nil for NULL
+			ptr[0] = value.Addr().Interface()
+			return nil
+		}
 		if value.IsNil() {
 			value.Set(reflect.New(value.Type().Elem()))
 		}
@@ -106,9 +306,6 @@ func (s *tagStore) findPtr(value reflect.Value, name []string, ptr []interface{}
 }
 
 func (s *tagStore) findValueByName(value reflect.Value, name []string, ret []interface{}, retPtr bool) {
-	if value.Type().Implements(typeValuer) {
-		return
-	}
 	switch value.Kind() {
 	case reflect.Ptr:
 		if value.IsNil() {
@@ -117,12 +314,29 @@ func (s *tagStore) findValueByName(value reflect.Value, name []string, ret []int
 		s.findValueByName(value.Elem(), name, ret, retPtr)
 	case reflect.Struct:
 		l := s.get(value.Type())
+		opts := s.options(value.Type())
 		for i := 0; i < value.NumField(); i++ {
 			tag := l[i]
 			if tag == "" {
 				continue
 			}
 			fieldValue := value.Field(i)
+			if column, v := fieldColumns(fieldValue, tag, opts[i], retPtr); column != nil {
+				for j, col := range column {
+					for k, want := range name {
+						if want != col || ret[k] != nil {
+							continue
+						}
+						if retPtr {
+							ret[k] = v[j]
+						} else {
+							// keep nil value valid
+							ret[k] = reflect.ValueOf(&v[j]).Elem()
+						}
+					}
+				}
+				continue
+			}
 			for i, want := range name {
 				if want != tag {
 					continue
@@ -135,7 +349,45 @@ func (s *tagStore) findValueByName(value reflect.Value, name []string, ret []int
 					}
 				}
 			}
+			if isValueType(fieldValue.Type()) {
+				continue
+			}
+			if prefix := opts[i].prefix(tag); prefix != "" {
+				s.findPrefixed(fieldValue, prefix, name, ret, retPtr)
+				continue
+			}
 			s.findValueByName(fieldValue, name, ret, retPtr)
 		}
 	}
 }
+
+// matchColumns sets ptr to the scan destinations of the same columns in name.
+func matchColumns(column []string, dest []interface{}, name []string, ptr []interface{}) {
+	for j, col := range column {
+		for k, want := range name {
+			if want == col && ptr[k] == nil {
+				ptr[k] = dest[j]
+			}
+		}
+	}
+}
+
+// findPrefixed finds the columns with prefix in a nested struct field,
+// which is allocated if it is a nil pointer and any column is found.
+func (s *tagStore) findPrefixed(value reflect.Value, prefix string, name []string, ret []interface{}, retPtr bool) {
+	sub := make([]string, len(name))
+	found := false
+	for i, want := range name {
+		if strings.HasPrefix(want, prefix) && ret[i] == nil {
+			sub[i] = want[len(prefix):]
+			found = true
+		}
+	}
+	if !found {
+		return
+	}
+	if retPtr && value.Kind() == reflect.Ptr && value.IsNil() && value.CanSet() {
+		value.Set(reflect.New(value.Type().Elem()))
+	}
+	s.findValueByName(value, sub, ret, retPtr)
+}
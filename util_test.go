@@ -2,9 +2,12 @@ package dbr
 
 import (
 	"reflect"
+	"strings"
 	"testing"
 	"time"
 
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/jiyeyuran/dbr/v2/dialect"
 	"github.com/stretchr/testify/require"
 )
 
@@ -50,6 +53,24 @@ func TestSnakeCase(t *testing.T) {
 	}
 }
 
+func TestNameMappers(t *testing.T) {
+	for _, test := range []struct {
+		in    string
+		camel string
+		lower string
+	}{
+		{in: "", camel: "", lower: ""},
+		{in: "UserID", camel: "userID", lower: "userid"},
+		{in: "ID", camel: "id", lower: "id"},
+		{in: "HTTPServer", camel: "httpServer", lower: "httpserver"},
+		{in: "Float64Val", camel: "float64Val", lower: "float64val"},
+	} {
+		require.Equal(t, test.camel, CamelCase(test.in))
+		require.Equal(t, test.lower, LowerCase(test.in))
+	}
+	require.Equal(t, "user_id", SnakeCase("UserID"))
+}
+
 func BenchmarkCamelCaseToSnakeCase(b *testing.B) {
 	for i := 0; i < b.N; i++ {
 		camelCaseToSnakeCase("getHTTPResponseCode")
@@ -112,3 +133,136 @@ func TestFindValueByName(t *testing.T) {
 		require.Equal(t, test.want, got)
 	}
 }
+
+type planBase struct {
+	ID   int64
+	Name string
+}
+
+type planAuthor struct {
+	Name string
+}
+
+type planNode struct {
+	ID     int64
+	Parent *planNode
+}
+
+func TestScanPlan(t *testing.T) {
+	type record struct {
+		*planBase
+		ID     int64
+		Title  string
+		Author *planAuthor `db:"author,prefix"`
+		Editor planAuthor  `db:"editor,prefix=ed_"`
+		Extra  struct {
+			Title string
+		}
+	}
+	name := []string{"id", "name", "title", "author_name", "ed_name", "missing"}
+
+	s := newTagStore()
+	for i := 0; i < 2; i++ {
+		// the second is cached
+		p := s.scanPlan(reflect.TypeOf(record{}), name)
+		require.NotNil(t, p)
+
+		var v record
+		got := make([]interface{}, len(name))
+		p.find(reflect.ValueOf(&v).Elem(), got)
+		require.NotNil(t, v.Author)
+
+		want := make([]interface{}, len(name))
+		s.findValueByName(reflect.ValueOf(&v).Elem(), name, want, true)
+		for k := range name {
+			require.True(t, want[k] == got[k], name[k])
+		}
+		require.True(t, got[0] == &v.ID)
+		require.True(t, got[2] == &v.Title)
+		require.Nil(t, got[5])
+
+		v = record{planBase: &planBase{}}
+		got = make([]interface{}, len(name))
+		p.find(reflect.ValueOf(&v).Elem(), got)
+		require.True(t, got[0] == &v.planBase.ID)
+		require.True(t, got[1] == &v.planBase.Name)
+	}
+
+	require.Nil(t, s.scanPlan(reflect.TypeOf(planNode{}), []string{"id"}))
+	require.Nil(t, s.scanPlan(reflect.TypeOf(struct{ Money testMoney }{}), []string{"amount"}))
+}
+
+func TestScanPlanMapper(t *testing.T) {
+	type record struct {
+		Title string
+	}
+	mapper := func(prefix string) func(string) string {
+		return func(name string) string {
+			return prefix + strings.ToLower(name)
+		}
+	}
+	name := []string{"a_title", "b_title"}
+	for k, prefix := range []string{"a_", "b_"} {
+		// the closures have the same code but map differently
+		s := newTagStore()
+		s.nameMapping = mapper(prefix)
+		p := s.scanPlan(reflect.TypeOf(record{}), name)
+		require.NotNil(t, p)
+
+		var v record
+		ptr := make([]interface{}, len(name))
+		p.find(reflect.ValueOf(&v).Elem(), ptr)
+		require.True(t, ptr[k] == &v.Title)
+		require.Nil(t, ptr[1-k])
+	}
+}
+
+func TestPlanCache(t *testing.T) {
+	c := newPlanCache(2)
+	key := func(column string) scanPlanKey {
+		return scanPlanKey{typ: reflect.TypeOf(0), column: column}
+	}
+	a := new(scanPlan)
+	c.put(key("a"), a)
+	c.put(key("b"), nil)
+	p, ok := c.get(key("a"))
+	require.True(t, ok)
+	require.True(t, p == a)
+	// b is the least recently used
+	c.put(key("c"), new(scanPlan))
+	require.Equal(t, 2, c.len())
+	_, ok = c.get(key("b"))
+	require.False(t, ok)
+	_, ok = c.get(key("a"))
+	require.True(t, ok)
+}
+
+func TestConnectionNameMapper(t *testing.T) {
+	sess, mock := newMockSession(t, dialect.MySQL)
+	sess.NameMapper = CamelCase
+
+	type user struct {
+		UserID   int64
+		FullName string
+	}
+	mock.ExpectQuery("SELECT \\* FROM `users`").
+		WillReturnRows(sqlmock.NewRows([]string{"userID", "fullName"}).AddRow(1, "alice"))
+	var users []user
+	_, err := sess.Select("*").From("users").Load(&users)
+	require.NoError(t, err)
+	require.Equal(t, []user{{1, "alice"}}, users)
+
+	mock.ExpectBegin()
+	tx, err := sess.Begin()
+	require.NoError(t, err)
+	builder := tx.InsertInto("users").Columns("fullName").Record(&user{FullName: "bob"})
+	query, err := InterpolateForDialect("?", []interface{}{builder}, dialect.MySQL)
+	require.NoError(t, err)
+	require.Equal(t, "INSERT INTO `users` (`fullName`) VALUES ('bob')", query)
+
+	query, err = InterpolateForDialect("?", []interface{}{tx.UpdateRecord("users", &users[0], &user{UserID: 1, FullName: "bob"})}, dialect.MySQL)
+	require.NoError(t, err)
+	require.Equal(t, "UPDATE `users` SET `fullName` = 'bob'", query)
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
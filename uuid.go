@@ -0,0 +1,202 @@
+package dbr
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// UUID is a universally unique identifier in RFC 4122 byte order.
+// It is written as text, like the uuid type of postgres or CHAR(36),
+// and BinaryUUID is written as 16 bytes.
+type UUID [16]byte
+
+// ParseUUID parses s like "6ba7b810-9dad-11d1-80b4-00c04fd430c8",
+// optionally in braces or with "urn:uuid:" prefix, or without hyphens.
+func ParseUUID(s string) (UUID, error) {
+	var u UUID
+	orig := s
+	s = strings.TrimPrefix(s, "urn:uuid:")
+	if strings.HasPrefix(s, "{") && strings.HasSuffix(s, "}") {
+		s = s[1 : len(s)-1]
+	}
+	if len(s) == 36 {
+		if s[8] != '-' || s[13] != '-' || s[18] != '-' || s[23] != '-' {
+			return u, fmt.Errorf("%w: %q", ErrInvalidUUID, orig)
+		}
+		s = s[:8] + s[9:13] + s[14:18] + s[19:23] + s[24:]
+	}
+	if len(s) != 32 {
+		return u, fmt.Errorf("%w: %q", ErrInvalidUUID, orig)
+	}
+	if _, err := hex.Decode(u[:], []byte(s)); err != nil {
+		return u, fmt.Errorf("%w: %q", ErrInvalidUUID, orig)
+	}
+	return u, nil
+}
+
+// String returns the canonical form like "6ba7b810-9dad-11d1-80b4-00c04fd430c8".
+func (u UUID) String() string {
+	var buf [36]byte
+	hex.Encode(buf[0:8], u[0:4])
+	buf[8] = '-'
+	hex.Encode(buf[9:13], u[4:6])
+	buf[13] = '-'
+	hex.Encode(buf[14:18], u[6:8])
+	buf[18] = '-'
+	hex.Encode(buf[19:23], u[8:10])
+	buf[23] = '-'
+	hex.Encode(buf[24:], u[10:])
+	return string(buf[:])
+}
+
+// Scan implements the Scanner interface.
+// The value can be 16 bytes, or text in any form accepted by ParseUUID.
+func (u *UUID) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case []byte:
+		if len(v) == 16 {
+			copy(u[:], v)
+			return nil
+		}
+		return u.Scan(string(v))
+	case string:
+		parsed, err := ParseUUID(v)
+		if err != nil {
+			return err
+		}
+		*u = parsed
+		return nil
+	}
+	return fmt.Errorf("%w: cannot scan %T", ErrInvalidUUID, value)
+}
+
+// Value implements the driver Valuer interface.
+func (u UUID) Value() (driver.Value, error) {
+	return u.String(), nil
+}
+
+// MarshalJSON serializes a UUID to JSON string in the canonical form.
+func (u UUID) MarshalJSON() ([]byte, error) {
+	return json.Marshal(u.String())
+}
+
+// UnmarshalJSON deserializes a UUID from JSON string.
+func (u *UUID) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+	return u.Scan(s)
+}
+
+// BinaryUUID is a UUID written as 16 bytes, like BINARY(16) in mysql or
+// RAW(16) in oracle, which have no native UUID type.
+type BinaryUUID UUID
+
+// String returns the canonical form like "6ba7b810-9dad-11d1-80b4-00c04fd430c8".
+func (u BinaryUUID) String() string {
+	return UUID(u).String()
+}
+
+// Scan implements the Scanner interface like UUID.
+func (u *BinaryUUID) Scan(value interface{}) error {
+	return (*UUID)(u).Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (u BinaryUUID) Value() (driver.Value, error) {
+	return u[:], nil
+}
+
+// MarshalJSON serializes a BinaryUUID to JSON string in the canonical form.
+func (u BinaryUUID) MarshalJSON() ([]byte, error) {
+	return UUID(u).MarshalJSON()
+}
+
+// UnmarshalJSON deserializes a BinaryUUID from JSON string.
+func (u *BinaryUUID) UnmarshalJSON(b []byte) error {
+	return (*UUID)(u).UnmarshalJSON(b)
+}
+
+// NullUUID is a type that can be null or a UUID.
+type NullUUID struct {
+	UUID  UUID
+	Valid bool // Valid is true if UUID is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (n *NullUUID) Scan(value interface{}) error {
+	if value == nil {
+		n.UUID, n.Valid = UUID{}, false
+		return nil
+	}
+	err := n.UUID.Scan(value)
+	n.Valid = err == nil
+	return err
+}
+
+// Value implements the driver Valuer interface.
+func (n NullUUID) Value() (driver.Value, error) {
+	if !n.Valid {
+		return nil, nil
+	}
+	return n.UUID.Value()
+}
+
+// MarshalJSON correctly serializes a NullUUID to JSON.
+func (n NullUUID) MarshalJSON() ([]byte, error) {
+	if n.Valid {
+		return n.UUID.MarshalJSON()
+	}
+	return nullString, nil
+}
+
+// UnmarshalJSON correctly deserializes a NullUUID from JSON.
+func (n *NullUUID) UnmarshalJSON(b []byte) error {
+	if bytes.Equal(b, nullString) {
+		return n.Scan(nil)
+	}
+	err := n.UUID.UnmarshalJSON(b)
+	n.Valid = err == nil
+	return err
+}
+
+// NewNullUUID creates a NullUUID with Scan().
+func NewNullUUID(v interface{}) (n NullUUID) {
+	n.Scan(v)
+	return
+}
+
+// NullUUIDFrom creates a valid NullUUID.
+func NullUUIDFrom(v UUID) NullUUID {
+	return NullUUID{UUID: v, Valid: true}
+}
+
+// NullUUIDFromPtr creates a NullUUID that is NULL if p is nil.
+func NullUUIDFromPtr(p *UUID) (n NullUUID) {
+	if p != nil {
+		n = NullUUIDFrom(*p)
+	}
+	return
+}
+
+// Ptr returns a pointer to the value, or nil if n is NULL.
+func (n NullUUID) Ptr() *UUID {
+	if !n.Valid {
+		return nil
+	}
+	v := n.UUID
+	return &v
+}
+
+// ValueOrZero returns the value, or the zero value if n is NULL.
+func (n NullUUID) ValueOrZero() UUID {
+	if !n.Valid {
+		return UUID{}
+	}
+	return n.UUID
+}
@@ -0,0 +1,95 @@
+package dbr
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/jiyeyuran/dbr/v2/dialect"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseUUID(t *testing.T) {
+	want := UUID{0x6b, 0xa7, 0xb8, 0x10, 0x9d, 0xad, 0x11, 0xd1, 0x80, 0xb4, 0x00, 0xc0, 0x4f, 0xd4, 0x30, 0xc8}
+	for _, in := range []string{
+		"6ba7b810-9dad-11d1-80b4-00c04fd430c8",
+		"6BA7B810-9DAD-11D1-80B4-00C04FD430C8",
+		"{6ba7b810-9dad-11d1-80b4-00c04fd430c8}",
+		"urn:uuid:6ba7b810-9dad-11d1-80b4-00c04fd430c8",
+		"6ba7b8109dad11d180b400c04fd430c8",
+	} {
+		u, err := ParseUUID(in)
+		require.NoError(t, err)
+		require.Equal(t, want, u)
+		require.Equal(t, "6ba7b810-9dad-11d1-80b4-00c04fd430c8", u.String())
+	}
+
+	for _, in := range []string{"", "6ba7b810", "6ba7b810-9dad-11d1-80b4_00c04fd430c8", "xba7b810-9dad-11d1-80b4-00c04fd430c8"} {
+		_, err := ParseUUID(in)
+		require.True(t, errors.Is(err, ErrInvalidUUID), in)
+	}
+}
+
+func TestNullUUID(t *testing.T) {
+	u, err := ParseUUID("6ba7b810-9dad-11d1-80b4-00c04fd430c8")
+	require.NoError(t, err)
+
+	n := NewNullUUID(u[:])
+	require.Equal(t, NullUUID{UUID: u, Valid: true}, n)
+	require.Equal(t, n, NewNullUUID([]byte(u.String())))
+	require.False(t, NewNullUUID(nil).Valid)
+	require.False(t, NewNullUUID(int64(1)).Valid)
+
+	for _, test := range []struct {
+		dialect Dialect
+		query   string
+	}{
+		{
+			dialect: dialect.PostgreSQL,
+			query:   `SELECT * FROM "t" WHERE "id" = '6ba7b810-9dad-11d1-80b4-00c04fd430c8'`,
+		},
+		{
+			dialect: dialect.MySQL,
+			query:   "SELECT * FROM `t` WHERE `id` = '6ba7b810-9dad-11d1-80b4-00c04fd430c8'",
+		},
+	} {
+		query, err := InterpolateForDialect("SELECT * FROM ? WHERE ?", []interface{}{I("t"), Eq("id", n)}, test.dialect)
+		require.NoError(t, err)
+		require.Equal(t, test.query, query)
+	}
+
+	// the same value is written with and without dbr
+	value, err := n.Value()
+	require.NoError(t, err)
+	require.Equal(t, u.String(), value)
+
+	bin := BinaryUUID(u)
+	value, err = bin.Value()
+	require.NoError(t, err)
+	require.Equal(t, u[:], value)
+	query, err := InterpolateForDialect("SELECT * FROM ? WHERE ?", []interface{}{I("t"), Eq("id", bin)}, dialect.MySQL)
+	require.NoError(t, err)
+	require.Equal(t, "SELECT * FROM `t` WHERE `id` = 0x6ba7b8109dad11d180b400c04fd430c8", query)
+	var scanned BinaryUUID
+	require.NoError(t, scanned.Scan(u[:]))
+	require.Equal(t, bin, scanned)
+	require.Equal(t, u.String(), scanned.String())
+
+	query, err = InterpolateForDialect("?", []interface{}{NullUUID{}}, dialect.MySQL)
+	require.NoError(t, err)
+	require.Equal(t, "NULL", query)
+
+	var out struct {
+		ID     NullUUID `json:"id"`
+		Parent NullUUID `json:"parent"`
+	}
+	in := `{"id":"6ba7b810-9dad-11d1-80b4-00c04fd430c8","parent":null}`
+	err = json.Unmarshal([]byte(in), &out)
+	require.NoError(t, err)
+	require.Equal(t, n, out.ID)
+	require.False(t, out.Parent.Valid)
+
+	b, err := json.Marshal(out)
+	require.NoError(t, err)
+	require.Equal(t, in, string(b))
+}
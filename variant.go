@@ -0,0 +1,26 @@
+package dbr
+
+import (
+	"encoding/json"
+
+	"github.com/jiyeyuran/dbr/v2/dialect"
+)
+
+// Variant encodes value as JSON for VARIANT columns in snowflake.
+// It builds `PARSE_JSON('...')`.
+func Variant(value interface{}) Builder {
+	return BuildFunc(func(d Dialect, buf Buffer) error {
+		if d != dialect.Snowflake {
+			return errDialectNotSupported("VARIANT")
+		}
+		b, err := json.Marshal(value)
+		if err != nil {
+			return err
+		}
+		buf.WriteString("PARSE_JSON(")
+		buf.WriteString(placeholder)
+		buf.WriteString(")")
+		buf.WriteValue(string(b))
+		return nil
+	})
+}
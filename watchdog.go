@@ -0,0 +1,184 @@
+package dbr
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/jiyeyuran/dbr/v2/dialect"
+)
+
+// Watchdog watches the queries running longer than Threshold, which sends
+// the event "dbr.watchdog" with the query to the EventReceiver, and stops
+// the query if Cancel or Kill is set.
+//
+// Exec and the Load methods are watched. Iterate and Rows are not,
+// since the rows are read by the caller.
+type Watchdog struct {
+	// Threshold enables the watchdog if it is positive.
+	Threshold time.Duration
+	// Cancel cancels the context of the query.
+	Cancel bool
+	// Kill stops the query on the server with another connection,
+	// by KILL QUERY in mysql and pg_cancel_backend in postgres.
+	// The query is marked with a unique comment to be found, so it skips
+	// the cache of EnableStmtCache.
+	Kill bool
+}
+
+// watchdoger is implemented by Session and Tx for Session.Watchdog.
+type watchdoger interface {
+	watchdog() *Watchdog
+}
+
+// watchdogOf returns the Watchdog of runner, or nil if it is disabled.
+func watchdogOf(runner interface{}) *Watchdog {
+	if w, ok := runner.(watchdoger); ok {
+		return w.watchdog()
+	}
+	return nil
+}
+
+// watch is the in-flight query of a Watchdog.
+type watch struct {
+	// id marks the query for Kill.
+	id string
+
+	mu    sync.Mutex
+	query string
+	// db runs the query, which is a replica for the reads of ReplicaPool.
+	db *sql.DB
+}
+
+type watchKey struct{}
+
+// startWatch starts the watchdog of runner for the query run with the
+// returned context, and returns the func to stop it.
+func startWatch(ctx context.Context, runner runner, log EventReceiver, d Dialect) (context.Context, func()) {
+	wd := watchdogOf(runner)
+	if wd == nil {
+		return ctx, func() {}
+	}
+	w := &watch{db: dbOf(runner)}
+	kill := wd.Kill && w.db != nil && dialect.CapabilitiesOf(d).KillStyle != dialect.NoKill
+	if kill {
+		var b [8]byte
+		rand.Read(b[:])
+		w.id = hex.EncodeToString(b[:])
+		ctx = skipStmtCache(ctx)
+	}
+	ctx = context.WithValue(ctx, watchKey{}, w)
+	cancel := func() {}
+	if wd.Cancel {
+		ctx, cancel = context.WithCancel(ctx)
+	}
+
+	timer := time.AfterFunc(wd.Threshold, func() {
+		w.mu.Lock()
+		query, db := w.query, w.db
+		w.mu.Unlock()
+		log.EventKv("dbr.watchdog", kvs{
+			"sql":       query,
+			"threshold": wd.Threshold.String(),
+		})
+		if wd.Cancel {
+			cancel()
+		}
+		if kill {
+			err := killQuery(db, d, w.id)
+			if err != nil {
+				log.EventErrKv("dbr.watchdog.kill", err, kvs{
+					"sql": query,
+				})
+			}
+		}
+	})
+	return ctx, func() {
+		timer.Stop()
+		cancel()
+	}
+}
+
+// watchQuery records query for the watchdog in ctx, and marks it for Kill.
+func watchQuery(ctx context.Context, query string) string {
+	w, ok := ctx.Value(watchKey{}).(*watch)
+	if !ok {
+		return query
+	}
+	if w.id != "" {
+		query = openingSQLComment + " dbr:watch=" + w.id + space + closingSQLComment + space + query
+	}
+	w.mu.Lock()
+	w.query = query
+	w.mu.Unlock()
+	return query
+}
+
+// watchOn records that the query of the watchdog in ctx runs on db.
+func watchOn(ctx context.Context, db *sql.DB) {
+	w, ok := ctx.Value(watchKey{}).(*watch)
+	if !ok {
+		return
+	}
+	w.mu.Lock()
+	w.db = db
+	w.mu.Unlock()
+}
+
+// killQuery stops the query marked with id.
+func killQuery(db *sql.DB, d Dialect, id string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	pattern := "%dbr:watch=" + id + "%"
+	if dialect.CapabilitiesOf(d).KillStyle == dialect.CancelBackend {
+		_, err := db.ExecContext(ctx, "SELECT pg_cancel_backend(pid) FROM pg_stat_activity WHERE query LIKE $1 AND pid <> pg_backend_pid()", pattern)
+		return err
+	}
+	rows, err := db.QueryContext(ctx, "SELECT ID FROM information_schema.PROCESSLIST WHERE INFO LIKE ? AND ID <> CONNECTION_ID()", pattern)
+	if err != nil {
+		return err
+	}
+	var ids []int64
+	for rows.Next() {
+		var id int64
+		err := rows.Scan(&id)
+		if err != nil {
+			rows.Close()
+			return err
+		}
+		ids = append(ids, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	for _, id := range ids {
+		// KILL does not support placeholders
+		_, err := db.ExecContext(ctx, "KILL QUERY "+strconv.FormatInt(id, 10))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// dbOf returns the database of runner, or nil if it is unknown.
+func dbOf(runner interface{}) *sql.DB {
+	switch runner := runner.(type) {
+	case *Session:
+		return runner.DB
+	case *replicaRunner:
+		// the replica is recorded by watchOn
+		return runner.DB
+	case *Tx:
+		return runner.db
+	case *TwoPhaseTx:
+		return runner.sess.DB
+	}
+	return nil
+}
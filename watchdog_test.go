@@ -0,0 +1,79 @@
+package dbr
+
+import (
+	"regexp"
+	"sync"
+	"testing"
+	"time"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/jiyeyuran/dbr/v2/dialect"
+	"github.com/stretchr/testify/require"
+)
+
+type testWatchdogReceiver struct {
+	NullEventReceiver
+	mu     sync.Mutex
+	events []map[string]string
+}
+
+func (r *testWatchdogReceiver) EventKv(eventName string, kvs map[string]string) {
+	if eventName != "dbr.watchdog" {
+		return
+	}
+	r.mu.Lock()
+	r.events = append(r.events, kvs)
+	r.mu.Unlock()
+}
+
+func (r *testWatchdogReceiver) count() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.events)
+}
+
+func TestWatchdog(t *testing.T) {
+	log := &testWatchdogReceiver{}
+	sess, mock := newMockSession(t, dialect.MySQL)
+	sess.EventReceiver = log
+	sess.Watchdog = Watchdog{Threshold: 10 * time.Millisecond, Cancel: true}
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT `id` FROM `suggestions`")).
+		WillDelayFor(time.Second).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	start := time.Now()
+	var ids []int64
+	_, err := sess.Select("id").From("suggestions").Load(&ids)
+	require.Error(t, err)
+	require.True(t, time.Since(start) < time.Second)
+	require.Equal(t, 1, log.count())
+	require.Equal(t, "SELECT `id` FROM `suggestions`", log.events[0]["sql"])
+
+	// fast queries are not reported
+	sess, mock = newMockSession(t, dialect.MySQL)
+	sess.EventReceiver = log
+	sess.Watchdog = Watchdog{Threshold: time.Second, Kill: true}
+	watched := `^/\* dbr:watch=[0-9a-f]{16} \*/ DELETE FROM ` + "`suggestions`$"
+	mock.ExpectExec(watched).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	_, err = sess.DeleteFrom("suggestions").Exec()
+	require.NoError(t, err)
+	require.Equal(t, 1, log.count())
+	require.NoError(t, mock.ExpectationsWereMet())
+
+	// Kill stops the marked query with another connection
+	sess.Watchdog.Threshold = 10 * time.Millisecond
+	mock.ExpectExec(watched).
+		WillDelayFor(200 * time.Millisecond).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT ID FROM information_schema.PROCESSLIST WHERE INFO LIKE ? AND ID <> CONNECTION_ID()")).
+		WillReturnRows(sqlmock.NewRows([]string{"ID"}).AddRow(42))
+	mock.ExpectExec(regexp.QuoteMeta("KILL QUERY 42")).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	_, err = sess.DeleteFrom("suggestions").Exec()
+	require.NoError(t, err)
+	require.Equal(t, 2, log.count())
+	require.Eventually(t, func() bool {
+		return mock.ExpectationsWereMet() == nil
+	}, time.Second, 10*time.Millisecond)
+}
@@ -0,0 +1,63 @@
+package dbr
+
+import "github.com/jiyeyuran/dbr/v2/dialect"
+
+// cte is a common table expression in `WITH ...`.
+type cte struct {
+	name      string
+	column    []string
+	builder   Builder
+	recursive bool
+}
+
+func (c *cte) Build(d Dialect, buf Buffer) error {
+	buf.WriteString(d.QuoteIdent(c.name))
+	if len(c.column) > 0 {
+		buf.WriteString(" (")
+		for i, col := range c.column {
+			if i > 0 {
+				buf.WriteString(", ")
+			}
+			buf.WriteString(d.QuoteIdent(col))
+		}
+		buf.WriteString(")")
+	}
+	buf.WriteString(" AS (")
+	err := c.builder.Build(d, buf)
+	if err != nil {
+		return err
+	}
+	buf.WriteString(")")
+	return nil
+}
+
+func buildWith(d Dialect, buf Buffer, ctes []*cte) error {
+	if len(ctes) == 0 {
+		return nil
+	}
+	caps := dialect.CapabilitiesOf(d)
+	if !caps.SupportsCTE {
+		return errDialectNotSupported("WITH")
+	}
+	buf.WriteString("WITH ")
+	// mssql and oracle do not have the keyword, and recursion is implicit
+	if caps.SupportsRecursiveKeyword {
+		for _, c := range ctes {
+			if c.recursive {
+				buf.WriteString("RECURSIVE ")
+				break
+			}
+		}
+	}
+	for i, c := range ctes {
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+		err := c.Build(d, buf)
+		if err != nil {
+			return err
+		}
+	}
+	buf.WriteString(" ")
+	return nil
+}